@@ -0,0 +1,93 @@
+// Package govar provides a powerful and highly configurable pretty-printer for Go
+// data structures. This file detects slices that share a backing array: when
+// dumped slices overlap the same memory at different offsets, all but the one
+// starting first are annotated with the ID of that one and their offset in it,
+// since aliased slices are a common source of subtle bugs.
+package govar
+
+import (
+	"reflect"
+	"sort"
+	"strconv"
+)
+
+// sliceSpan is the memory a slice encountered during the analysis can reach:
+// its backing array from its first element up to its capacity.
+type sliceSpan struct {
+	key        canonicalKey // Raw key of the slice, as used for reference tracking.
+	start, end uintptr
+	elemType   reflect.Type
+	order      int // Position in the pre-scan.
+}
+
+// sharedArray records that a slice shares its backing array with the slice
+// identified by owner, starting offset elements after it.
+type sharedArray struct {
+	owner  canonicalKey
+	offset int
+}
+
+// recordSliceSpan remembers the span of v if it is a non-empty slice.
+func (d *Dumper) recordSliceSpan(v reflect.Value, rawKey canonicalKey, order int) {
+	v = deref(v)
+	if v.Kind() != reflect.Slice || v.IsNil() || v.Cap() == 0 || v.Type().Elem().Size() == 0 {
+		return
+	}
+	start := v.Pointer()
+	end := start + uintptr(v.Cap())*v.Type().Elem().Size()
+	d.sliceSpans = append(d.sliceSpans, sliceSpan{key: rawKey, start: start, end: end, elemType: v.Type().Elem(), order: order})
+}
+
+// detectSharedArrays is an analysis pass run after IDs were assigned. It groups
+// the recorded slices whose spans overlap and whose elements have the same
+// type. The slice starting first in each group gets an ID, if it has none, and
+// the others are recorded in sharedArrays with their offset from it.
+func (d *Dumper) detectSharedArrays() {
+	byElemType := make(map[reflect.Type][]sliceSpan)
+	for _, span := range d.sliceSpans {
+		byElemType[span.elemType] = append(byElemType[span.elemType], span)
+	}
+	for _, spans := range byElemType {
+		sort.Slice(spans, func(i, j int) bool {
+			if spans[i].start != spans[j].start {
+				return spans[i].start < spans[j].start
+			}
+			return spans[i].order < spans[j].order
+		})
+		owner, groupEnd := spans[0], spans[0].end
+		for _, span := range spans[1:] {
+			if span.start >= groupEnd {
+				owner, groupEnd = span, span.end
+				continue
+			}
+			groupEnd = max(groupEnd, span.end)
+			ownerRoot, root := d.findRoot(owner.key), d.findRoot(span.key)
+			if root == ownerRoot {
+				continue // The same slice, reached twice.
+			}
+			if _, hasID := d.referenceIDs[ownerRoot]; !hasID {
+				d.referenceIDs[ownerRoot] = "&" + strconv.Itoa(len(d.referenceIDs)+1)
+			}
+			offset := int((span.start - owner.start) / span.elemType.Size())
+			d.sharedArrays[root] = sharedArray{owner: ownerRoot, offset: offset}
+		}
+	}
+}
+
+// sharedArrayNote returns the annotation of a slice sharing its backing array
+// with another dumped slice (e.g. "shares array with &2 [offset 3] "), or "".
+func (d *Dumper) sharedArrayNote(v reflect.Value) string {
+	if len(d.sharedArrays) == 0 || v.Kind() != reflect.Slice {
+		return ""
+	}
+	rawKey, ok := d.getRawKey(v)
+	if !ok {
+		return ""
+	}
+	shared, ok := d.sharedArrays[d.findRoot(rawKey)]
+	if !ok {
+		return ""
+	}
+	note := "shares array with " + d.referenceIDs[shared.owner] + " [offset " + strconv.Itoa(shared.offset) + "]"
+	return d.ApplyFormat(ColorPink, note) + " "
+}
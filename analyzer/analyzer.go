@@ -0,0 +1,133 @@
+// Package analyzer provides a go/analysis analyzer reporting govar dump calls
+// (Dump, Sdump, Die, …) left in non-test code, so debugging output does not
+// ship to production by accident. Run it with go vet:
+//
+//	go install github.com/janvaclavik/govar/analyzer/cmd/govarvet@latest
+//	go vet -vettool=$(which govarvet) ./...
+//
+// or add Analyzer to a multichecker or golangci-lint plugin.
+package analyzer
+
+import (
+	"go/ast"
+	"go/types"
+	"path"
+	"slices"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+)
+
+// govarModule is the import path prefix of the packages whose calls are flagged.
+const govarModule = "github.com/janvaclavik/govar"
+
+// dumpPrefixes are the name prefixes of the functions and methods writing or
+// returning dumps: Dump, DumpValues, Edump, Fdump, Sdump, SdumpHTML, Die,
+// Watch, WatchTo, Trace, Count, CountSummary, Printf, Fprintf, Sprintf, ….
+var dumpPrefixes = []string{"Dump", "Edump", "Fdump", "Sdump", "Die", "Watch", "Trace", "Count", "Printf", "Fprintf", "Sprintf"}
+
+// Analyzer reports calls of govar dump functions and Dumper methods outside
+// _test.go files, except in allowlisted packages or of allowlisted functions.
+var Analyzer = &analysis.Analyzer{
+	Name:     "govardump",
+	Doc:      "report govar dump calls (Dump, Sdump, Die, …) left outside of tests",
+	URL:      "https://pkg.go.dev/github.com/janvaclavik/govar/analyzer",
+	Run:      run,
+	Requires: []*analysis.Analyzer{inspect.Analyzer},
+}
+
+var (
+	allowPkgs  string // Comma-separated path.Match patterns of packages allowed to dump.
+	allowFuncs string // Comma-separated names of functions allowed to be called.
+)
+
+func init() {
+	Analyzer.Flags.StringVar(&allowPkgs, "allowpkgs", "", "comma-separated package path patterns (path.Match syntax, or a path ending in /...) where dump calls are allowed")
+	Analyzer.Flags.StringVar(&allowFuncs, "allowfuncs", "", "comma-separated govar function or method names that are allowed, e.g. Sdump,SdumpHTML")
+}
+
+func run(pass *analysis.Pass) (any, error) {
+	if packageAllowed(pass.Pkg.Path()) {
+		return nil, nil
+	}
+	allowed := splitList(allowFuncs)
+
+	insp := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+	insp.Preorder([]ast.Node{(*ast.CallExpr)(nil)}, func(n ast.Node) {
+		call := n.(*ast.CallExpr)
+		fn := calledFunc(pass.TypesInfo, call)
+		if fn == nil || !isDumpFunc(fn) || slices.Contains(allowed, fn.Name()) {
+			return
+		}
+		if strings.HasSuffix(pass.Fset.File(call.Pos()).Name(), "_test.go") {
+			return
+		}
+		pass.Reportf(call.Pos(), "leftover govar dump call: %s.%s", path.Base(fn.Pkg().Path()), fn.Name())
+	})
+	return nil, nil
+}
+
+// calledFunc returns the function or method called by call, or nil if it calls
+// something else (a function value, a conversion, a builtin).
+func calledFunc(info *types.Info, call *ast.CallExpr) *types.Func {
+	fun := ast.Unparen(call.Fun)
+	switch index := fun.(type) { // Explicitly instantiated generic functions.
+	case *ast.IndexExpr:
+		fun = index.X
+	case *ast.IndexListExpr:
+		fun = index.X
+	}
+	var ident *ast.Ident
+	switch fun := fun.(type) {
+	case *ast.Ident:
+		ident = fun
+	case *ast.SelectorExpr:
+		ident = fun.Sel
+	default:
+		return nil
+	}
+	fn, _ := info.Uses[ident].(*types.Func)
+	return fn
+}
+
+// isDumpFunc reports whether fn is a dump function or method of a govar package.
+func isDumpFunc(fn *types.Func) bool {
+	if fn.Pkg() == nil {
+		return false
+	}
+	if pkgPath := fn.Pkg().Path(); pkgPath != govarModule && !strings.HasPrefix(pkgPath, govarModule+"/") {
+		return false
+	}
+	for _, prefix := range dumpPrefixes {
+		if strings.HasPrefix(fn.Name(), prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// packageAllowed reports whether pkgPath matches one of the allowpkgs patterns.
+func packageAllowed(pkgPath string) bool {
+	for _, pattern := range splitList(allowPkgs) {
+		if prefix, ok := strings.CutSuffix(pattern, "/..."); ok && (pkgPath == prefix || strings.HasPrefix(pkgPath, prefix+"/")) {
+			return true
+		}
+		if ok, _ := path.Match(pattern, pkgPath); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// splitList splits a comma-separated flag value, dropping empty entries.
+func splitList(list string) []string {
+	var items []string
+	for _, item := range strings.Split(list, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
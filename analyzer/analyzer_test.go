@@ -0,0 +1,25 @@
+package analyzer
+
+import (
+	"testing"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+)
+
+func TestAnalyzer(t *testing.T) {
+	analysistest.Run(t, analysistest.TestData(), Analyzer, "a")
+}
+
+func TestAnalyzerAllowlist(t *testing.T) {
+	defer func(pkgs, funcs string) { allowPkgs, allowFuncs = pkgs, funcs }(allowPkgs, allowFuncs)
+	allowPkgs, allowFuncs = "tools/...", "Sdump,SdumpHTML"
+
+	// No diagnostics are expected from the allowlisted package, nor for Sdump.
+	analysistest.Run(t, analysistest.TestData(), Analyzer, "tools/debug", "b")
+
+	for pkgPath, want := range map[string]bool{"tools": true, "tools/debug/v2": true, "toolsx": false, "shop/tools": false} {
+		if got := packageAllowed(pkgPath); got != want {
+			t.Errorf("packageAllowed(%q) = %v, want %v", pkgPath, got, want)
+		}
+	}
+}
@@ -0,0 +1,15 @@
+// Command govarvet reports govar dump calls left outside of tests. It can run
+// standalone or as a go vet tool:
+//
+//	govarvet ./...
+//	go vet -vettool=$(which govarvet) ./...
+package main
+
+import (
+	"github.com/janvaclavik/govar/analyzer"
+	"golang.org/x/tools/go/analysis/singlechecker"
+)
+
+func main() {
+	singlechecker.Main(analyzer.Analyzer)
+}
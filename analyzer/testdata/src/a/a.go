@@ -0,0 +1,32 @@
+package a
+
+import (
+	"context"
+	"os"
+	"time"
+
+	"github.com/janvaclavik/govar"
+)
+
+func handler(order any) string {
+	govar.Dump(order)             // want `leftover govar dump call: govar.Dump`
+	govar.Fdump(os.Stderr, order) // want `leftover govar dump call: govar.Fdump`
+	govar.DumpTypeOf[int]()       // want `leftover govar dump call: govar.DumpTypeOf`
+	d := govar.NewDumper()
+	d.Dump(order) // want `leftover govar dump call: govar.Dump`
+	d.Tree(order)
+	govar.Watch(context.Background(), &order, time.Second) // want `leftover govar dump call: govar.Watch`
+	govar.Trace("load", order)                             // want `leftover govar dump call: govar.Trace`
+	govar.Count("handler")                                 // want `leftover govar dump call: govar.Count`
+	govar.CountSummary()                                   // want `leftover govar dump call: govar.CountSummary`
+	govar.Printf("order: %v\n", order)                     // want `leftover govar dump call: govar.Printf`
+	govar.Fprintf(os.Stderr, "order: %v\n", order)         // want `leftover govar dump call: govar.Fprintf`
+	_ = govar.Sprintf("order: %v", order)                  // want `leftover govar dump call: govar.Sprintf`
+	govar.Configure(2)
+	dump := govar.Dump
+	dump(order)
+	if order == nil {
+		govar.Die(order) // want `leftover govar dump call: govar.Die`
+	}
+	return govar.SdumpHTML(order) // want `leftover govar dump call: govar.SdumpHTML`
+}
@@ -0,0 +1,11 @@
+package a
+
+import (
+	"testing"
+
+	"github.com/janvaclavik/govar"
+)
+
+func TestHandler(t *testing.T) {
+	govar.Dump(handler(1))
+}
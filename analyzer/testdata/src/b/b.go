@@ -0,0 +1,8 @@
+package b
+
+import "github.com/janvaclavik/govar"
+
+func render(v any) string {
+	govar.Dump(v) // want `leftover govar dump call: govar.Dump`
+	return govar.Sdump(v)
+}
@@ -0,0 +1,29 @@
+package govar
+
+import (
+	"context"
+	"io"
+	"time"
+)
+
+type Dumper struct{}
+
+func NewDumper() *Dumper { return &Dumper{} }
+
+func (d *Dumper) Dump(values ...any)                             {}
+func (d *Dumper) Sdump(values ...any) string                     { return "" }
+func (d *Dumper) Tree(values ...any)                             {}
+func Dump(values ...any)                                         {}
+func Die(values ...any)                                          {}
+func Fdump(w io.Writer, values ...any)                           {}
+func Sdump(values ...any) string                                 { return "" }
+func SdumpHTML(values ...any) string                             { return "" }
+func DumpTypeOf[T any]()                                         {}
+func Watch(ctx context.Context, ptr any, interval time.Duration) {}
+func Trace(label string, values ...any)                          {}
+func Count(label string)                                         {}
+func CountSummary()                                              {}
+func Printf(format string, args ...any)                          {}
+func Fprintf(w io.Writer, format string, args ...any)            {}
+func Sprintf(format string, args ...any) string                  { return "" }
+func Configure(indent int)                                       {}
@@ -0,0 +1,7 @@
+package debug
+
+import "github.com/janvaclavik/govar"
+
+func Inspect(v any) {
+	govar.Dump(v)
+}
@@ -0,0 +1,74 @@
+// Package govar provides a powerful and highly configurable pretty-printer for Go
+// data structures. This file implements the anonymization mode, which hides the
+// contents of strings and numbers while preserving structure, types, lengths,
+// and references, so production dumps can be shared in bug reports.
+package govar
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"unicode/utf8"
+)
+
+// AnonymizeMode selects how DumperConfig.Anonymize hides dumped values.
+type AnonymizeMode int
+
+const (
+	AnonymizeOff  AnonymizeMode = iota // Values are rendered as they are.
+	AnonymizeMask                      // Strings become "xxxx", digits become '#' (same lengths).
+	AnonymizeHash                      // Strings and digits are replaced by deterministic hashes (same lengths), so equal values stay equal.
+)
+
+// anonymizeText hides the contents of a string, keeping its rune count.
+func (d *Dumper) anonymizeText(s string) string {
+	n := utf8.RuneCountInString(s)
+	switch d.config.Anonymize {
+	case AnonymizeMask:
+		return strings.Repeat("x", n)
+	case AnonymizeHash:
+		return stretchHash(s, n, hexDigits)
+	}
+	return s
+}
+
+// anonymizeNumber blurs the digits of a formatted number, keeping its sign,
+// decimal point, exponent, and length.
+func (d *Dumper) anonymizeNumber(s string) string {
+	if d.config.Anonymize == AnonymizeOff {
+		return s
+	}
+	digits := strings.Repeat("#", len(s))
+	if d.config.Anonymize == AnonymizeHash {
+		digits = stretchHash(s, len(s), decimalDigits)
+	}
+	out := []byte(s)
+	for i := range out {
+		if out[i] >= '0' && out[i] <= '9' {
+			out[i] = digits[i]
+		}
+	}
+	return string(out)
+}
+
+const (
+	hexDigits     = "0123456789abcdef"
+	decimalDigits = "0123456789"
+)
+
+// stretchHash derives n characters from the alphabet out of the SHA-256 of s,
+// re-hashing as needed for long inputs.
+func stretchHash(s string, n int, alphabet string) string {
+	sb := &strings.Builder{}
+	sum := sha256.Sum256([]byte(s))
+	for sb.Len() < n {
+		for _, b := range sum {
+			if sb.Len() == n {
+				break
+			}
+			sb.WriteByte(alphabet[int(b)%len(alphabet)])
+		}
+		sum = sha256.Sum256([]byte(hex.EncodeToString(sum[:])))
+	}
+	return sb.String()
+}
@@ -4,22 +4,32 @@
 // sensible default configurations.
 package govar
 
-import "io"
+import (
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"reflect"
+	"runtime/debug"
+)
 
 // DefaultConfig provides a standard, full-featured dumper configuration.
 // It enables types, metadata, colors, reference tracking, and method embedding.
+// SetDefault replaces it for the package-level functions.
 var DefaultConfig = DumperConfig{
 	IndentWidth:         3,
 	MaxDepth:            15,
 	MaxItems:            150,
 	MaxStringLen:        10000,
 	MaxInlineLength:     80,
+	MaxPaddingWidth:     40,
 	ShowTypes:           true,
 	UseColors:           true,
 	TrackReferences:     true,
 	EmbedTypeMethods:    true,
 	ShowMetaInformation: true,
 	ShowHexdump:         true,
+	MatrixGrid:          true,
 	IgnoreStringer:      false,
 	HTMLtagToken:        "span",
 	HTMLtagSection:      "pre",
@@ -33,12 +43,102 @@ var SimpleConfig = DumperConfig{
 	MaxItems:            150,
 	MaxStringLen:        10000,
 	MaxInlineLength:     80,
+	MaxPaddingWidth:     40,
 	ShowTypes:           false,
 	UseColors:           true,
 	TrackReferences:     true,
 	EmbedTypeMethods:    false,
 	ShowMetaInformation: false,
 	ShowHexdump:         true,
+	MatrixGrid:          true,
+	IgnoreStringer:      false,
+	HTMLtagToken:        "span",
+	HTMLtagSection:      "pre",
+}
+
+// JSONLikeConfig provides a dumper configuration that renders values as
+// JSON5-like literals (`key: value,` with braces for maps and structs),
+// omitting types and metadata for output familiar to users of other ecosystems.
+var JSONLikeConfig = DumperConfig{
+	IndentWidth:         3,
+	MaxDepth:            15,
+	MaxItems:            150,
+	MaxStringLen:        10000,
+	MaxInlineLength:     80,
+	MaxPaddingWidth:     40,
+	ShowTypes:           false,
+	UseColors:           true,
+	TrackReferences:     true,
+	EmbedTypeMethods:    false,
+	ShowMetaInformation: false,
+	ShowHexdump:         false,
+	IgnoreStringer:      false,
+	JSONLikeLayout:      true,
+	HTMLtagToken:        "span",
+	HTMLtagSection:      "pre",
+}
+
+// TestConfig provides a dumper configuration for golden files and test
+// assertions: colorless and deterministic (stable labels instead of addresses),
+// without method lists.
+var TestConfig = DumperConfig{
+	IndentWidth:         3,
+	MaxDepth:            15,
+	MaxItems:            150,
+	MaxStringLen:        10000,
+	MaxInlineLength:     80,
+	MaxPaddingWidth:     40,
+	ShowTypes:           true,
+	UseColors:           false,
+	TrackReferences:     true,
+	EmbedTypeMethods:    false,
+	ShowMetaInformation: true,
+	ShowHexdump:         true,
+	MatrixGrid:          true,
+	IgnoreStringer:      false,
+	Deterministic:       true,
+	HTMLtagToken:        "span",
+	HTMLtagSection:      "pre",
+}
+
+// LogConfig provides a compact dumper configuration for log lines: every value
+// is rendered colorless on a single line, without types or metadata.
+var LogConfig = DumperConfig{
+	IndentWidth:         3,
+	MaxDepth:            15,
+	MaxItems:            150,
+	MaxStringLen:        10000,
+	MaxInlineLength:     80,
+	MaxPaddingWidth:     40,
+	SingleLine:          true,
+	ShowTypes:           false,
+	UseColors:           false,
+	TrackReferences:     true,
+	EmbedTypeMethods:    false,
+	ShowMetaInformation: false,
+	ShowHexdump:         false,
+	IgnoreStringer:      false,
+	HTMLtagToken:        "span",
+	HTMLtagSection:      "pre",
+}
+
+// DeepConfig provides a full-featured dumper configuration like DefaultConfig
+// that never truncates: nesting depth, collection sizes and string lengths are
+// unlimited. Beware of dumping very large values with it.
+var DeepConfig = DumperConfig{
+	IndentWidth:         3,
+	MaxDepth:            math.MaxInt,
+	MaxItems:            math.MaxInt,
+	MaxStringLen:        math.MaxInt,
+	MaxInlineLength:     80,
+	MaxPaddingWidth:     40,
+	ShowTypes:           true,
+	UseColors:           true,
+	TrackReferences:     true,
+	EmbedTypeMethods:    true,
+	ShowMetaInformation: true,
+	ShowHexdump:         true,
+	MatrixGrid:          true,
 	IgnoreStringer:      false,
 	HTMLtagToken:        "span",
 	HTMLtagSection:      "pre",
@@ -47,20 +147,39 @@ var SimpleConfig = DumperConfig{
 // Die dumps the provided values using the DefaultConfig and terminates the program
 // with os.Exit(1). It is a convenient shortcut for `govar.Dump(...)` followed by an exit.
 func Die(values ...any) {
-	d := NewDumper(DefaultConfig)
+	d := NewDumper(defaultConfig())
+	if !Enabled() {
+		os.Exit(d.dieExitCode())
+	}
 	d.Die(values...)
 }
 
+// DieOnPanic is meant to be deferred, typically at the top of main or of a
+// goroutine: `defer govar.DieOnPanic()`. If a panic occurs, it dumps the
+// recovered value and the stack trace using the DefaultConfig and exits with status 1.
+func DieOnPanic() {
+	// recover only works when called directly by the deferred function.
+	if r := recover(); r != nil {
+		NewDumper(defaultConfig()).dieWithPanic(r, debug.Stack())
+	}
+}
+
 // Dump prints the given values to stdout using the DefaultConfig.
 // It provides a rich, colored output with full type and metadata information.
 func Dump(values ...any) {
-	d := NewDumper(DefaultConfig)
+	if !Enabled() {
+		return
+	}
+	d := NewDumper(defaultConfig())
 	d.Dump(values...)
 }
 
 // DumpNoColors prints the given values to stdout with full formatting, but with colors disabled.
 func DumpNoColors(values ...any) {
-	cfg := DefaultConfig
+	if !Enabled() {
+		return
+	}
+	cfg := defaultConfig()
 	cfg.UseColors = false
 	d := NewDumper(cfg)
 	d.Dump(values...)
@@ -69,21 +188,180 @@ func DumpNoColors(values ...any) {
 // DumpValues prints the values to stdout using the SimpleConfig.
 // This produces a more compact output, omitting types, metadata, and methods.
 func DumpValues(values ...any) {
+	if !Enabled() {
+		return
+	}
 	d := NewDumper(SimpleConfig)
 	d.Dump(values...)
 }
 
+// DumpType prints the type structure of the given values (fields, types, struct
+// tags, and methods, but no values) to stdout using the DefaultConfig.
+func DumpType(values ...any) {
+	if !Enabled() {
+		return
+	}
+	d := NewDumper(defaultConfig())
+	d.DumpType(values...)
+}
+
+// DumpTypeOf prints the structure of type T to stdout using the DefaultConfig.
+// Unlike DumpType, it needs no value, so it also works for interface types:
+// `govar.DumpTypeOf[io.ReadWriter]()`.
+func DumpTypeOf[T any]() {
+	if !Enabled() {
+		return
+	}
+	d := NewDumper(defaultConfig())
+	fmt.Fprintln(stdoutWriter(), d.sdumpTypes(d.config.UseColors, reflect.TypeFor[T]()))
+}
+
+// Trace prints a label with the time elapsed since the previous Trace call on
+// the same goroutine, followed by the values, to stdout using the DefaultConfig:
+//
+//	govar.Trace("parsed", req) // ⏱ parsed +3.2ms
+func Trace(label string, values ...any) {
+	if !Enabled() {
+		return
+	}
+	d := NewDumper(defaultConfig())
+	d.Trace(label, values...)
+}
+
+// Count counts an execution of the code path it is called from, identified by
+// the label and the call site, and prints the count to stdout on the first hit
+// and then at most once per second: `govar.Count("cache miss")`.
+func Count(label string) {
+	if !Enabled() {
+		return
+	}
+	d := NewDumper(defaultConfig())
+	d.Count(label)
+}
+
+// CountSummary prints the hit counts of all Count call sites to stdout.
+func CountSummary() {
+	if !Enabled() {
+		return
+	}
+	d := NewDumper(defaultConfig())
+	d.CountSummary()
+}
+
+// DumpT prints the given values to stdout using the DefaultConfig if the tag is
+// enabled by the GOVAR_TAGS environment variable or SetTags, and does nothing
+// otherwise: `govar.DumpT("db", query)`.
+func DumpT(tag string, values ...any) {
+	if !Enabled() {
+		return
+	}
+	d := NewDumper(defaultConfig())
+	d.DumpT(tag, values...)
+}
+
+// printfConfig is the configuration of the package-level Printf-style
+// functions: the SimpleConfig without colors, suited to log messages.
+func printfConfig() DumperConfig {
+	cfg := SimpleConfig
+	cfg.UseColors = false
+	return cfg
+}
+
+// Sprintf formats according to a format specifier like fmt.Sprintf, rendering
+// %v operands inline with govar (no colors, types only for %+v), e.g.
+// govar.Sprintf("user=%v items=%v", user, items). See Dumper.Sprintf. When
+// dumping is disabled (see SetEnabled), it behaves exactly like fmt.Sprintf.
+func Sprintf(format string, args ...any) string {
+	if !Enabled() {
+		return fmt.Sprintf(format, args...)
+	}
+	d := NewDumper(printfConfig())
+	return d.Sprintf(format, args...)
+}
+
+// Printf is like Sprintf, printing to stdout.
+func Printf(format string, args ...any) {
+	if !Enabled() {
+		fmt.Fprintf(stdoutWriter(), format, args...)
+		return
+	}
+	d := NewDumper(printfConfig())
+	d.Printf(format, args...)
+}
+
+// Fprintf is like Sprintf, writing to w. It returns the number of bytes
+// written and any write error.
+func Fprintf(w io.Writer, format string, args ...any) (int, error) {
+	if !Enabled() {
+		return fmt.Fprintf(w, format, args...)
+	}
+	d := NewDumper(printfConfig())
+	return d.Fprintf(w, format, args...)
+}
+
+// DumpWith prints the given values to stdout using the configuration registered
+// under name (see RegisterConfig and LookupConfig), e.g. `govar.DumpWith("test", v)`.
+// Unknown names are reported and the DefaultConfig is used instead.
+func DumpWith(name string, values ...any) {
+	if !Enabled() {
+		return
+	}
+	cfg, ok := LookupConfig(name)
+	if !ok {
+		fmt.Fprintln(stdoutWriter(), NewDumper(defaultConfig()).unknownConfigNotice(name))
+		cfg = defaultConfig()
+	}
+	d := NewDumper(cfg)
+	d.Dump(values...)
+}
+
+// Edump prints the given values to stderr using the DefaultConfig. Use it instead
+// of Dump when stdout carries program output (CLIs, pipelines).
+func Edump(values ...any) {
+	if !Enabled() {
+		return
+	}
+	d := NewDumper(defaultConfig())
+	d.Edump(values...)
+}
+
+// EdumpNoColors prints the given values to stderr with full formatting, but with colors disabled.
+func EdumpNoColors(values ...any) {
+	if !Enabled() {
+		return
+	}
+	cfg := defaultConfig()
+	cfg.UseColors = false
+	d := NewDumper(cfg)
+	d.Edump(values...)
+}
+
+// EdumpValues prints the values to stderr using the SimpleConfig.
+func EdumpValues(values ...any) {
+	if !Enabled() {
+		return
+	}
+	d := NewDumper(SimpleConfig)
+	d.Edump(values...)
+}
+
 // Fdump writes the formatted output of the given values to the provided io.Writer
 // using the DefaultConfig.
 func Fdump(w io.Writer, values ...any) {
-	d := NewDumper(DefaultConfig)
+	if !Enabled() {
+		return
+	}
+	d := NewDumper(defaultConfig())
 	d.Fdump(w, values...)
 }
 
 // FdumpNoColors writes formatted output to the given writer, with all formatting
 // enabled except for colored output.
 func FdumpNoColors(w io.Writer, values ...any) {
-	cfg := DefaultConfig
+	if !Enabled() {
+		return
+	}
+	cfg := defaultConfig()
 	cfg.UseColors = false
 	d := NewDumper(cfg)
 	d.Fdump(w, values...)
@@ -91,6 +369,9 @@ func FdumpNoColors(w io.Writer, values ...any) {
 
 // FdumpValues writes simplified formatted output to the writer using the SimpleConfig.
 func FdumpValues(w io.Writer, values ...any) {
+	if !Enabled() {
+		return
+	}
 	d := NewDumper(SimpleConfig)
 	d.Fdump(w, values...)
 }
@@ -98,14 +379,20 @@ func FdumpValues(w io.Writer, values ...any) {
 // Sdump returns the full-formatted string representation of the given values
 // using the DefaultConfig.
 func Sdump(values ...any) string {
-	d := NewDumper(DefaultConfig)
+	if !Enabled() {
+		return ""
+	}
+	d := NewDumper(defaultConfig())
 	return d.Sdump(values...)
 }
 
 // SdumpNoColors returns the formatted string representation with all features enabled
 // except for colored output.
 func SdumpNoColors(values ...any) string {
-	cfg := DefaultConfig
+	if !Enabled() {
+		return ""
+	}
+	cfg := defaultConfig()
 	cfg.UseColors = false
 	d := NewDumper(cfg)
 	return d.Sdump(values...)
@@ -114,6 +401,9 @@ func SdumpNoColors(values ...any) string {
 // SdumpValues returns the simplified string representation of the given values
 // using the SimpleConfig.
 func SdumpValues(values ...any) string {
+	if !Enabled() {
+		return ""
+	}
 	d := NewDumper(SimpleConfig)
 	return d.Sdump(values...)
 }
@@ -121,13 +411,29 @@ func SdumpValues(values ...any) string {
 // SdumpHTML returns the HTML-formatted string representation of the values
 // using the DefaultConfig. The output is wrapped in HTML tags suitable for embedding in a web page.
 func SdumpHTML(values ...any) string {
-	d := NewDumper(DefaultConfig)
+	if !Enabled() {
+		return ""
+	}
+	d := NewDumper(defaultConfig())
 	return d.SdumpHTML(values...)
 }
 
 // SdumpHTMLValues returns a simplified HTML-formatted string of the values
 // using the SimpleConfig.
 func SdumpHTMLValues(values ...any) string {
+	if !Enabled() {
+		return ""
+	}
 	d := NewDumper(SimpleConfig)
 	return d.SdumpHTML(values...)
 }
+
+// SdumpHTMLInteractive returns a self-contained interactive HTML view of the values
+// (searchable, filterable, collapsible tree) using the DefaultConfig.
+func SdumpHTMLInteractive(values ...any) string {
+	if !Enabled() {
+		return ""
+	}
+	d := NewDumper(defaultConfig())
+	return d.SdumpHTMLInteractive(values...)
+}
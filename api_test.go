@@ -5,6 +5,7 @@ import (
 	"io"
 	"os"
 	"os/exec"
+	"reflect"
 	"strings"
 	"testing"
 )
@@ -54,6 +55,47 @@ func TestSdump(t *testing.T) {
 	})
 }
 
+// TestSdumpValue checks dumping reflect.Values without going through interface{}.
+func TestSdumpValue(t *testing.T) {
+	type node struct {
+		Name string
+		Next *node
+	}
+	type holder struct {
+		secret testData
+		shared node
+		ptr    *node
+	}
+	h := holder{secret: simpleData, shared: node{Name: "shared"}}
+	h.ptr = &h.shared
+	cfg := DefaultConfig
+	cfg.UseColors = false
+	cfg.EmbedTypeMethods = false
+	d := NewDumper(cfg)
+
+	rv := reflect.ValueOf(&h).Elem()
+	out := d.SdumpValue(rv.Field(0))
+	if !strings.Contains(out, `"Test"`) || !strings.Contains(out, "123") {
+		t.Errorf("expected the unexported field to be dumped, got:\n%s", out)
+	}
+
+	// Addressable values keep their identity, so the pointer is a back-reference.
+	out = d.SdumpValue(rv.Field(1), rv.Field(2))
+	if !strings.Contains(out, "&1") || !strings.Contains(out, "↩︎ &1") {
+		t.Errorf("expected a back-reference to the addressable value, got:\n%s", out)
+	}
+
+	if out := d.SdumpValue(reflect.Value{}); !strings.Contains(out, "<nil>") {
+		t.Errorf("expected the zero reflect.Value to be rendered as nil, got:\n%s", out)
+	}
+
+	var buf bytes.Buffer
+	d.FdumpValue(&buf, reflect.ValueOf(simpleData))
+	if !strings.Contains(buf.String(), "govar.testData") {
+		t.Errorf("FdumpValue() output should contain type information, got:\n%s", buf.String())
+	}
+}
+
 // TestFdump checks the Fdump variants by writing to a buffer.
 func TestFdump(t *testing.T) {
 	var buf bytes.Buffer
@@ -110,6 +152,34 @@ func TestSdumpHTML(t *testing.T) {
 		}
 	})
 
+	t.Run("SdumpHTML with collapsed subtrees", func(t *testing.T) {
+		type Inner struct{ A, B, C []int }
+		type Outer struct{ Inner Inner }
+		cfg := DefaultConfig
+		cfg.HTMLCollapseDepth = 1
+		out := NewDumper(cfg).SdumpHTML(Outer{Inner{A: []int{1}, B: []int{2}, C: []int{3}}})
+		if !strings.Contains(out, `<details class="govar-collapsed"`) {
+			t.Errorf("SdumpHTML() should wrap subtrees at the collapse depth in <details>, got:\n%s", out)
+		}
+		if !strings.Contains(out, "… 3 fields") {
+			t.Errorf("SdumpHTML() collapsed summary should mention the field count, got:\n%s", out)
+		}
+	})
+
+	t.Run("SdumpHTML as a document", func(t *testing.T) {
+		cfg := DefaultConfig
+		cfg.HTMLDocument = true
+		out := NewDumper(cfg).SdumpHTML(simpleData)
+		if !strings.HasPrefix(out, "<!DOCTYPE html>\n<html>\n<head>\n<meta charset=\"utf-8\">") {
+			t.Errorf("SdumpHTML() should start with a document head, got:\n%s", out)
+		}
+		for _, want := range []string{"<style>", "<body>\n<pre class=\"govar\"", "</pre>\n</body>\n</html>\n"} {
+			if !strings.Contains(out, want) {
+				t.Errorf("SdumpHTML() document should contain %q, got:\n%s", want, out)
+			}
+		}
+	})
+
 	t.Run("SdumpHTMLValues", func(t *testing.T) {
 		out := SdumpHTMLValues(simpleData)
 		if !strings.HasPrefix(out, "<pre") {
@@ -121,6 +191,20 @@ func TestSdumpHTML(t *testing.T) {
 	})
 }
 
+// TestSdumpHTMLInteractive checks that the interactive output embeds the tree and the script.
+func TestSdumpHTMLInteractive(t *testing.T) {
+	out := SdumpHTMLInteractive(simpleData)
+	if !strings.HasPrefix(out, `<div class="govar-interactive">`) {
+		t.Error("SdumpHTMLInteractive() output should start with the interactive container")
+	}
+	if !strings.Contains(out, `<script type="application/json">[{"type":"govar.testData","kind":"struct"`) {
+		t.Errorf("SdumpHTMLInteractive() should embed the render tree as JSON, got:\n%s", out)
+	}
+	if !strings.Contains(out, "gv-search") || !strings.Contains(out, "gv-collapse") {
+		t.Error("SdumpHTMLInteractive() should contain the search and collapse controls")
+	}
+}
+
 // TestDump captures stdout to verify that Dump produces output.
 func TestDump(t *testing.T) {
 	// Keep backup of the real stdout
@@ -147,6 +231,63 @@ func TestDump(t *testing.T) {
 	}
 }
 
+// TestEdump captures stderr to verify that Edump writes there and not to stdout.
+func TestEdump(t *testing.T) {
+	oldOut, oldErr := os.Stdout, os.Stderr
+	rOut, wOut, _ := os.Pipe()
+	rErr, wErr, _ := os.Pipe()
+	os.Stdout, os.Stderr = wOut, wErr
+
+	EdumpNoColors(simpleData)
+
+	wOut.Close()
+	wErr.Close()
+	os.Stdout, os.Stderr = oldOut, oldErr
+
+	var stdout, stderr bytes.Buffer
+	io.Copy(&stdout, rOut)
+	io.Copy(&stderr, rErr)
+
+	if stdout.Len() != 0 {
+		t.Errorf("EdumpNoColors() should not write to stdout, got %q", stdout.String())
+	}
+	if !strings.Contains(stderr.String(), `"Test"`) {
+		t.Errorf("EdumpNoColors() output missing content: got %q", stderr.String())
+	}
+}
+
+// TestSetEnabled verifies that disabling dumping silences the package-level
+// functions and that enabling it again restores them.
+func TestSetEnabled(t *testing.T) {
+	SetEnabled(false)
+	defer SetEnabled(true)
+
+	old := os.Stdout
+	r, w, _ := os.Pipe()
+	os.Stdout = w
+
+	Dump(simpleData)
+	DumpValues(simpleData)
+	Trace("disabled", simpleData)
+
+	w.Close()
+	os.Stdout = old
+
+	var buf bytes.Buffer
+	io.Copy(&buf, r)
+	if buf.Len() != 0 {
+		t.Errorf("expected no output while disabled, got %q", buf.String())
+	}
+	if out := Sdump(simpleData); out != "" {
+		t.Errorf("expected Sdump() to return \"\" while disabled, got %q", out)
+	}
+
+	SetEnabled(true)
+	if !Enabled() || Sdump(simpleData) == "" {
+		t.Error("expected Sdump() to produce output after SetEnabled(true)")
+	}
+}
+
 // TestDie is a special test that checks if the Die function exits with status 1.
 // It does this by re-running the test binary with a specific environment variable.
 func TestDie(t *testing.T) {
@@ -172,3 +313,57 @@ func TestDie(t *testing.T) {
 	// The default exit code for a failed test is 1, which matches what Die() does.
 	// So we just check that the process failed.
 }
+
+// TestDieConfigured checks that Die honors DieExitCode and DieToStderr.
+func TestDieConfigured(t *testing.T) {
+	if os.Getenv("GOVAR_TEST_DIE") == "configured" {
+		cfg := DefaultConfig
+		cfg.UseColors = false
+		cfg.DieExitCode = 3
+		cfg.DieToStderr = true
+		NewDumper(cfg).Die("testing die")
+		return
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command(os.Args[0], "-test.run=^TestDieConfigured$")
+	cmd.Env = append(os.Environ(), "GOVAR_TEST_DIE=configured")
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+	err := cmd.Run()
+
+	e, ok := err.(*exec.ExitError)
+	if !ok || e.ExitCode() != 3 {
+		t.Fatalf("expected exit status 3, got %v", err)
+	}
+	if !strings.Contains(stderr.String(), `"testing die"`) {
+		t.Errorf("Die() should write to stderr, got %q", stderr.String())
+	}
+	if strings.Contains(stdout.String(), "testing die") {
+		t.Errorf("Die() should not write to stdout, got %q", stdout.String())
+	}
+}
+
+// TestDieOnPanic checks that a deferred DieOnPanic dumps the panic value and stack.
+func TestDieOnPanic(t *testing.T) {
+	if os.Getenv("GOVAR_TEST_DIE") == "panic" {
+		defer DieOnPanic()
+		panic(testData{Name: "boom", Value: 7})
+	}
+
+	var stdout bytes.Buffer
+	cmd := exec.Command(os.Args[0], "-test.run=^TestDieOnPanic$")
+	cmd.Env = append(os.Environ(), "GOVAR_TEST_DIE=panic")
+	cmd.Stdout = &stdout
+	err := cmd.Run()
+
+	e, ok := err.(*exec.ExitError)
+	if !ok || e.ExitCode() != 1 {
+		t.Fatalf("expected exit status 1, got %v", err)
+	}
+	out := stdout.String()
+	for _, want := range []string{"[!] panic", "boom", "goroutine", "TestDieOnPanic"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("DieOnPanic() output missing %q, got:\n%s", want, out)
+		}
+	}
+}
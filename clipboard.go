@@ -0,0 +1,47 @@
+// Package govar provides a powerful and highly configurable pretty-printer for Go
+// data structures. This file implements the CopyToClipboard option: after a dump
+// is written to a terminal, its plain-text form is sent to the system clipboard
+// with an OSC 52 escape sequence, which terminals supporting it (xterm, iTerm2,
+// kitty, WezTerm, Windows Terminal, …) handle even over SSH, and others ignore.
+package govar
+
+import (
+	"encoding/base64"
+	"io"
+	"os"
+	"strings"
+)
+
+// maxClipboardPayload is the largest base64 payload sent in an OSC 52 sequence.
+// Several terminals drop longer sequences, so larger dumps are not copied.
+const maxClipboardPayload = 100000
+
+// copyToClipboard writes an OSC 52 sequence copying the plain-text dump of vs to
+// w, if CopyToClipboard is enabled and w is a terminal.
+func (d *Dumper) copyToClipboard(w io.Writer, vs []any) {
+	if !d.config.CopyToClipboard || !isTerminal(w) {
+		return
+	}
+	cfg := d.config
+	cfg.UseColors = false
+	cfg.CopyToClipboard = false
+	text := NewDumper(cfg).Record(RecordText, vs...).Text
+	if seq := osc52Sequence(text, os.Getenv("TMUX") != ""); seq != "" {
+		io.WriteString(w, seq)
+	}
+}
+
+// osc52Sequence returns the OSC 52 sequence setting the clipboard to text, or
+// "" if text is too long. Inside tmux, the sequence is wrapped in a DCS
+// passthrough, as tmux does not forward it to the outer terminal otherwise.
+func osc52Sequence(text string, tmux bool) string {
+	payload := base64.StdEncoding.EncodeToString([]byte(strings.TrimSuffix(text, "\n")))
+	if len(payload) > maxClipboardPayload {
+		return ""
+	}
+	seq := "\033]52;c;" + payload + "\a"
+	if tmux {
+		seq = "\033Ptmux;" + strings.ReplaceAll(seq, "\033", "\033\033") + "\033\\"
+	}
+	return seq
+}
@@ -0,0 +1,37 @@
+package govar
+
+import (
+	"bytes"
+	"encoding/base64"
+	"strings"
+	"testing"
+)
+
+func TestOSC52Sequence(t *testing.T) {
+	seq := osc52Sequence("int => 42\n", false)
+	want := "\033]52;c;" + base64.StdEncoding.EncodeToString([]byte("int => 42")) + "\a"
+	if seq != want {
+		t.Errorf("expected %q, got %q", want, seq)
+	}
+
+	wrapped := osc52Sequence("int => 42", true)
+	if !strings.HasPrefix(wrapped, "\033Ptmux;\033\033]52;c;") || !strings.HasSuffix(wrapped, "\a\033\\") {
+		t.Errorf("expected a tmux passthrough sequence, got %q", wrapped)
+	}
+
+	if seq := osc52Sequence(strings.Repeat("x", maxClipboardPayload), false); seq != "" {
+		t.Errorf("oversized text should not be copied, got a %d byte sequence", len(seq))
+	}
+}
+
+func TestCopyToClipboardSkipsNonTerminals(t *testing.T) {
+	cfg := DefaultConfig
+	cfg.UseColors = false
+	cfg.EmbedTypeMethods = false
+	cfg.CopyToClipboard = true
+	buf := &bytes.Buffer{}
+	NewDumper(cfg).Fdump(buf, 42)
+	if strings.Contains(buf.String(), "\033]52") {
+		t.Errorf("OSC 52 sequence should only be written to terminals, got %q", buf.String())
+	}
+}
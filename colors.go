@@ -25,6 +25,9 @@ const (
 	ColorReset = "\033[0m"
 )
 
+// depthPalette is cycled through by nesting level when RainbowDepth is enabled.
+var depthPalette = []string{ColorGoldenrod, ColorPink, ColorSkyBlue, ColorGreen, ColorCoralRed, ColorMutedBlue}
+
 // ColorPaletteHTML maps color codes to HTML colors.
 var ColorPaletteHTML = map[string]string{
 	ColorPaleGray:  "#B0BEC5",
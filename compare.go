@@ -0,0 +1,272 @@
+// Package govar provides a powerful and highly configurable pretty-printer for Go
+// data structures. This file implements comparison overlays: a value is rendered
+// as usual, but the fields, elements and map entries whose values differ from a
+// baseline are highlighted, and the entries only the baseline has are listed.
+// It is a lighter-weight alternative to a full diff report.
+package govar
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// diffStatus classifies a value rendered by SdumpAgainst.
+type diffStatus int
+
+const (
+	diffSame    diffStatus = iota // The value equals the baseline.
+	diffChanged                   // The value differs from the baseline.
+	diffAdded                     // The baseline has no value at this path.
+)
+
+// missingEntry is an element or map entry of the baseline that the compared
+// value does not have.
+type missingEntry struct {
+	step  string        // Path step of the entry, e.g. `[3]` or `["key"]`.
+	label string        // Index or map key, as rendered.
+	value reflect.Value // Value of the entry in the baseline.
+}
+
+// baselineDiff holds the differences between a value and its baseline, keyed
+// by the paths the renderer tracks (see enterPath).
+type baselineDiff struct {
+	status  map[string]diffStatus     // Changed and added values.
+	was     map[string]reflect.Value  // Baseline values of changed values.
+	missing map[string][]missingEntry // Entries only in the baseline, by the path of their container.
+	visited map[[2]uintptr]bool       // Pointer pairs already compared, to stop at cycles.
+}
+
+// DumpAgainst prints current to stdout like Dump, highlighting where it differs
+// from baseline. See SdumpAgainst.
+func (d *Dumper) DumpAgainst(baseline, current any) {
+	fmt.Fprintln(stdoutWriter(), d.SdumpAgainst(baseline, current))
+}
+
+// FdumpAgainst writes current to w like Fdump, highlighting where it differs
+// from baseline. See SdumpAgainst.
+func (d *Dumper) FdumpAgainst(w io.Writer, baseline, current any) {
+	fmt.Fprintln(w, d.SdumpAgainst(baseline, current))
+}
+
+// SdumpAgainst renders current like Sdump, comparing it field by field with
+// baseline, typically an earlier copy of the same value. The labels of fields,
+// elements and map entries whose values changed are shown in yellow and their
+// values marked |changed: was …|, those baseline does not have in green, marked
+// |added|, and the elements and map entries only baseline has are listed in
+// red, marked |missing|. Pointers and interfaces are followed on both sides;
+// values of different types count as changed.
+func (d *Dumper) SdumpAgainst(baseline, current any) string {
+	d.Formatter = d.textFormatter(d.config.UseColors)
+	d.overlay = &baselineDiff{
+		status:  make(map[string]diffStatus),
+		was:     make(map[string]reflect.Value),
+		missing: make(map[string][]missingEntry),
+		visited: make(map[[2]uintptr]bool),
+	}
+	defer func() { d.overlay = nil }()
+	d.compareBaseline("", reflect.ValueOf(baseline), reflect.ValueOf(current), 0)
+
+	sb := &strings.Builder{}
+	d.renderHeader(sb)
+	d.renderAllValues(sb, current)
+	return sb.String()
+}
+
+// compareBaseline records the differences between cur, rendered at path, and
+// base in d.overlay. Values nested deeper than MaxDepth are not compared, as
+// they are not rendered either.
+func (d *Dumper) compareBaseline(path string, base, cur reflect.Value, level int) {
+	if level > d.config.MaxDepth {
+		return
+	}
+	for {
+		if !base.IsValid() || !cur.IsValid() {
+			if base.IsValid() != cur.IsValid() {
+				d.overlay.markChanged(path, base)
+			}
+			return
+		}
+		if base.Type() != cur.Type() {
+			d.overlay.markChanged(path, base)
+			return
+		}
+		if base.Kind() != reflect.Ptr && base.Kind() != reflect.Interface {
+			break
+		}
+		if base.IsNil() || cur.IsNil() {
+			if base.IsNil() != cur.IsNil() {
+				d.overlay.markChanged(path, base)
+			}
+			return
+		}
+		if base.Kind() == reflect.Ptr {
+			pair := [2]uintptr{base.Pointer(), cur.Pointer()}
+			if pair[0] == pair[1] || d.overlay.visited[pair] {
+				return
+			}
+			d.overlay.visited[pair] = true
+		}
+		base, cur = base.Elem(), cur.Elem()
+	}
+
+	switch cur.Kind() {
+	case reflect.Struct:
+		for i := range cur.NumField() {
+			d.compareBaseline(appendPathStep(path, cur.Type().Field(i).Name), base.Field(i), cur.Field(i), level+1)
+		}
+	case reflect.Slice, reflect.Array:
+		shared := min(base.Len(), cur.Len())
+		for i := range shared {
+			d.compareBaseline(appendPathStep(path, indexPathStep(i)), base.Index(i), cur.Index(i), level+1)
+		}
+		for i := shared; i < cur.Len(); i++ {
+			d.overlay.status[appendPathStep(path, indexPathStep(i))] = diffAdded
+		}
+		for i := shared; i < base.Len(); i++ {
+			entry := missingEntry{step: indexPathStep(i), label: strconv.Itoa(i), value: base.Index(i)}
+			d.overlay.missing[path] = append(d.overlay.missing[path], entry)
+		}
+	case reflect.Map:
+		for _, key := range cur.MapKeys() {
+			step := appendPathStep(path, mapKeyPathStep(key))
+			if baseVal := base.MapIndex(key); baseVal.IsValid() {
+				d.compareBaseline(step, baseVal, cur.MapIndex(key), level+1)
+			} else {
+				d.overlay.status[step] = diffAdded
+			}
+		}
+		for _, key := range sortMapKeys(base) {
+			if !cur.MapIndex(key).IsValid() {
+				entry := missingEntry{step: mapKeyPathStep(key), label: d.formatMapKeyAsIndex(key), value: base.MapIndex(key)}
+				d.overlay.missing[path] = append(d.overlay.missing[path], entry)
+			}
+		}
+	default:
+		if !sameScalar(base, cur) {
+			d.overlay.markChanged(path, base)
+		}
+	}
+}
+
+// markChanged records that the value at path differs from base.
+func (o *baselineDiff) markChanged(path string, base reflect.Value) {
+	o.status[path] = diffChanged
+	o.was[path] = base
+}
+
+// sameScalar reports whether two values of the same non-composite type are
+// equal. Channels, functions and unsafe pointers are compared by address, and
+// NaN equals NaN, as the renderings are then identical.
+func sameScalar(a, b reflect.Value) bool {
+	switch a.Kind() {
+	case reflect.Bool:
+		return a.Bool() == b.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return a.Int() == b.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return a.Uint() == b.Uint()
+	case reflect.Float32, reflect.Float64:
+		x, y := a.Float(), b.Float()
+		return x == y || (x != x && y != y)
+	case reflect.Complex64, reflect.Complex128:
+		return a.Complex() == b.Complex()
+	case reflect.String:
+		return a.String() == b.String()
+	case reflect.Chan, reflect.Func, reflect.UnsafePointer:
+		return a.Pointer() == b.Pointer()
+	}
+	return true
+}
+
+// formatLabel formats the field name, index or map key of the child at step of
+// the value being rendered with color, or leaves it plain if color is empty.
+// While SdumpAgainst renders, labels of changed and added values are colored
+// by their status instead.
+func (d *Dumper) formatLabel(step, color, label string) string {
+	if d.overlay != nil {
+		switch d.overlay.status[appendPathStep(d.valuePath, step)] {
+		case diffChanged:
+			color = ColorGoldenrod
+		case diffAdded:
+			color = ColorGreen
+		}
+	}
+	if color == "" {
+		return label
+	}
+	return d.ApplyFormat(color, label)
+}
+
+// renderDiffHint marks the value just rendered as changed or added while
+// SdumpAgainst renders, so the differences show without colors as well.
+func (d *Dumper) renderDiffHint(sb *strings.Builder) {
+	if d.overlay == nil {
+		return
+	}
+	switch d.overlay.status[d.valuePath] {
+	case diffChanged:
+		sb.WriteString(" " + d.ApplyFormat(ColorDimGray, "|changed: was "+d.summarizeBaseline(d.overlay.was[d.valuePath])+"|"))
+	case diffAdded:
+		sb.WriteString(" " + d.ApplyFormat(ColorDimGray, "|added|"))
+	}
+}
+
+// summarizeBaseline renders the baseline value of a changed value on one line,
+// shortened to MaxInlineLength and anonymized like map keys.
+func (d *Dumper) summarizeBaseline(v reflect.Value) string {
+	if !v.IsValid() {
+		return "nil"
+	}
+	if d.config.Anonymize != AnonymizeOff {
+		return d.formatAnonymizedMapKey(v)
+	}
+	summary := d.summarizeKey(v, 0)
+	if utf8.RuneCountInString(summary) > d.config.MaxInlineLength {
+		summary = string([]rune(summary)[:max(d.config.MaxInlineLength-1, 0)]) + "…"
+	}
+	return summary
+}
+
+// renderMissingEntries lists the baseline entries missing from the list or map
+// being rendered while SdumpAgainst renders. Inline, they are appended to the
+// entries; first is true if no entry precedes them.
+func (d *Dumper) renderMissingEntries(sb *strings.Builder, level int, inline, first bool) {
+	if d.overlay == nil {
+		return
+	}
+	for _, entry := range d.overlay.missing[d.valuePath] {
+		label := d.ApplyFormat(ColorRed, entry.label)
+		if d.config.JSONLikeLayout {
+			label += ": "
+		} else {
+			label += " "
+		}
+		if formattedType := d.formatType(entry.value, true); formattedType != "" {
+			label += formattedType + " "
+		}
+		if !d.config.JSONLikeLayout {
+			label += "=> "
+		}
+		leave := d.enterPath(entry.step)
+		if inline {
+			if !first {
+				sb.WriteString(", ")
+			}
+			first = false
+			sb.WriteString(label)
+			d.renderValue(sb, entry.value, level, false)
+		} else {
+			d.renderIndent(sb, level+1, label)
+			d.renderValue(sb, entry.value, level+1, false)
+		}
+		leave()
+		sb.WriteString(" " + d.ApplyFormat(ColorDimGray, "|missing|"))
+		if !inline {
+			d.renderBlockLineEnd(sb)
+		}
+	}
+}
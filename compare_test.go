@@ -0,0 +1,60 @@
+package govar
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSdumpAgainst(t *testing.T) {
+	type Item struct {
+		Name string
+		Qty  int
+	}
+	type Order struct {
+		ID    int
+		Items []Item
+		Tags  map[string]string
+		Note  *string
+	}
+	note := "rush"
+	baseline := Order{ID: 1, Items: []Item{{"apple", 1}, {"pear", 2}}, Tags: map[string]string{"env": "prod", "gone": "x"}}
+	current := Order{ID: 1, Items: []Item{{"apple", 5}}, Tags: map[string]string{"env": "prod", "new": "y"}, Note: &note}
+
+	cfg := DefaultConfig
+	cfg.EmbedTypeMethods = false
+	cfg.ShowMetaInformation = false
+	cfg.Palette = PaletteDark
+	out := NewDumper(cfg).SdumpAgainst(baseline, current)
+	for _, want := range []string{
+		ColorGoldenrod + "Qty" + ColorReset,     // changed field
+		ColorGoldenrod + "Note" + ColorReset,    // nil pointer became non-nil
+		ColorGreen + `"new"` + ColorReset,       // added map entry
+		ColorRed + `"gone"` + ColorReset,        // missing map entry
+		ColorRed + "1" + ColorReset,             // missing element
+		ColorLightTeal + "Name" + ColorReset,    // unchanged field
+		ColorDarkTeal + `"env"` + ColorReset,    // unchanged map entry
+		ColorDimGray + "|missing|" + ColorReset, // marker
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q, got:\n%q", want, out)
+		}
+	}
+
+	cfg.UseColors = false
+	out = NewDumper(cfg).SdumpAgainst([]int{1, 2, 3}, []int{1, 4})
+	if !strings.Contains(out, "[0 => 1, 1 => 4 |changed: was 2|, 2 => 3 |missing|]") {
+		t.Errorf("missing elements should be listed, got:\n%s", out)
+	}
+
+	out = NewDumper(cfg).SdumpAgainst(baseline, current)
+	for _, want := range []string{
+		`=> 5 |changed: was 1|`,
+		`=> "y" |added|`,
+		`|changed: was nil|`,
+		`"gone" => "x" |missing|`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q without colors, got:\n%s", want, out)
+		}
+	}
+}
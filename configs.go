@@ -0,0 +1,80 @@
+// Package govar provides a powerful and highly configurable pretty-printer for Go
+// data structures. This file implements the registry of named configurations,
+// so a configuration can be selected by name at runtime, e.g. from a flag or an
+// environment variable, with DumpWith or LookupConfig, and SetDefault, which
+// overrides the configuration of the package-level functions.
+package govar
+
+import (
+	"strconv"
+	"sync"
+	"sync/atomic"
+)
+
+// defaultOverride holds the configuration set with SetDefault.
+var defaultOverride atomic.Pointer[DumperConfig]
+
+// SetDefault replaces the configuration of the package-level functions that
+// use DefaultConfig (Dump, Sdump, Edump, Fdump, Die, Trace, the NoColors
+// variants, …), e.g. to disable colors in CI or lower MaxItems, without
+// replacing every call with a custom Dumper. Unlike assigning to DefaultConfig,
+// it is safe for concurrent use. SetDefault(DefaultConfig) restores the
+// preset.
+func SetDefault(cfg DumperConfig) {
+	defaultOverride.Store(&cfg)
+}
+
+// defaultConfig returns the configuration set with SetDefault, or DefaultConfig.
+func defaultConfig() DumperConfig {
+	if cfg := defaultOverride.Load(); cfg != nil {
+		return *cfg
+	}
+	return DefaultConfig
+}
+
+// configRegistry holds the configurations registered with RegisterConfig.
+var configRegistry sync.Map // name -> DumperConfig
+
+// builtinConfig returns the preset of the given name, unless replaced with
+// RegisterConfig. They are read when looked up, so changes to the preset
+// variables are honored.
+func builtinConfig(name string) (DumperConfig, bool) {
+	switch name {
+	case "default":
+		return defaultConfig(), true
+	case "simple":
+		return SimpleConfig, true
+	case "json":
+		return JSONLikeConfig, true
+	case "test":
+		return TestConfig, true
+	case "log":
+		return LogConfig, true
+	case "deep":
+		return DeepConfig, true
+	}
+	return DumperConfig{}, false
+}
+
+// RegisterConfig registers cfg under name, replacing any configuration
+// registered before, including the presets "default", "simple", "json",
+// "test", "log", and "deep". It is safe for concurrent use.
+func RegisterConfig(name string, cfg DumperConfig) {
+	configRegistry.Store(name, cfg)
+}
+
+// LookupConfig returns the configuration registered under name with
+// RegisterConfig or, failing that, the preset of that name. The boolean
+// reports whether a configuration was found.
+func LookupConfig(name string) (DumperConfig, bool) {
+	if cfg, ok := configRegistry.Load(name); ok {
+		return cfg.(DumperConfig), true
+	}
+	return builtinConfig(name)
+}
+
+// unknownConfigNotice formats the line DumpWith prints for an unknown name.
+func (d *Dumper) unknownConfigNotice(name string) string {
+	d.Formatter = d.textFormatter(d.config.UseColors)
+	return d.ApplyFormat(ColorCoralRed, "[!] unknown govar config "+strconv.Quote(name)+", using the default")
+}
@@ -0,0 +1,69 @@
+package govar
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestLookupConfig(t *testing.T) {
+	for _, name := range []string{"default", "simple", "json", "test", "log", "deep"} {
+		if _, ok := LookupConfig(name); !ok {
+			t.Errorf("expected the preset %q", name)
+		}
+	}
+	if cfg, _ := LookupConfig("deep"); cfg.MaxItems != math.MaxInt {
+		t.Errorf("expected the deep preset not to truncate, got MaxItems %d", cfg.MaxItems)
+	}
+	if _, ok := LookupConfig("nope"); ok {
+		t.Error("expected unknown names not to be found")
+	}
+
+	custom := SimpleConfig
+	custom.IndentWidth = 7
+	RegisterConfig("custom", custom)
+	if cfg, ok := LookupConfig("custom"); !ok || cfg.IndentWidth != 7 {
+		t.Errorf("expected the registered config, got %+v (found=%v)", cfg, ok)
+	}
+}
+
+func TestPresetConfigs(t *testing.T) {
+	type Order struct {
+		ID    int
+		Items []string
+		Notes map[string]string
+	}
+	order := Order{ID: 7, Items: []string{"a", "b"}, Notes: map[string]string{"gift": "yes"}}
+
+	out := strings.TrimSuffix(NewDumper(LogConfig).Sdump(order), "\n")
+	if strings.Count(out, "\n") != 1 || !strings.HasSuffix(out, `{⯀ ID => 7, ⯀ Items => [0 => "a", 1 => "b"], ⯀ Notes => ["gift"  => "yes"]}`) {
+		t.Errorf("expected the header and a single line, got:\n%s", out)
+	}
+
+	ch := make(chan int)
+	first, second := NewDumper(TestConfig).Sdump(ch), NewDumper(TestConfig).Sdump(ch)
+	if first != second || strings.Contains(first, "0x") || strings.Contains(first, "\033[") {
+		t.Errorf("expected stable, colorless output, got:\n%s\n%s", first, second)
+	}
+}
+
+func TestSetDefault(t *testing.T) {
+	cfg := DefaultConfig
+	cfg.UseColors = false
+	cfg.MaxItems = 2
+	SetDefault(cfg)
+	defer SetDefault(DefaultConfig)
+
+	out := Sdump([]int{1, 2, 3})
+	if strings.Contains(out, "\033[") || !strings.Contains(out, "… (truncated)") {
+		t.Errorf("expected colorless, truncated output, got %q", out)
+	}
+	if got, _ := LookupConfig("default"); got.MaxItems != 2 {
+		t.Errorf("expected the \"default\" config to follow SetDefault, got MaxItems %d", got.MaxItems)
+	}
+
+	SetDefault(DefaultConfig)
+	if out := Sdump([]int{1, 2, 3}); strings.Contains(out, "truncated") {
+		t.Errorf("expected SetDefault(DefaultConfig) to restore the preset, got %q", out)
+	}
+}
@@ -0,0 +1,50 @@
+// Package govar provides a powerful and highly configurable pretty-printer for Go
+// data structures. This file implements the ShowDumpCounters option, which adds
+// a process-wide dump sequence number and a per-call-site hit count to the
+// header (e.g. "[>] govar.Dump #17 (3rd hit)"), to correlate interleaved output
+// of loops and goroutines.
+package govar
+
+import (
+	"strconv"
+	"sync"
+	"sync/atomic"
+)
+
+var (
+	dumpSequence atomic.Uint64
+	callSiteHits sync.Map // "file:line" -> *atomic.Uint64
+)
+
+// nextDumpCounters advances the dump sequence and the hit count of the call
+// site at file and line, and returns both.
+func nextDumpCounters(file string, line int) (seq, hits uint64) {
+	counter, _ := callSiteHits.LoadOrStore(file+":"+strconv.Itoa(line), new(atomic.Uint64))
+	return dumpSequence.Add(1), counter.(*atomic.Uint64).Add(1)
+}
+
+// ordinal returns n with its English ordinal suffix (1st, 2nd, 3rd, 11th, …).
+func ordinal(n uint64) string {
+	suffix := "th"
+	if n%100 < 11 || n%100 > 13 {
+		switch n % 10 {
+		case 1:
+			suffix = "st"
+		case 2:
+			suffix = "nd"
+		case 3:
+			suffix = "rd"
+		}
+	}
+	return strconv.FormatUint(n, 10) + suffix
+}
+
+// dumpCountersLabel returns the counters shown after the function name in the
+// header, e.g. " #17 (3rd hit)", or "" if ShowDumpCounters is disabled.
+func (d *Dumper) dumpCountersLabel(file string, line int) string {
+	if !d.config.ShowDumpCounters {
+		return ""
+	}
+	seq, hits := nextDumpCounters(file, line)
+	return " #" + strconv.FormatUint(seq, 10) + " (" + ordinal(hits) + " hit)"
+}
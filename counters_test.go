@@ -0,0 +1,32 @@
+package govar
+
+import (
+	"strconv"
+	"testing"
+)
+
+func TestOrdinal(t *testing.T) {
+	for n, want := range map[uint64]string{1: "1st", 2: "2nd", 3: "3rd", 4: "4th", 11: "11th", 12: "12th", 13: "13th", 21: "21st", 102: "102nd", 111: "111th"} {
+		if got := ordinal(n); got != want {
+			t.Errorf("ordinal(%d) = %q, want %q", n, got, want)
+		}
+	}
+}
+
+func TestDumpCountersLabel(t *testing.T) {
+	cfg := DefaultConfig
+	cfg.ShowDumpCounters = true
+	d := NewDumper(cfg)
+
+	start := dumpSequence.Load()
+	d.dumpCountersLabel("loop.go", 10)
+	d.dumpCountersLabel("other.go", 3)
+	if got, want := d.dumpCountersLabel("loop.go", 10), " #"+strconv.FormatUint(start+3, 10)+" (2nd hit)"; got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+
+	cfg.ShowDumpCounters = false
+	if got := NewDumper(cfg).dumpCountersLabel("loop.go", 10); got != "" {
+		t.Errorf("disabled counters should not be shown, got %q", got)
+	}
+}
@@ -0,0 +1,81 @@
+// Package govar provides a powerful and highly configurable pretty-printer for Go
+// data structures. This file implements the DedupeRepeats option: a dump
+// identical to the previous one from the same call site is suppressed, and the
+// number of suppressed repeats is printed as "… repeated ×N" once the output
+// of that call site changes, another call site dumps, or maxSuppressedRepeats
+// repeats have been suppressed.
+package govar
+
+import (
+	"strconv"
+	"sync"
+)
+
+// maxSuppressedRepeats is the number of repeats after which the count is
+// printed even though the output has not changed, so that a long-running loop
+// dumping the same value still shows signs of life.
+const maxSuppressedRepeats = 1000
+
+// repeatState tracks the last dump of a call site.
+type repeatState struct {
+	written bool   // A dump was written.
+	last    string // Rendered values of the last dump written, without the header and render statistics.
+	repeats int    // Dumps identical to last suppressed since it was written.
+}
+
+var (
+	repeatMu       sync.Mutex
+	repeatStates   = map[string]*repeatState{} // "file:line" -> state, of all call sites dumping with DedupeRepeats.
+	lastRepeatSite *repeatState                // Call site of the last dump written or suppressed.
+)
+
+// dedupeRepeat reports whether the dump whose values rendered as body repeats
+// the previous dump of the calling call site and must be suppressed. Either
+// way it returns the "… repeated ×N" lines to write first, or "" if there are
+// no suppressed repeats to report: those of the call site that dumped last, if
+// it was another one, and those of this call site once its output changes or
+// maxSuppressedRepeats is reached.
+func (d *Dumper) dedupeRepeat(body string) (string, bool) {
+	if !d.config.DedupeRepeats {
+		return "", false
+	}
+	file, line, _ := findCallerInStack()
+	if file == "" {
+		return "", false
+	}
+	key := file + ":" + strconv.Itoa(line)
+	repeatMu.Lock()
+	defer repeatMu.Unlock()
+	s := repeatStates[key]
+	if s == nil {
+		s = &repeatState{}
+		repeatStates[key] = s
+	}
+
+	repeated := ""
+	if prev := lastRepeatSite; prev != nil && prev != s {
+		repeated = d.takeRepeats(prev)
+	}
+	lastRepeatSite = s
+	if s.written && body == s.last {
+		s.repeats++
+		if s.repeats >= maxSuppressedRepeats {
+			repeated += d.takeRepeats(s)
+		}
+		return repeated, true
+	}
+	repeated += d.takeRepeats(s)
+	s.written, s.last = true, body
+	return repeated, false
+}
+
+// takeRepeats returns the "… repeated ×N" line for the repeats suppressed at
+// call site s and resets their count, or returns "" if there are none.
+func (d *Dumper) takeRepeats(s *repeatState) string {
+	if s.repeats == 0 {
+		return ""
+	}
+	repeats := s.repeats
+	s.repeats = 0
+	return d.ApplyFormat(ColorDimGray, "… repeated ×"+strconv.Itoa(repeats)) + "\n"
+}
@@ -0,0 +1,73 @@
+package govar
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestDedupeRepeats(t *testing.T) {
+	cfg := DefaultConfig
+	cfg.UseColors = false
+	cfg.EmbedTypeMethods = false
+	cfg.DedupeRepeats = true
+	d := NewDumper(cfg)
+
+	buf := &bytes.Buffer{}
+	for _, v := range []int{1, 1, 1, 2, 2, 3} {
+		d.Fdump(buf, v)
+	}
+	out := buf.String()
+
+	if got := strings.Count(out, "[>]"); got != 3 {
+		t.Errorf("expected 3 dumps, got %d:\n%s", got, out)
+	}
+	for _, want := range []string{"int => 1\n\n… repeated ×2\n", "int => 2\n\n… repeated ×1\n", "int => 3\n"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected output to contain %q, got:\n%s", want, out)
+		}
+	}
+}
+
+func TestDedupeRepeatsLoopEndingOnRepeat(t *testing.T) {
+	cfg := DefaultConfig
+	cfg.UseColors = false
+	cfg.EmbedTypeMethods = false
+	cfg.DedupeRepeats = true
+	d := NewDumper(cfg)
+
+	buf := &bytes.Buffer{}
+	for range 3 {
+		d.Fdump(buf, "tick")
+	}
+	d.Fdump(buf, "done")
+	if want := "\"tick\"\n\n… repeated ×2\n[>]"; !strings.Contains(buf.String(), want) {
+		t.Errorf("expected repeats reported at the next dump from another call site, got:\n%s", buf.String())
+	}
+
+	buf.Reset()
+	for range maxSuppressedRepeats + 1 {
+		d.Fdump(buf, "tock")
+	}
+	if got := buf.String(); strings.Count(got, "[>]") != 1 || !strings.HasSuffix(got, "… repeated ×1000\n") {
+		t.Errorf("expected repeats reported once %d were suppressed, got:\n%s", maxSuppressedRepeats, got)
+	}
+}
+
+func TestDedupeRepeatsIgnoresRenderStats(t *testing.T) {
+	cfg := DefaultConfig
+	cfg.UseColors = false
+	cfg.EmbedTypeMethods = false
+	cfg.DedupeRepeats = true
+	cfg.ShowRenderStats = true
+	d := NewDumper(cfg)
+
+	buf := &bytes.Buffer{}
+	for range 3 {
+		d.Fdump(buf, "tick")
+	}
+	d.Fdump(buf, "done")
+	if got := strings.Count(buf.String(), "[>]"); got != 2 || !strings.Contains(buf.String(), "… repeated ×2\n") {
+		t.Errorf("expected dumps differing only in render statistics to be deduplicated, got:\n%s", buf.String())
+	}
+}
@@ -0,0 +1,71 @@
+// Package govar provides a powerful and highly configurable pretty-printer for Go
+// data structures. This file implements the Deterministic mode, which
+// normalizes the parts of a dump that vary between runs over equal data
+// (addresses and the order of map entries whose keys render alike), so dumps
+// can be compared against golden files.
+package govar
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// formatAddress renders a chan, func, or pointer address. In Deterministic mode
+// each distinct non-nil address is replaced by a label numbered in order of
+// appearance (#1, #2, …), so shared identities stay visible.
+func (d *Dumper) formatAddress(addr uintptr) string {
+	if !d.config.Deterministic || addr == 0 {
+		return fmt.Sprintf("%#x", addr)
+	}
+	label, ok := d.addressLabels[addr]
+	if !ok {
+		label = fmt.Sprintf("#%d", len(d.addressLabels)+1)
+		d.addressLabels[addr] = label
+	}
+	return label
+}
+
+// orderTiedMapKeys reorders runs of adjacent map keys that render identically
+// (e.g. distinct pointers to equal structs), whose relative order otherwise
+// depends on map iteration, by the rendering of their entries.
+func (d *Dumper) orderTiedMapKeys(v reflect.Value, keys []reflect.Value) {
+	labels := make([]string, len(keys))
+	for i, k := range keys {
+		labels[i] = d.formatMapKeyAsIndex(k)
+	}
+	for start := 0; start < len(keys); {
+		end := start + 1
+		for end < len(keys) && labels[end] == labels[start] {
+			end++
+		}
+		if end-start > 1 {
+			run := keys[start:end]
+			signatures := make(map[reflect.Value]string, len(run))
+			for _, k := range run {
+				signatures[k] = d.entrySignature(k, v.MapIndex(k))
+			}
+			sort.SliceStable(run, func(i, j int) bool {
+				return signatures[run[i]] < signatures[run[j]]
+			})
+		}
+		start = end
+	}
+}
+
+// entrySignature renders a map entry with a plain, deterministic dumper.
+func (d *Dumper) entrySignature(key, value reflect.Value) string {
+	cfg := d.config
+	cfg.UseColors = false
+	cfg.ShowMetaInformation = false
+	cfg.ShowMemoryUsage = false
+	cfg.ShowImplements = false
+	key, value = tryExport(key), tryExport(value)
+	if !key.CanInterface() || !value.CanInterface() {
+		return ""
+	}
+	sb := &strings.Builder{}
+	NewDumper(cfg).renderAllValues(sb, key.Interface(), value.Interface())
+	return sb.String()
+}
@@ -0,0 +1,58 @@
+package govar
+
+import (
+	"strings"
+	"testing"
+	"unsafe"
+)
+
+func TestDeterministicAddresses(t *testing.T) {
+	type wiring struct {
+		In      chan int
+		Same    chan int
+		Handler func()
+		Raw     unsafe.Pointer
+		Nil     chan int
+	}
+	newWiring := func() wiring {
+		ch := make(chan int)
+		n := 42
+		return wiring{In: ch, Same: ch, Handler: func() {}, Raw: unsafe.Pointer(&n)}
+	}
+
+	cfg := DefaultConfig
+	cfg.UseColors = false
+	cfg.Deterministic = true
+	first := NewDumper(cfg).Sdump(newWiring())
+	second := NewDumper(cfg).Sdump(newWiring())
+
+	if first != second {
+		t.Errorf("expected identical dumps, got:\n%s\n---\n%s", first, second)
+	}
+	for _, want := range []string{"In       chan int       => |B:0| ⮁ chan@#1", "Same     chan int       => |B:0| ⮁ chan@#1", "|func@#2|", "unsafe.Pointer(#3)", "<nil>"} {
+		if !strings.Contains(first, want) {
+			t.Errorf("expected %q in output, got:\n%s", want, first)
+		}
+	}
+	if strings.Contains(first, "0xc") {
+		t.Errorf("expected no raw addresses, got:\n%s", first)
+	}
+}
+
+func TestDeterministicMapOrder(t *testing.T) {
+	type key struct{ ID int }
+	input := map[*key]string{{ID: 1}: "b", {ID: 1}: "a", {ID: 2}: "c"}
+
+	cfg := DefaultConfig
+	cfg.UseColors = false
+	cfg.Deterministic = true
+	want := NewDumper(cfg).Sdump(input)
+	for range 20 {
+		if got := NewDumper(cfg).Sdump(input); got != want {
+			t.Fatalf("expected identical dumps, got:\n%s\n---\n%s", got, want)
+		}
+	}
+	if strings.Index(want, `"a"`) > strings.Index(want, `"b"`) {
+		t.Errorf("expected alike keys ordered by their values, got:\n%s", want)
+	}
+}
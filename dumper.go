@@ -5,38 +5,152 @@
 package govar
 
 import (
+	"bytes"
+	"encoding"
 	"encoding/hex"
+	"encoding/json"
 	"fmt"
 	"io"
 	"os"
-	"path/filepath"
 	"reflect"
+	"regexp"
+	"runtime/debug"
+	"sort"
 	"strconv"
 	"strings"
+	"time"
 	"unicode/utf8"
-	"unsafe"
 )
 
 // DumperConfig holds configuration parameters for the Dumper.
 // These control output formatting, depth, type information, etc.
 type DumperConfig struct {
-	IndentWidth         int    // Number of spaces to use per indentation level.
-	MaxDepth            int    // Maximum levels of nested structures to print.
-	MaxItems            int    // Maximum number of items to print per slice/map.
-	MaxStringLen        int    // Maximum string length before truncation.
-	MaxInlineLength     int    // Maximum inline width before switching to block format.
-	ShowTypes           bool   // Whether to show type names.
-	UseColors           bool   // Whether to apply ANSI colors to output.
-	TrackReferences     bool   // Track shared references to detect cycles.
-	HTMLtagToken        string // HTML span tag class used for syntax tokens.
-	HTMLtagSection      string // HTML span tag class used for value sections.
-	EmbedTypeMethods    bool   // Include exported methods from embedded types.
-	ShowMetaInformation bool   // Show metadata such as string lengths or slice capacities.
-	ShowHexdump         bool   // Show byte slices as hexdump when applicable.
-	IgnoreStringer      bool   // Ignores fmt.Stringer and error formatting if true
+	IndentWidth         int      // Number of spaces to use per indentation level.
+	MaxDepth            int      // Maximum levels of nested structures to print.
+	MaxItems            int      // Maximum number of items to print per slice/map.
+	MaxFields           int      // Maximum number of fields to print per struct (0 prints all).
+	MapSampleThreshold  int      // Show maps with more entries than this as an unsorted sample of MaxItems entries instead of sorting every key (0 disables).
+	MaxStringLen        int      // Maximum string length before truncation.
+	MaxInlineLength     int      // Maximum inline width before switching to block format.
+	MaxPaddingWidth     int      // Widest field, key or type column padded for alignment; longer ones are left unaligned (0 disables the cap).
+	NoAlignment         bool     // Skip the pass measuring keys and types of block structs, maps and slices; columns are left ragged, which is faster for large collections.
+	SingleLine          bool     // Render every value on a single line, as the `govar:"format=compact"` tag does for a field.
+	AdaptiveInline      bool     // Derive the inline width from the terminal width minus indentation (MaxInlineLength when unknown).
+	ShowTypes           bool     // Whether to show type names.
+	SmartTypes          bool     // With ShowTypes, omit types obvious from the value (plain bools, numbers, strings); named types, pointers and interfaces keep theirs.
+	UseColors           bool     // Whether to apply ANSI colors to output.
+	TrackReferences     bool     // Track shared references to detect cycles.
+	HTMLtagToken        string   // HTML span tag class used for syntax tokens.
+	HTMLtagSection      string   // HTML span tag class used for value sections.
+	EmbedTypeMethods    bool     // Include exported methods from embedded types.
+	ShowMetaInformation bool     // Show metadata such as string lengths or slice capacities.
+	HiddenMeta          MetaInfo // Kinds of metadata to omit even when ShowMetaInformation is enabled.
+	ShowIndirection     bool     // Prefix dereferenced values with their pointer indirection (e.g. **→42 for **int).
+	ShallowPointers     bool     // Summarize nested pointers to composite values on one line instead of recursing.
+	HTMLCollapseDepth   int      // In HTML output, hide block subtrees at this nesting level behind <details> toggles (0 disables).
+	HTMLDocument        bool     // Make SdumpHTML emit a complete HTML document (doctype, charset, stylesheet) ready to be saved as a file.
+	ShowHexdump         bool     // Show byte slices as hexdump when applicable.
+	MatrixGrid          bool     // Render [N][M] arrays of numbers as a right-aligned grid under a single column index header.
+	SparseArrays        bool     // Render mostly-zero numeric arrays/slices of 16+ elements as their non-zero elements plus a count of zeros.
+	IgnoreStringer      bool     // Ignores fmt.Stringer and error formatting if true
+	StringerWithFields  bool     // Show the exported-field structs behind Stringer/error text under that text, instead of the text alone.
+	ExpandErrorFields   bool     // Show the exported fields of concrete error types (status codes, causes) under the Error() message.
+	ExpandJoinedErrors  bool     // Render multi-errors (Unwrap() []error, e.g. errors.Join) as a list of their errors instead of one message.
+	ErrorStackFrames    int      // Show up to this many frames of an error's stack (StackTrace() or %+v output) under its message (0 disables).
+	ExpandHeavyTypes    bool     // Fully traverse known heavy stdlib types (testing.T, http.Transport, sql.DB, …) instead of summarizing them.
+	RainbowDepth        bool     // Tint braces, brackets and keys by nesting depth, cycling through a small palette.
+	ShowLegend          bool     // Print a legend of the colors and symbols used (strings, numbers, types, fields, references) after the dump.
+	HighlightPattern    string   // Highlight substrings matching this regular expression in colored output (see Dumper.WithHighlight).
+	ShowDumpCounters    bool     // Show a dump sequence number and the hit count of the call site in the header ("[>] govar.Dump #17 (3rd hit)").
+	SkipUnexported      bool     // Omit unexported struct fields entirely instead of marking them with 🞏.
+	ShowRenderStats     bool     // Show the number of rendered nodes and the analysis and render time in a footer (|rendered 12,401 nodes in 48ms|).
+	HeaderLinkTemplate  string   // Link the header's file:line to this URL ({path} and {line} are replaced), e.g. "vscode://file/{path}:{line}"; colored and HTML output only.
+	ShowExpressions     bool     // Label each top-level value with the source expression passed to the dump call (cfg.Timeout → …), parsed from the caller's file.
+	ShowMemoryUsage     bool     // Annotate structs and collections with their deep size and share of the dumped value (|312 KiB, 64%|).
+	ShowImplements      bool     // Annotate named structs with the interfaces they implement (|implements: shop.Pricer|), see SetInterfaceResolver.
+	Deterministic       bool     // Replace chan/func/pointer addresses with stable labels (#1) and fix the order of alike map keys, for golden files.
+	UseFormatter        bool     // Render fmt.Formatter implementations with %+v (after Stringer and error; follows the Stringer type lists).
+	UseTextMarshaler    bool     // Render encoding.TextMarshaler implementations (e.g. netip.Addr) as their text, after the above.
+	UseJSONMarshaler    bool     // Render json.Marshaler structs without exported fields as their pretty-printed JSON.
+	DieExitCode         int      // Exit status used by Die and DieOnPanic (0 selects 1).
+	CallGetters         bool     // Call accessor methods of structs (Len, Err, IsZero, …, plus GetterNames) and show their results after the fields.
+	RecoverRaces        bool     // Render collections whose reading panics (e.g. slices shrunk by another goroutine) as <concurrently modified> instead of crashing. Map races are fatal and not covered.
+	DieToStderr         bool     // Write the output of Die and DieOnPanic to stderr instead of stdout.
+	DedupeRepeats       bool     // Suppress Dump/Edump/Fdump output identical to the previous dump from the same call site, printing "… repeated ×N" when it changes or another call site dumps.
+	CopyToClipboard     bool     // Also copy the plain-text dump to the clipboard with an OSC 52 sequence when Dump/Edump/Fdump write to a terminal.
+	NaturalMapKeys      bool     // Sort string map keys numerically-aware ("item2" before "item10").
+	DistinguishNil      bool     // Render nil slices and maps as <nil slice>/<nil map>, distinct from empty ones.
+	SortMapsByValue     bool     // Order maps with numeric values largest first and string values alphabetically.
+	JSONLikeLayout      bool     // Render collections as JSON5-like literals (`key: value,`, braces for maps).
+	FullTypePaths       bool     // Show full import paths in type names (e.g. "github.com/acme/billing.Invoice").
+	ShortenTypePaths    bool     // Collapse import paths left in type names (e.g. generic type arguments) to their last element.
+	// PackageAliases maps import paths (or path prefixes) to short aliases used in
+	// type names, e.g. {"github.com/acme/repo/internal": "int"} renders
+	// "github.com/acme/repo/internal/foo.Bar" as "int/foo.Bar". The longest matching prefix wins.
+	PackageAliases map[string]string
+	// TypeColors maps type names (e.g. "domain.OrderID", or a full import path
+	// like "github.com/acme/domain.OrderID") or path.Match patterns ("domain.*")
+	// to colors (e.g. ColorPink); values of matching types and their type names
+	// are rendered entirely in that color.
+	TypeColors map[string]string
+	// IgnoreStringerTypes lists types (e.g. "main.Order" or "github.com/acme/shop.Order")
+	// whose Stringer/error formatting is ignored even when IgnoreStringer is false,
+	// so their fields are shown. Pointer types match their element type too.
+	IgnoreStringerTypes []string
+	// StringerTypes lists types that keep their Stringer/error formatting even when
+	// IgnoreStringer is true (e.g. "time.Duration", "time.Time").
+	StringerTypes []string
+	// GetterNames lists further methods called by CallGetters besides the
+	// built-in accessor names, e.g. "DebugString" or "GetOwner". Only call
+	// methods without side effects.
+	GetterNames []string
+	// MaskFieldPatterns lists shell-style patterns (e.g. "*password*", "*token")
+	// matched case-insensitively against struct field names; matching fields are
+	// masked like fields tagged `govar:"mask"`.
+	MaskFieldPatterns []string
+	// MaskStrategy is used for pattern matches and bare `govar:"mask"` tags;
+	// MaskNone selects MaskFull. Tags can pick their own, e.g. `govar:"mask=partial"`.
+	MaskStrategy MaskStrategy
+	// InterfaceTypes selects whether values stored in interfaces show the static
+	// type, the dynamic type, or both (the default, e.g. "⧉ any(*T)").
+	InterfaceTypes InterfaceTypeMode
+	// TypedNils selects how interfaces holding a nil value are rendered; the
+	// default is Go's "(*T)(nil)".
+	TypedNils TypedNilStyle
+	// Palette selects the terminal colors. The default, PaletteAuto, switches to
+	// LightPalette when the terminal reports a light background (see DetectPalette).
+	Palette PaletteMode
+	// Locale sets the decimal separator and digit grouping of rendered numbers and
+	// meta hints, e.g. LocaleGerman renders 1234.5 as "1.234,500000". The zero
+	// value keeps Go's formatting. Map keys and indices are not affected.
+	Locale NumberLocale
+	// Anonymize hides the contents of strings and numbers (including map keys and
+	// Stringer/error texts) while keeping structure, types, lengths, and references,
+	// so dumps can be shared without leaking data. Hexdumps are disabled in this mode.
+	Anonymize AnonymizeMode
 }
 
+// MetaInfo is a bit set selecting individual kinds of meta information
+// rendered when ShowMetaInformation is enabled.
+type MetaInfo uint
+
+const (
+	MetaStringLength  MetaInfo = 1 << iota // Rune counts of strings (|R:5|).
+	MetaListLength                         // Lengths and capacities of slices and arrays (|L:2 C:4|).
+	MetaMapSize                            // Number of entries in maps (|3|).
+	MetaChanBuffer                         // Buffer capacity of channels (|B:8|).
+	MetaFuncAddress                        // Entry addresses of functions (|func@0x…|).
+	MetaInterfaceHint                      // Stringer, error, Formatter and TextMarshaler hints (|Stringer:|, |error:|, …).
+	MetaListCapacity                       // Capacities of slices, when they differ from the length (the C:4 of |L:2 C:4|).
+
+	MetaAll = MetaStringLength | MetaListLength | MetaMapSize | MetaChanBuffer | MetaFuncAddress | MetaInterfaceHint | MetaListCapacity
+)
+
 // Dumper is a configurable structure-aware pretty printer for Go values.
+//
+// A Dumper can be reused for any number of dumps: every top-level call (Dump,
+// Sdump, Tree, ...) starts from a clean reference and cycle detection state.
+// It is not safe for concurrent use; give each goroutine its own Dumper.
 type Dumper struct {
 	config DumperConfig
 	Formatter
@@ -46,11 +160,37 @@ type Dumper struct {
 	canonicalRoots     map[canonicalKey]canonicalKey    // Union-find structure to group identical values.
 	primitiveInstances map[canonicalKey]any             // Stores instances of primitive values for unification.
 	definitionPoints   map[canonicalKey]definitionPoint // The chosen definition point for each ID.
+	candidatePoints    map[canonicalKey]definitionPoint // The best definition point seen for each raw key.
 	renderedIDs        map[canonicalKey]bool            // Tracks if an ID has already been printed.
 	fakeAddrs          map[any]uintptr                  // Assigns synthetic addresses to non-addressable primitives.
 	visitedForScan     map[canonicalKey]bool            // Tracks visited nodes for the pre-scan BFS.
+	sliceSpans         []sliceSpan                      // Memory reachable by each slice seen in the pre-scan.
+	sharedArrays       map[canonicalKey]sharedArray     // Slices sharing the backing array of another dumped slice.
+	// --- Layout State ---
+	forceInline   int                         // Greater than zero while rendering a field tagged `govar:"format=compact"`.
+	highlight     *regexp.Regexp              // Matches of this pattern are highlighted by ApplyFormat.
+	memoryTotal   int64                       // Deep size of the top-level value being rendered, for ShowMemoryUsage.
+	inlineLengths map[canonicalKey]int        // Memoized estimatedInlineLength results of addressable values.
+	typeNames     map[reflect.Type]string     // Memoized typeName results; they only depend on the config.
+	typeColors    map[reflect.Type]string     // Memoized typeColor results ("" for types without a color).
+	colorOverride string                      // TypeColors color replacing all others while a value of its type renders.
+	mapSamples    map[uintptr][]reflect.Value // Keys sampled from large maps, so every pass sees the same entries.
+	// --- HTML Path State ---
+	htmlPaths bool   // True while SdumpHTML renders, to attach data-govar-path attributes.
+	valuePath string // Path of the value being rendered, e.g. `Users[2].Name`.
+	// --- Comparison Overlay State ---
+	overlay *baselineDiff // Differences from the baseline while SdumpAgainst renders.
+	// --- Deterministic Mode State ---
+	addressLabels map[uintptr]string // Stable labels replacing addresses in Deterministic mode.
+	// --- Adaptive Layout State ---
+	termWidth int // Terminal width detected for AdaptiveInline (0 if unknown).
+	// --- Deep Rendering State ---
+	freshStackLevel int // Nesting level whose rendering was last moved to a fresh goroutine.
 	// --- Simple Cycle Detection State ---
-	visitedPointers map[unsafe.Pointer]bool // Used for basic cycle detection when TrackReferences is off.
+	visitedPointers map[canonicalKey]bool // Values on the current render path, for basic cycle detection when TrackReferences is off.
+	// --- Render Statistics State ---
+	renderedNodes int // Number of values rendered since the analysis, for ShowRenderStats.
+	statsStart    int // Output length before the ShowRenderStats footer, which DedupeRepeats ignores.
 }
 
 // NewDumper creates a new Dumper with the provided configuration.
@@ -63,67 +203,185 @@ func NewDumper(cfg DumperConfig) *Dumper {
 		canonicalRoots:     make(map[canonicalKey]canonicalKey),
 		primitiveInstances: make(map[canonicalKey]any),
 		definitionPoints:   make(map[canonicalKey]definitionPoint),
+		candidatePoints:    make(map[canonicalKey]definitionPoint),
 		renderedIDs:        make(map[canonicalKey]bool),
 		fakeAddrs:          make(map[any]uintptr),
 		visitedForScan:     make(map[canonicalKey]bool),
-		visitedPointers:    make(map[unsafe.Pointer]bool),
+		sharedArrays:       make(map[canonicalKey]sharedArray),
+		visitedPointers:    make(map[canonicalKey]bool),
+		addressLabels:      make(map[uintptr]string),
+		inlineLengths:      make(map[canonicalKey]int),
+		typeNames:          make(map[reflect.Type]string),
+		typeColors:         make(map[reflect.Type]string),
+		mapSamples:         make(map[uintptr][]reflect.Value),
+		highlight:          compileHighlight(cfg.HighlightPattern),
 	}
 }
 
-// Die dumps the given values and immediately terminates the program.
+// Die dumps the given values and immediately terminates the program with
+// the configured DieExitCode.
 func (d *Dumper) Die(vs ...any) {
-	d.Dump(vs...)
-	os.Exit(1)
+	if d.config.DieToStderr {
+		d.Edump(vs...)
+	} else {
+		d.Dump(vs...)
+	}
+	os.Exit(d.dieExitCode())
+}
+
+// DieOnPanic is meant to be deferred. If the surrounding function panics, it
+// recovers, dumps the panic value followed by the stack trace, and terminates
+// the program with the configured DieExitCode. Otherwise it does nothing.
+func (d *Dumper) DieOnPanic() {
+	if r := recover(); r != nil {
+		d.dieWithPanic(r, debug.Stack())
+	}
+}
+
+// dieWithPanic renders a recovered panic value and its stack, then exits.
+// The panic header replaces the usual call-site header, which would point
+// into the runtime's panic machinery.
+func (d *Dumper) dieWithPanic(r any, stack []byte) {
+	d.Formatter = d.textFormatter(d.config.UseColors)
+	sb := &strings.Builder{}
+	fmt.Fprintln(sb, d.ApplyFormat(ColorRed, "[!] panic"))
+	d.renderAllValues(sb, r)
+	sb.WriteString("\n")
+	sb.WriteString(d.ApplyFormat(ColorSlateGray, strings.TrimRight(string(stack), "\n")))
+
+	w := stdoutWriter()
+	if d.config.DieToStderr {
+		w = stderrWriter()
+	}
+	fmt.Fprintln(w, sb.String())
+	os.Exit(d.dieExitCode())
+}
+
+// dieExitCode returns the configured exit status, defaulting to 1.
+func (d *Dumper) dieExitCode() int {
+	if d.config.DieExitCode == 0 {
+		return 1
+	}
+	return d.config.DieExitCode
 }
 
 // Dump prints values to stdout using the configured formatting.
 func (d *Dumper) Dump(vs ...any) {
-	if d.config.UseColors {
-		d.Formatter = &ANSIcolorFormatter{}
-	} else {
-		d.Formatter = &PlainFormatter{}
-	}
+	d.Formatter = d.textFormatter(d.config.UseColors)
 	sb := &strings.Builder{}
 	d.renderHeader(sb)
+	headerLen := sb.Len()
 	d.renderAllValues(sb, vs...)
-	fmt.Fprintln(os.Stdout, sb.String())
+	repeated, suppressed := d.dedupeRepeat(sb.String()[headerLen:d.statsStart])
+	w := stdoutWriter()
+	fmt.Fprint(w, repeated)
+	if suppressed {
+		return
+	}
+	fmt.Fprintln(w, sb.String())
+	d.copyToClipboard(w, vs)
+}
+
+// Edump prints values to stderr using the configured formatting.
+func (d *Dumper) Edump(vs ...any) {
+	d.Fdump(stderrWriter(), vs...)
 }
 
 // Fdump writes values to the given io.Writer using the configured formatting.
 func (d *Dumper) Fdump(w io.Writer, vs ...any) {
-	if d.config.UseColors {
-		d.Formatter = &ANSIcolorFormatter{}
-	} else {
-		d.Formatter = &PlainFormatter{}
-	}
+	d.Formatter = d.textFormatter(d.config.UseColors)
 	sb := &strings.Builder{}
 	d.renderHeader(sb)
+	headerLen := sb.Len()
 	d.renderAllValues(sb, vs...)
+	repeated, suppressed := d.dedupeRepeat(sb.String()[headerLen:d.statsStart])
+	fmt.Fprint(w, repeated)
+	if suppressed {
+		return
+	}
 	fmt.Fprintln(w, sb.String())
+	d.copyToClipboard(w, vs)
 }
 
 // Sdump returns a string containing the formatted values.
 func (d *Dumper) Sdump(vs ...any) string {
-	if d.config.UseColors {
-		d.Formatter = &ANSIcolorFormatter{}
-	} else {
-		d.Formatter = &PlainFormatter{}
-	}
+	d.Formatter = d.textFormatter(d.config.UseColors)
 	sb := &strings.Builder{}
 	d.renderHeader(sb)
 	d.renderAllValues(sb, vs...)
 	return sb.String()
 }
 
-// SdumpHTML returns an HTML-formatted dump wrapped in a <pre> block.
+// DumpValue prints values given as reflect.Values to stdout, like Dump. The
+// values are not round-tripped through interface{}, so addressable values keep
+// their identity for reference tracking and values reached through unexported
+// fields can be dumped too. (Not to be confused with the package-level
+// DumpValues, which omits types.)
+func (d *Dumper) DumpValue(vs ...reflect.Value) {
+	fmt.Fprintln(stdoutWriter(), d.SdumpValue(vs...))
+}
+
+// FdumpValue writes values given as reflect.Values to the given io.Writer, like Fdump.
+func (d *Dumper) FdumpValue(w io.Writer, vs ...reflect.Value) {
+	fmt.Fprintln(w, d.SdumpValue(vs...))
+}
+
+// SdumpValue returns a string containing the formatted reflect.Values, like Sdump.
+func (d *Dumper) SdumpValue(vs ...reflect.Value) string {
+	d.Formatter = d.textFormatter(d.config.UseColors)
+	sb := &strings.Builder{}
+	d.renderHeader(sb)
+	d.renderReflectValues(sb, vs)
+	return sb.String()
+}
+
+// htmlDocumentHead and htmlDocumentTail wrap SdumpHTML output into a complete
+// HTML document when HTMLDocument is enabled.
+const (
+	htmlDocumentHead = `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>govar dump</title>
+<style>
+body { margin: 0; padding: 16px; background-color: #1e1e1e; }
+.govar { font-family: ui-monospace, SFMono-Regular, Menlo, Consolas, monospace; font-size: 13px; line-height: 1.4; white-space: pre; overflow-x: auto; }
+</style>
+</head>
+<body>
+`
+	htmlDocumentTail = `
+</body>
+</html>
+`
+)
+
+// SdumpHTML returns an HTML-formatted dump wrapped in a <pre> block, or in a
+// complete HTML document when HTMLDocument is enabled.
 func (d *Dumper) SdumpHTML(vs ...any) string {
+	return d.sdumpHTML(true, vs...)
+}
+
+// sdumpHTML implements SdumpHTML; withHeader selects whether the call site
+// header is rendered.
+func (d *Dumper) sdumpHTML(withHeader bool, vs ...any) string {
 	d.Formatter = &HTMLformatter{HTMLtagToken: d.config.HTMLtagToken, UseColors: d.config.UseColors}
+	d.htmlPaths = true
+	defer func() { d.htmlPaths = false }()
 
 	sb := &strings.Builder{}
+	if d.config.HTMLDocument {
+		sb.WriteString(htmlDocumentHead)
+	}
 	sb.WriteString(fmt.Sprintf(`<%s class="govar" style="background-color:black; color:white; padding:4px; border-radius: 4px">`+"\n", d.config.HTMLtagSection))
-	d.renderHeader(sb)
+	if withHeader {
+		d.renderHeader(sb)
+	}
 	d.renderAllValues(sb, vs...)
 	sb.WriteString(fmt.Sprintf("</%s>", d.config.HTMLtagSection))
+	if d.config.HTMLDocument {
+		sb.WriteString(htmlDocumentTail)
+	}
 	return sb.String()
 }
 
@@ -140,7 +398,7 @@ func (d *Dumper) asStringerInterface(v reflect.Value) string {
 			if rv.Kind() == reflect.Ptr && rv.IsNil() {
 				return d.ApplyFormat(ColorCoralRed, "<nil>")
 			}
-			str := d.stringEscape(s.String())
+			str := d.stringEscape(d.anonymizeText(s.String()))
 			str = d.ApplyFormat(ColorGoldenrod, `"`) + d.ApplyFormat(ColorLime, str) + d.ApplyFormat(ColorGoldenrod, `"`)
 			return str
 		}
@@ -161,7 +419,7 @@ func (d *Dumper) asErrorInterface(v reflect.Value) string {
 			if rv.Kind() == reflect.Ptr && rv.IsNil() {
 				return d.ApplyFormat(ColorCoralRed, "<nil>")
 			}
-			str := d.stringEscape(e.Error())
+			str := d.stringEscape(d.anonymizeText(e.Error()))
 			str = d.ApplyFormat(ColorGoldenrod, `"`) + d.ApplyFormat(ColorCoralRed, str) + d.ApplyFormat(ColorGoldenrod, `"`)
 			return str
 		}
@@ -169,47 +427,143 @@ func (d *Dumper) asErrorInterface(v reflect.Value) string {
 	return ""
 }
 
+// asFormatterInterface checks if a value implements fmt.Formatter and the
+// UseFormatter option is enabled. If so, it returns the value formatted with
+// %+v. Otherwise, it returns an empty string.
+func (d *Dumper) asFormatterInterface(v reflect.Value) string {
+	if !d.config.UseFormatter {
+		return ""
+	}
+	val := v
+	if !val.CanInterface() {
+		val = tryExport(val)
+	}
+	if val.CanInterface() {
+		if f, ok := val.Interface().(fmt.Formatter); ok {
+			rv := reflect.ValueOf(f)
+			if rv.Kind() == reflect.Ptr && rv.IsNil() {
+				return d.ApplyFormat(ColorCoralRed, "<nil>")
+			}
+			str := d.stringEscape(d.anonymizeText(fmt.Sprintf("%+v", f)))
+			str = d.ApplyFormat(ColorGoldenrod, `"`) + d.ApplyFormat(ColorLime, str) + d.ApplyFormat(ColorGoldenrod, `"`)
+			return str
+		}
+	}
+	return ""
+}
+
+// asTextMarshalerInterface checks if a value implements encoding.TextMarshaler
+// and the UseTextMarshaler option is enabled. If so, it returns the marshaled
+// text. Otherwise, or if marshaling fails, it returns an empty string.
+func (d *Dumper) asTextMarshalerInterface(v reflect.Value) string {
+	if !d.config.UseTextMarshaler {
+		return ""
+	}
+	val := v
+	if !val.CanInterface() {
+		val = tryExport(val)
+	}
+	if val.CanInterface() {
+		if m, ok := val.Interface().(encoding.TextMarshaler); ok {
+			rv := reflect.ValueOf(m)
+			if rv.Kind() == reflect.Ptr && rv.IsNil() {
+				return d.ApplyFormat(ColorCoralRed, "<nil>")
+			}
+			text, err := m.MarshalText()
+			if err != nil {
+				return ""
+			}
+			str := d.stringEscape(d.anonymizeText(string(text)))
+			str = d.ApplyFormat(ColorGoldenrod, `"`) + d.ApplyFormat(ColorLime, str) + d.ApplyFormat(ColorGoldenrod, `"`)
+			return str
+		}
+	}
+	return ""
+}
+
+// asJSONMarshalerInterface checks if a value implements json.Marshaler, the
+// UseJSONMarshaler option is enabled, and structural rendering would only show
+// unexported internals (a struct without exported fields). If so, it returns
+// the JSON indented for the given nesting level. Otherwise, or if marshaling
+// fails, it returns an empty string. Under Anonymize the JSON is not used, as
+// its strings and numbers cannot be anonymized.
+func (d *Dumper) asJSONMarshalerInterface(v reflect.Value, level int) string {
+	if !d.config.UseJSONMarshaler || d.config.Anonymize != AnonymizeOff || !hasOnlyUnexportedFields(v) {
+		return ""
+	}
+	val := v
+	if !val.CanInterface() {
+		val = tryExport(val)
+	}
+	if val.CanInterface() {
+		if m, ok := val.Interface().(json.Marshaler); ok {
+			data, err := m.MarshalJSON()
+			if err != nil {
+				return ""
+			}
+			pretty := &bytes.Buffer{}
+			indent := strings.Repeat(" ", d.config.IndentWidth)
+			if err := json.Indent(pretty, data, strings.Repeat(indent, level), indent); err != nil {
+				return ""
+			}
+			return d.ApplyFormat(ColorLime, pretty.String())
+		}
+	}
+	return ""
+}
+
 // calculateStructPadding determines the maximum key and type string lengths for
 // fields within a struct to align them neatly in block mode.
 func (d *Dumper) calculateStructPadding(v reflect.Value) (int, int) {
 	maxKeyLen, maxTypeLen := 0, 0
+	if d.config.NoAlignment {
+		return maxKeyLen, maxTypeLen
+	}
 	t := v.Type()
-	for i := 0; i < t.NumField(); i++ {
+	fields, _ := d.shownFields(t)
+	for _, i := range fields {
 		field, fieldVal := t.Field(i), v.Field(i)
 		if field.PkgPath != "" {
 			fieldVal = tryExport(fieldVal)
 		}
-		keyLen := utf8.RuneCountInString(field.Name)
-		if keyLen > maxKeyLen {
-			maxKeyLen = keyLen
-		}
-		typeName := d.formatTypeNoColors(fieldVal, false)
-		if utf8.RuneCountInString(typeName) > maxTypeLen {
-			maxTypeLen = utf8.RuneCountInString(typeName)
-		}
+		maxKeyLen = d.widenPadding(maxKeyLen, utf8.RuneCountInString(field.Name)+2) // +2 for visibility symbol
+		maxTypeLen = d.widenPadding(maxTypeLen, utf8.RuneCountInString(d.formatTypeNoColors(fieldVal, false)))
 	}
 	if d.config.EmbedTypeMethods {
 		for _, m := range findTypeMethods(t) {
-			if utf8.RuneCountInString(m.Name) > maxKeyLen {
-				maxKeyLen = utf8.RuneCountInString(m.Name)
-			}
+			maxKeyLen = d.widenPadding(maxKeyLen, utf8.RuneCountInString(m.Name)+2)
 		}
 	}
-	maxKeyLen += 2 // for visibility symbol
 	return maxKeyLen, maxTypeLen
 }
 
 // estimatedInlineLength calculates the approximate string length of a value if it
 // were to be rendered on a single line. This is used as a heuristic to decide
-// whether to use inline or block formatting.
+// whether to use inline or block formatting. Estimates of addressable values
+// are memoized for the current render pass, as every nesting level asks again
+// for the values below it.
 func (d *Dumper) estimatedInlineLength(v reflect.Value) int {
+	if !v.CanAddr() {
+		return d.measureInlineLength(v)
+	}
+	key := canonicalKey{addr: v.UnsafeAddr(), typ: v.Type()}
+	if length, ok := d.inlineLengths[key]; ok {
+		return length
+	}
+	length := d.measureInlineLength(v)
+	d.inlineLengths[key] = length
+	return length
+}
+
+// measureInlineLength computes the estimate returned by estimatedInlineLength.
+func (d *Dumper) measureInlineLength(v reflect.Value) int {
 	length := 0
 	switch v.Kind() {
 	case reflect.String:
 		strVal := v.String()
 		runeCount := utf8.RuneCountInString(strVal)
 		length += runeCount + 2
-		if d.config.ShowMetaInformation {
+		if d.showMeta(MetaStringLength) {
 			meta := fmt.Sprintf(" |R:%d|", runeCount)
 			length += len(meta)
 		}
@@ -227,8 +581,8 @@ func (d *Dumper) estimatedInlineLength(v reflect.Value) int {
 		return length + 5
 	case reflect.Array, reflect.Slice:
 		length += 2 // braces
-		if d.config.ShowMetaInformation {
-			if v.Kind() == reflect.Slice && v.Len() != v.Cap() {
+		if d.showMeta(MetaListLength) {
+			if v.Kind() == reflect.Slice && v.Len() != v.Cap() && d.showMeta(MetaListCapacity) {
 				length += len(fmt.Sprintf("|L:%d C:%d| ", v.Len(), v.Cap()))
 			} else {
 				length += len(fmt.Sprintf("|%d| ", v.Len()))
@@ -245,7 +599,7 @@ func (d *Dumper) estimatedInlineLength(v reflect.Value) int {
 
 	case reflect.Map:
 		length += 2 // braces
-		if d.config.ShowMetaInformation {
+		if d.showMeta(MetaMapSize) {
 			length += len(fmt.Sprintf("|%d| ", v.Len()))
 		}
 		for i, key := range v.MapKeys() {
@@ -256,7 +610,7 @@ func (d *Dumper) estimatedInlineLength(v reflect.Value) int {
 
 			length += d.estimatedInlineLength(key) + 4 + d.estimatedInlineLength(val) // key => val
 			if d.config.ShowTypes {
-				length += len(val.Type().String()) + 1 // type len + whitespace
+				length += len(d.typeName(val.Type())) + 1 // type len + whitespace
 			}
 		}
 		return length
@@ -264,14 +618,19 @@ func (d *Dumper) estimatedInlineLength(v reflect.Value) int {
 	case reflect.Struct:
 		length += 2 // braces
 		t := v.Type()
+		shown := 0
 		for i := range v.NumField() {
-			if i > 0 {
+			if d.skipsField(t.Field(i)) {
+				continue
+			}
+			if shown > 0 {
 				length += 2 // comma and space
 			}
+			shown++
 			name := t.Field(i).Name
 			length += 2 + len(name) + 4 + d.estimatedInlineLength(v.Field(i)) // Indicator Name => val
 			if d.config.ShowTypes {
-				length += len(v.Field(i).Type().String()) + 1 // type len + whitespace
+				length += len(d.typeName(v.Field(i).Type())) + 1 // type len + whitespace
 			}
 		}
 		return length
@@ -283,25 +642,32 @@ func (d *Dumper) estimatedInlineLength(v reflect.Value) int {
 
 // formatArrayOrSlice formats a slice or an array, deciding between inline and block rendering.
 func (d *Dumper) formatArrayOrSlice(v reflect.Value, level int) string {
+	if d.isMatrix(v) {
+		return d.formatMatrix(v, level)
+	}
 	sb := &strings.Builder{}
+	sb.WriteString(d.sharedArrayNote(v))
 
-	if d.config.ShowMetaInformation {
+	if d.showMeta(MetaListLength) {
 		var listLen string
 		if v.Kind() == reflect.Array {
-			listLen = fmt.Sprintf("%d", v.Len())
+			listLen = d.localizeInt(v.Len())
 		} else {
-			if v.Len() == v.Cap() {
-				listLen = fmt.Sprintf("%d", v.Len())
+			if v.Len() == v.Cap() || !d.showMeta(MetaListCapacity) {
+				listLen = d.localizeInt(v.Len())
 			} else {
-				listLen = fmt.Sprintf("L:%d C:%d", v.Len(), v.Cap())
+				listLen = "L:" + d.localizeInt(v.Len()) + " C:" + d.localizeInt(v.Cap())
 			}
 		}
 		fmt.Fprint(sb, d.metaHint(listLen, ""))
 	}
 
-	fmt.Fprint(sb, "[")
+	fmt.Fprint(sb, d.delim("[", level))
 
-	if d.shouldRenderInline(v) {
+	if d.isSparse(v) {
+		// SPARSE RENDER
+		d.renderSparse(sb, v, level)
+	} else if d.shouldRenderInline(v, level) {
 		// INLINE RENDER
 		for i := range v.Len() {
 			if i >= d.config.MaxItems {
@@ -311,28 +677,34 @@ func (d *Dumper) formatArrayOrSlice(v reflect.Value, level int) string {
 			if i > 0 {
 				fmt.Fprint(sb, ", ")
 			}
+			step := indexPathStep(i)
 			formattedType := d.formatType(v.Index(i), true)
-			indexSymbol := d.ApplyFormat(ColorDarkTeal, fmt.Sprintf("%d", i))
-
-			fmt.Fprintf(sb, "%s%s => ", indexSymbol, formattedType)
+			if d.config.JSONLikeLayout {
+				if formattedType != "" {
+					fmt.Fprint(sb, formattedType, " ")
+				}
+			} else {
+				indexSymbol := d.formatLabel(step, d.depthColor(level, ColorDarkTeal), fmt.Sprintf("%d", i))
+				fmt.Fprintf(sb, "%s%s => ", indexSymbol, formattedType)
+			}
+			leave := d.enterPath(step)
 			d.renderValue(sb, v.Index(i), level, false)
+			leave()
 		}
+		d.renderMissingEntries(sb, level, true, v.Len() == 0)
 
 	} else {
 		// BLOCK RENDER
 		fmt.Fprintln(sb)
-		if d.config.ShowHexdump && v.Type().Elem().Kind() == reflect.Uint8 {
+		if d.config.ShowHexdump && d.config.Anonymize == AnonymizeOff && v.Type().Elem().Kind() == reflect.Uint8 {
 			d.renderHexdump(sb, v, level)
 		} else {
 			maxTypeLen := 0
 			for i := range v.Len() {
-				if i >= d.config.MaxItems {
+				if i >= d.config.MaxItems || d.config.NoAlignment {
 					break
 				}
-				typeName := d.formatTypeNoColors(v.Index(i), true)
-				if utf8.RuneCountInString(typeName) > maxTypeLen {
-					maxTypeLen = utf8.RuneCountInString(typeName)
-				}
+				maxTypeLen = d.widenPadding(maxTypeLen, utf8.RuneCountInString(d.formatTypeNoColors(v.Index(i), true)))
 			}
 
 			for i := range v.Len() {
@@ -340,11 +712,16 @@ func (d *Dumper) formatArrayOrSlice(v reflect.Value, level int) string {
 					d.renderIndent(sb, level+1, d.ApplyFormat(ColorSlateGray, "… (truncated)\n"))
 					break
 				}
+				step := indexPathStep(i)
 				formattedType := d.formatType(v.Index(i), true)
-				indexSymbol := d.ApplyFormat(ColorDarkTeal, fmt.Sprintf("%d", i))
+				indexSymbol := d.formatLabel(step, d.depthColor(level, ColorDarkTeal), fmt.Sprintf("%d", i))
 
 				renderIndex := ""
-				if formattedType != "" {
+				if d.config.JSONLikeLayout {
+					if formattedType != "" {
+						renderIndex = formattedType + " "
+					}
+				} else if formattedType != "" {
 					unformattedTypeLen := utf8.RuneCountInString(d.formatTypeNoColors(v.Index(i), true))
 					paddedType := padRight(formattedType, unformattedTypeLen, maxTypeLen)
 					renderIndex = fmt.Sprintf("%s %s => ", indexSymbol, paddedType)
@@ -352,14 +729,17 @@ func (d *Dumper) formatArrayOrSlice(v reflect.Value, level int) string {
 					renderIndex = fmt.Sprintf("%s => ", indexSymbol)
 				}
 				d.renderIndent(sb, level+1, renderIndex)
+				leave := d.enterPath(step)
 				d.renderValue(sb, v.Index(i), level+1, false)
-				fmt.Fprintln(sb)
+				leave()
+				d.renderBlockLineEnd(sb)
 			}
+			d.renderMissingEntries(sb, level, false, false)
 		}
 		d.renderIndent(sb, level, "")
 	}
 
-	fmt.Fprint(sb, "]")
+	fmt.Fprint(sb, d.delim("]", level))
 
 	return sb.String()
 }
@@ -387,10 +767,10 @@ func (d *Dumper) formatChan(v reflect.Value) string {
 			symbol = d.ApplyFormat(ColorGreen, "🢃")
 		}
 		result := ""
-		if d.config.ShowMetaInformation {
-			result = fmt.Sprint(d.metaHint(fmt.Sprintf("B:%d", v.Cap()), ""))
+		if d.showMeta(MetaChanBuffer) {
+			result = fmt.Sprint(d.metaHint("B:"+d.localizeInt(v.Cap()), ""))
 		}
-		result = result + fmt.Sprintf("%s %s%s", symbol, d.ApplyFormat(ColorPink, "chan@"), d.ApplyFormat(ColorLightTeal, fmt.Sprintf("%#x", v.Pointer())))
+		result = result + fmt.Sprintf("%s %s%s", symbol, d.ApplyFormat(ColorPink, "chan@"), d.ApplyFormat(ColorLightTeal, d.formatAddress(v.Pointer())))
 		return result
 	}
 }
@@ -398,8 +778,8 @@ func (d *Dumper) formatChan(v reflect.Value) string {
 // formatFunc formats a function, showing its name and pointer address.
 func (d *Dumper) formatFunc(v reflect.Value) string {
 	funName := d.ApplyFormat(ColorLightTeal, getFunctionName(v))
-	if d.config.ShowMetaInformation {
-		funName = fmt.Sprint(d.metaHint(fmt.Sprintf("func@%#x", v.Pointer()), "")) + funName
+	if d.showMeta(MetaFuncAddress) {
+		funName = fmt.Sprint(d.metaHint("func@"+d.formatAddress(v.Pointer()), "")) + funName
 	}
 	return funName
 }
@@ -408,15 +788,19 @@ func (d *Dumper) formatFunc(v reflect.Value) string {
 func (d *Dumper) formatMap(v reflect.Value, level int) string {
 	sb := &strings.Builder{}
 
-	if d.config.ShowMetaInformation {
-		mapLen := fmt.Sprintf("%d", v.Len())
+	if d.showMeta(MetaMapSize) {
+		mapLen := d.localizeInt(v.Len())
 		fmt.Fprint(sb, d.metaHint(mapLen, ""))
 	}
 
-	sortedKeys := sortMapKeys(v)
-	fmt.Fprint(sb, "[")
+	sortedKeys := d.sortedMapKeys(v)
+	openBrace, closeBrace := "[", "]"
+	if d.config.JSONLikeLayout {
+		openBrace, closeBrace = "{", "}"
+	}
+	fmt.Fprint(sb, d.delim(openBrace, level))
 
-	if d.shouldRenderInline(v) {
+	if d.shouldRenderInline(v, level) {
 		// INLINE RENDER
 		for i, key := range sortedKeys {
 			if i >= d.config.MaxItems {
@@ -426,10 +810,24 @@ func (d *Dumper) formatMap(v reflect.Value, level int) string {
 			if i > 0 {
 				fmt.Fprint(sb, ", ")
 			}
-			keyStr := d.formatMapKeyAsIndex(key)
+			step := mapKeyPathStep(key)
+			keyStr := d.formatLabel(step, d.depthColor(level, ColorDarkTeal), d.formatMapKeyAsIndex(key))
 			formattedType := d.formatType(v.MapIndex(key), true)
-			fmt.Fprintf(sb, "%s %s => ", d.ApplyFormat(ColorDarkTeal, keyStr), formattedType)
+			if d.config.JSONLikeLayout {
+				fmt.Fprintf(sb, "%s: ", keyStr)
+				if formattedType != "" {
+					fmt.Fprint(sb, formattedType, " ")
+				}
+			} else {
+				fmt.Fprintf(sb, "%s %s => ", keyStr, formattedType)
+			}
+			leave := d.enterPath(step)
 			d.renderValue(sb, v.MapIndex(key), level, false)
+			leave()
+		}
+		d.renderMissingEntries(sb, level, true, len(sortedKeys) == 0)
+		if d.isSampledMap(v) {
+			fmt.Fprint(sb, ", ", d.ApplyFormat(ColorSlateGray, sampleMarker(v, sortedKeys)))
 		}
 	} else {
 		// BLOCK RENDER
@@ -437,17 +835,14 @@ func (d *Dumper) formatMap(v reflect.Value, level int) string {
 		maxKeyLen := 0
 		maxTypeLen := 0
 		for i, key := range sortedKeys {
-			if i >= d.config.MaxItems {
+			if i >= d.config.MaxItems || d.config.NoAlignment {
 				break
 			}
-			keyStr := d.formatMapKeyAsIndex(key)
-			if utf8.RuneCountInString(keyStr) > maxKeyLen {
-				maxKeyLen = utf8.RuneCountInString(keyStr)
-			}
-			typeName := d.formatTypeNoColors(v.MapIndex(key), true)
-			if utf8.RuneCountInString(typeName) > maxTypeLen {
-				maxTypeLen = utf8.RuneCountInString(typeName)
+			if d.isBlockMapKey(key) {
+				continue
 			}
+			maxKeyLen = d.widenPadding(maxKeyLen, utf8.RuneCountInString(d.formatMapKeyAsIndex(key)))
+			maxTypeLen = d.widenPadding(maxTypeLen, utf8.RuneCountInString(d.formatTypeNoColors(v.MapIndex(key), true)))
 		}
 
 		for i, key := range sortedKeys {
@@ -455,31 +850,73 @@ func (d *Dumper) formatMap(v reflect.Value, level int) string {
 				d.renderIndent(sb, level+1, d.ApplyFormat(ColorSlateGray, "… (truncated)\n"))
 				break
 			}
+			step := mapKeyPathStep(key)
 			keyStr := d.formatMapKeyAsIndex(key)
 			formattedType := d.formatType(v.MapIndex(key), true)
 			keyRender := ""
-			if formattedType != "" {
-				unformattedTypeLen := utf8.RuneCountInString(d.formatTypeNoColors(v.MapIndex(key), true))
+			if d.isBlockMapKey(key) {
+				// Keys too large for a line are rendered like values, as blocks.
+				d.renderIndent(sb, level+1, "")
+				if keyType := d.formatType(key, true); keyType != "" {
+					fmt.Fprint(sb, keyType, " ")
+				}
+				d.renderValue(sb, key, level+1, true)
+				if formattedType != "" {
+					formattedType = " " + formattedType
+				}
+				fmt.Fprintf(sb, "%s => ", formattedType)
+				leave := d.enterPath(step)
+				d.renderValue(sb, v.MapIndex(key), level+1, false)
+				leave()
+				d.renderBlockLineEnd(sb)
+				continue
+			}
+			if d.config.JSONLikeLayout {
+				keyRender = d.formatLabel(step, d.depthColor(level, ColorDarkTeal), keyStr) + ": "
+				if formattedType != "" {
+					keyRender += formattedType + " "
+				}
+			} else if d.config.ShowTypes {
 				paddedKey := padRight(keyStr, utf8.RuneCountInString(keyStr), maxKeyLen)
-				paddedType := padRight(formattedType, unformattedTypeLen, maxTypeLen)
-				keyRender = fmt.Sprintf("%s  %s => ", d.ApplyFormat(ColorDarkTeal, paddedKey), paddedType)
+				keyRender = d.formatLabel(step, d.depthColor(level, ColorDarkTeal), paddedKey)
+				if formattedType != "" {
+					unformattedTypeLen := utf8.RuneCountInString(d.formatTypeNoColors(v.MapIndex(key), true))
+					keyRender += "  " + padRight(formattedType, unformattedTypeLen, maxTypeLen)
+				}
+				keyRender += " => "
 			} else {
-				keyRender = fmt.Sprintf("%s => ", keyStr)
+				keyRender = fmt.Sprintf("%s => ", d.formatLabel(step, "", keyStr))
 			}
 			d.renderIndent(sb, level+1, keyRender)
+			leave := d.enterPath(step)
 			d.renderValue(sb, v.MapIndex(key), level+1, false)
-			fmt.Fprintln(sb)
+			leave()
+			d.renderBlockLineEnd(sb)
+		}
+		d.renderMissingEntries(sb, level, false, false)
+		if d.isSampledMap(v) {
+			d.renderIndent(sb, level+1, d.ApplyFormat(ColorSlateGray, sampleMarker(v, sortedKeys))+"\n")
 		}
 		d.renderIndent(sb, level, "")
 	}
 
-	fmt.Fprint(sb, "]")
+	fmt.Fprint(sb, d.delim(closeBrace, level))
 	return sb.String()
 }
 
 // formatMapKeyAsIndex formats a map key for display. Simple keys are formatted
 // directly, while complex keys are summarized.
 func (d *Dumper) formatMapKeyAsIndex(k reflect.Value) string {
+	if d.config.Anonymize != AnonymizeOff {
+		return d.formatAnonymizedMapKey(k)
+	}
+	if isCompositeKey(k) {
+		summary := d.summarizeKey(k, 0)
+		if utf8.RuneCountInString(summary) > d.config.MaxInlineLength {
+			summary = string([]rune(summary)[:max(d.config.MaxInlineLength-1, 0)]) + "…"
+		}
+		return summary
+	}
 	// First, check if the key can be interfaced. This is the crucial fix
 	// to prevent the panic with unexported map keys
 	exportedKey := tryExport(k)
@@ -499,7 +936,7 @@ func (d *Dumper) formatMapKeyAsIndex(k reflect.Value) string {
 			return strconv.FormatBool(k.Bool())
 		default:
 			// The ultimate safe fallback for complex, unhandled types.
-			return fmt.Sprintf("<%s>", d.ApplyFormat(ColorSlateGray, k.Type().String()))
+			return fmt.Sprintf("<%s>", d.ApplyFormat(ColorSlateGray, d.typeName(k.Type())))
 		}
 	}
 
@@ -531,14 +968,197 @@ func (d *Dumper) formatMapKeyAsIndex(k reflect.Value) string {
 	return keyFormatted
 }
 
+// maxKeySummaryDepth bounds the nesting of structs, arrays and pointers shown
+// in the summary of a composite map key.
+const maxKeySummaryDepth = 3
+
+// isCompositeKey reports whether the map key k (or the value it holds, for
+// interface keys) is a struct, array or pointer, which formatMapKeyAsIndex
+// summarizes.
+func isCompositeKey(k reflect.Value) bool {
+	if k.Kind() == reflect.Interface && !k.IsNil() {
+		k = k.Elem()
+	}
+	switch k.Kind() {
+	case reflect.Struct, reflect.Array, reflect.Ptr:
+		return true
+	}
+	return false
+}
+
+// isBlockMapKey reports whether the map key k is too large for a single line
+// and is rendered as a block of its own in block-rendered maps.
+func (d *Dumper) isBlockMapKey(k reflect.Value) bool {
+	return !d.config.JSONLikeLayout && d.config.Anonymize == AnonymizeOff && isCompositeKey(k) &&
+		utf8.RuneCountInString(d.summarizeKey(k, 0)) > d.config.MaxInlineLength
+}
+
+// summarizeKey formats a map key on a single line: structs with their field
+// names ({Lat: 50.1, Lon: 14.4}), arrays as [a, b], pointers as &{…}, and
+// values implementing Stringer or error as their text. It only uses accessors
+// that work for unexported values too.
+func (d *Dumper) summarizeKey(v reflect.Value, depth int) string {
+	if v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return "nil"
+		}
+		v = v.Elem()
+	}
+	if v.Kind() == reflect.Ptr && v.IsNil() {
+		return "nil"
+	}
+	if ev := tryExport(v); ev.CanInterface() && d.useStringer(v.Type()) {
+		switch t := ev.Interface().(type) {
+		case error, fmt.Stringer:
+			return fmt.Sprint(t) // Recovers from panicking Error and String methods.
+		}
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		return strconv.Quote(v.String())
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return strconv.FormatUint(v.Uint(), 10)
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'g', -1, v.Type().Bits())
+	case reflect.Complex64, reflect.Complex128:
+		return fmt.Sprint(v.Complex())
+	case reflect.Chan, reflect.Func, reflect.UnsafePointer:
+		if v.IsNil() {
+			return "nil"
+		}
+		return d.formatAddress(v.Pointer())
+	case reflect.Ptr:
+		if depth >= maxKeySummaryDepth {
+			return "&…"
+		}
+		return "&" + d.summarizeKey(v.Elem(), depth+1)
+	case reflect.Struct:
+		if depth >= maxKeySummaryDepth {
+			return "{…}"
+		}
+		parts := make([]string, 0, v.NumField())
+		for i := range v.NumField() {
+			if d.skipsField(v.Type().Field(i)) {
+				continue
+			}
+			parts = append(parts, v.Type().Field(i).Name+": "+d.summarizeKey(v.Field(i), depth+1))
+		}
+		return "{" + strings.Join(parts, ", ") + "}"
+	case reflect.Array:
+		if depth >= maxKeySummaryDepth {
+			return "[…]"
+		}
+		parts := make([]string, v.Len())
+		for i := range v.Len() {
+			parts[i] = d.summarizeKey(v.Index(i), depth+1)
+		}
+		return "[" + strings.Join(parts, ", ") + "]"
+	}
+	return "<" + d.typeName(v.Type()) + ">"
+}
+
+// useStringer reports whether Stringer, error, and the other interface-based
+// representations apply to values of type t, honoring IgnoreStringer and the
+// per-type IgnoreStringerTypes and StringerTypes lists.
+func (d *Dumper) useStringer(t reflect.Type) bool {
+	if d.config.IgnoreStringer {
+		return matchesTypeList(t, d.config.StringerTypes)
+	}
+	return !matchesTypeList(t, d.config.IgnoreStringerTypes)
+}
+
+// formatAnonymizedMapKey formats a map key in anonymization mode. Keys other
+// than strings, numbers and booleans are shown by their type only.
+func (d *Dumper) formatAnonymizedMapKey(k reflect.Value) string {
+	switch k.Kind() {
+	case reflect.String:
+		return strconv.Quote(d.anonymizeText(k.String()))
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return d.anonymizeNumber(strconv.FormatInt(k.Int(), 10))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return d.anonymizeNumber(strconv.FormatUint(k.Uint(), 10))
+	case reflect.Float32, reflect.Float64:
+		return d.anonymizeNumber(fmt.Sprint(k.Float()))
+	case reflect.Bool:
+		return strconv.FormatBool(k.Bool())
+	case reflect.Interface:
+		if !k.IsNil() {
+			return d.formatAnonymizedMapKey(k.Elem())
+		}
+	}
+	return fmt.Sprintf("<%s>", d.typeName(k.Type()))
+}
+
+// formatShallowSummary renders a one-line summary of the composite value a
+// pointer refers to, e.g. `*main.Customer{ID:42 Name:"Ann" …} @0xc000010000`.
+// Structs list their first non-zero fields; collections only their size.
+func (d *Dumper) formatShallowSummary(ptr reflect.Value) string {
+	const maxSummaryFields = 4
+	v := ptr.Elem()
+	sb := &strings.Builder{}
+	sb.WriteString(d.ApplyFormat(ColorDarkGray, "*"+d.typeName(v.Type())))
+
+	switch v.Kind() {
+	case reflect.Struct:
+		parts := []string{}
+		for i := range v.NumField() {
+			fieldVal := v.Field(i)
+			if fieldVal.IsZero() || d.skipsField(v.Type().Field(i)) {
+				continue
+			}
+			if len(parts) == maxSummaryFields {
+				parts = append(parts, d.ApplyFormat(ColorSlateGray, "…"))
+				break
+			}
+			field := v.Type().Field(i)
+			fieldName := d.ApplyFormat(ColorLightTeal, field.Name)
+			if mask := d.fieldMask(field); mask != MaskNone {
+				parts = append(parts, fieldName+":"+d.ApplyFormat(ColorSlateGray, strconv.Quote(maskValue(fieldVal, mask))))
+				continue
+			}
+			parts = append(parts, fieldName+":"+d.formatShallowField(fieldVal))
+		}
+		sb.WriteString("{" + strings.Join(parts, " ") + "}")
+	case reflect.Map:
+		sb.WriteString("{" + d.ApplyFormat(ColorSlateGray, fmt.Sprintf("… %d entries", v.Len())) + "}")
+	default:
+		sb.WriteString("{" + d.ApplyFormat(ColorSlateGray, fmt.Sprintf("… %d items", v.Len())) + "}")
+	}
+
+	sb.WriteString(d.ApplyFormat(ColorSlateGray, " @"+d.formatAddress(ptr.Pointer())))
+	return sb.String()
+}
+
+// formatShallowField renders a struct field inside a shallow summary. Only
+// primitive values are shown; anything else collapses to an ellipsis.
+func (d *Dumper) formatShallowField(v reflect.Value) string {
+	switch v.Kind() {
+	case reflect.String:
+		return d.ApplyFormat(ColorLime, `"`+d.stringEscape(d.anonymizeText(v.String()))+`"`)
+	case reflect.Bool:
+		return d.formatBool(v)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64, reflect.Complex64, reflect.Complex128:
+		return d.renderPrimitive(v)
+	default:
+		return d.ApplyFormat(ColorSlateGray, "…")
+	}
+}
+
 // formatString formats a string, escaping special characters, applying color,
 // and adding metadata like rune count.
 func (d *Dumper) formatString(v reflect.Value) string {
 	strLen := utf8.RuneCountInString(v.String())
-	str := d.stringEscape(v.String())
+	str := d.stringEscape(d.anonymizeText(v.String()))
 	str = d.ApplyFormat(ColorGoldenrod, `"`) + d.ApplyFormat(ColorLime, str) + d.ApplyFormat(ColorGoldenrod, `"`)
-	if d.config.ShowMetaInformation {
-		str = d.metaHint(fmt.Sprintf("R:%d", strLen), "") + str
+	if d.showMeta(MetaStringLength) {
+		str = d.metaHint("R:"+d.localizeInt(strLen), "") + str
 	}
 	return str
 }
@@ -548,7 +1168,17 @@ func (d *Dumper) formatType(v reflect.Value, isInCollection bool) string {
 	if !d.config.ShowTypes {
 		return ""
 	}
-	return d.ApplyFormat(ColorDarkGray, d.formatTypeNoColors(v, isInCollection))
+	typeName := d.formatTypeNoColors(v, isInCollection)
+	if typeName == "" {
+		return ""
+	}
+	color := ColorDarkGray
+	if v.IsValid() {
+		if typeColor := d.typeColor(v.Type()); typeColor != "" {
+			color = typeColor
+		}
+	}
+	return d.ApplyFormat(color, typeName)
 }
 
 // formatTypeNoColors formats the type of a value as a plain string, without colors.
@@ -560,24 +1190,95 @@ func (d *Dumper) formatTypeNoColors(v reflect.Value, isInCollection bool) string
 	if !v.IsValid() {
 		return "invalid"
 	}
+	if d.config.SmartTypes && isObviousType(v.Type()) {
+		return ""
+	}
 	vKind := v.Kind()
-	expectedType := ""
+	formattedType := ""
 	if vKind == reflect.Interface {
-		expectedType = "⧉ " + v.Type().String()
+		formattedType = d.interfaceTypeName(v, "⧉ "+d.typeName(v.Type()))
 	} else if vKind == reflect.Array || vKind == reflect.Slice || vKind == reflect.Map || vKind == reflect.Struct {
-		expectedType = v.Type().String()
+		formattedType = d.typeName(v.Type())
 	} else if !isInCollection {
-		expectedType = v.Type().String()
+		formattedType = d.typeName(v.Type())
 	}
-	actualType := ""
-	if vKind == reflect.Interface && !v.IsNil() {
-		actualType = "(" + v.Elem().Type().String() + ")"
-	}
-	formattedType := expectedType + actualType
 	formattedType = strings.ReplaceAll(formattedType, "interface {}", "any")
 	return formattedType
 }
 
+// isObviousType reports whether values of type t already tell their type, as
+// unnamed bools, numbers and strings do. Named types like time.Duration don't.
+func isObviousType(t reflect.Type) bool {
+	return t.PkgPath() == "" && (t.Kind() == reflect.Bool || t.Kind() == reflect.String || isNumericKind(t.Kind()))
+}
+
+// typeName returns the display name of a type, honoring the FullTypePaths,
+// ShortenTypePaths and PackageAliases options.
+func (d *Dumper) typeName(t reflect.Type) string {
+	if !d.config.FullTypePaths && !d.config.ShortenTypePaths && len(d.config.PackageAliases) == 0 {
+		return t.String()
+	}
+	if name, ok := d.typeNames[t]; ok {
+		return name
+	}
+	name := formatTypeName(t, d.qualifyTypeName)
+	d.typeNames[t] = name
+	return name
+}
+
+// qualifyTypeName spells out a single named type for typeName.
+func (d *Dumper) qualifyTypeName(t reflect.Type) string {
+	name := t.Name()
+	if strings.Contains(name, "[") {
+		name = d.qualifyTypeArgs(name)
+	}
+	if alias, rest, ok := d.findPackageAlias(t.PkgPath()); ok {
+		return alias + rest + "." + name
+	}
+	if d.config.FullTypePaths {
+		return t.PkgPath() + "." + name
+	}
+	pkgName := strings.TrimSuffix(t.String(), "."+t.Name())
+	return pkgName + "." + name
+}
+
+// findPackageAlias looks up the longest PackageAliases prefix matching pkgPath on a
+// path-element boundary. It returns the alias and the unmatched remainder of the path.
+func (d *Dumper) findPackageAlias(pkgPath string) (string, string, bool) {
+	bestPrefix, found := "", false
+	for prefix := range d.config.PackageAliases {
+		if pkgPath != prefix && !strings.HasPrefix(pkgPath, prefix+"/") {
+			continue
+		}
+		if !found || len(prefix) > len(bestPrefix) {
+			bestPrefix, found = prefix, true
+		}
+	}
+	if !found {
+		return "", "", false
+	}
+	return d.config.PackageAliases[bestPrefix], pkgPath[len(bestPrefix):], true
+}
+
+// qualifiedNamePattern matches the package-qualified type names in the type
+// arguments of a generic type name, e.g. "github.com/acme/billing.Invoice".
+var qualifiedNamePattern = regexp.MustCompile(`(?:[\w\-.~]+/)*[\w\-.~]+\.\w+`)
+
+// qualifyTypeArgs applies PackageAliases and ShortenTypePaths to the import
+// paths of the type arguments in name, e.g. "List[github.com/acme/billing.Invoice]".
+func (d *Dumper) qualifyTypeArgs(name string) string {
+	return qualifiedNamePattern.ReplaceAllStringFunc(name, func(qualified string) string {
+		dot := strings.LastIndexByte(qualified, '.')
+		if alias, rest, ok := d.findPackageAlias(qualified[:dot]); ok {
+			return alias + rest + qualified[dot:]
+		}
+		if d.config.ShortenTypePaths {
+			return shortenImportPaths(qualified)
+		}
+		return qualified
+	})
+}
+
 // isSimpleMapKey checks if a map key is a simple primitive that can be rendered inline easily.
 func (d *Dumper) isSimpleMapKey(k reflect.Value) bool {
 	if isSimpleValue(k) || k.Kind() == reflect.Complex64 || k.Kind() == reflect.Complex128 {
@@ -592,13 +1293,12 @@ func (d *Dumper) isSimpleStruct(v reflect.Value) bool {
 	if d.config.EmbedTypeMethods && len(findTypeMethods(v.Type())) > 0 {
 		return false
 	}
+	return hasOnlySimpleFields(v.Type())
+}
 
-	for i := range v.NumField() {
-		if !isSimpleValue(v.Field(i)) {
-			return false
-		}
-	}
-	return true
+// showMeta reports whether the given kind of meta information should be rendered.
+func (d *Dumper) showMeta(kind MetaInfo) bool {
+	return d.config.ShowMetaInformation && d.config.HiddenMeta&kind == 0
 }
 
 // metaHint formats a metadata hint (e.g., "|L:5 C:10|") with color.
@@ -609,55 +1309,106 @@ func (d *Dumper) metaHint(msg string, ico string) string {
 	return d.ApplyFormat(ColorDimGray, fmt.Sprintf("|%s| ", msg))
 }
 
-// renderAllValues orchestrates the analysis and rendering of all provided values.
-func (d *Dumper) renderAllValues(sb *strings.Builder, vs ...any) {
-	if len(vs) == 0 {
-		return
+// nilLabel returns the placeholder rendered for a nil value.
+func (d *Dumper) nilLabel(v reflect.Value) string {
+	if d.config.JSONLikeLayout {
+		return "null"
 	}
-	addressableVars := make([]reflect.Value, len(vs))
-	for i, v := range vs {
-		addressableVars[i] = makeAddressable(reflect.ValueOf(v))
+	if d.config.DistinguishNil && v.IsValid() {
+		switch v.Kind() {
+		case reflect.Slice:
+			return "<nil slice>"
+		case reflect.Map:
+			return "<nil map>"
+		}
 	}
+	return "<nil>"
+}
 
-	// The analysis pipeline for ID/back-reference tracking.
-	if d.config.TrackReferences {
-		d.resetState()
-		// 1. Traverse the object graph to collect stats on all values.
-		for _, v := range addressableVars {
-			d.preScanBFS(v)
-		}
-		// 2. Unify identical values (copies) with their original sources.
-		d.unifyAllCopies()
-		// 3. Assign IDs (e.g., "&1") to values that are referenced multiple times.
-		d.assignReferenceIDs()
-		// 4. Determine the best location to print each ID.
-		for _, v := range addressableVars {
-			d.determineDefinitionPoints(v)
-		}
+// renderAllValues orchestrates the analysis and rendering of all provided values.
+func (d *Dumper) renderAllValues(sb *strings.Builder, vs ...any) {
+	d.renderReflectValues(sb, reflectValues(vs))
+}
+
+// renderReflectValues is renderAllValues for values given as reflect.Values.
+// The zero reflect.Value is rendered like a nil interface.
+func (d *Dumper) renderReflectValues(sb *strings.Builder, vals []reflect.Value) {
+	if len(vals) == 0 {
+		return
 	}
+	start := time.Now()
+	labels := d.expressionLabels(len(vals))
+	addressableVars := d.analyzeReflectValues(vals)
 
 	// Render each top-level value.
 	for i, v := range addressableVars {
 		if i > 0 {
 			sb.WriteString("\n")
 		}
-		vType, tmpRv := checkNilInterface(vs[i])
+		if i < len(labels) && labels[i] != "" {
+			fmt.Fprint(sb, d.ApplyFormat(ColorLightTeal, labels[i]), d.ApplyFormat(ColorSlateGray, " → "))
+		}
+		vType, tmpRv := checkNilValue(v)
 		if d.config.ShowTypes {
 			if vType != "unknown" {
 				vType = d.formatType(v, false)
 			} else {
 				vType = d.ApplyFormat(ColorDarkGray, vType)
 			}
-			fmt.Fprint(sb, vType, " => ")
+			if vType != "" {
+				fmt.Fprint(sb, vType, " => ")
+			}
 		}
 
 		if tmpRv != "" {
 			sb.WriteString(d.ApplyFormat(ColorCoralRed, tmpRv))
 		} else {
+			if d.config.ShowMemoryUsage {
+				d.memoryTotal = deepSize(v)
+			}
 			d.renderValue(sb, v, 0, false)
 		}
 		fmt.Fprintln(sb)
 	}
+	d.statsStart = sb.Len()
+	d.renderStatsFooter(sb, start)
+	if d.config.ShowLegend {
+		d.renderLegend(sb)
+	}
+}
+
+// analyzeValues makes all top-level values addressable and, when reference
+// tracking is enabled, runs the ID/back-reference analysis pipeline over them.
+func (d *Dumper) analyzeValues(vs ...any) []reflect.Value {
+	return d.analyzeReflectValues(reflectValues(vs))
+}
+
+// analyzeReflectValues is analyzeValues for values given as reflect.Values.
+// Values that are already addressable are kept, so they are analyzed in place.
+func (d *Dumper) analyzeReflectValues(vals []reflect.Value) []reflect.Value {
+	addressableVars := make([]reflect.Value, len(vals))
+	for i, v := range vals {
+		addressableVars[i] = makeAddressable(v)
+	}
+
+	d.resetState()
+	// The analysis pipeline for ID/back-reference tracking.
+	if d.config.TrackReferences {
+		d.guardAnalysis(func() {
+			// 1. Traverse the object graph once to collect stats and candidate
+			//    definition points for all values.
+			d.preScanBFS(addressableVars...)
+			// 2. Unify identical values (copies) with their original sources.
+			d.unifyAllCopies()
+			// 3. Assign IDs (e.g., "&1") to values that are referenced multiple times.
+			d.assignReferenceIDs()
+			// 4. Give IDs to slices whose backing arrays other slices share.
+			d.detectSharedArrays()
+			// 5. Choose the best location to print each ID among the candidates.
+			d.chooseDefinitionPoints()
+		})
+	}
+	return addressableVars
 }
 
 // renderBackref writes a back-reference symbol "↩︎ &N" to the string builder.
@@ -665,21 +1416,25 @@ func (d *Dumper) renderBackref(sb *strings.Builder, id string) {
 	fmt.Fprint(sb, d.ApplyFormat(ColorPink, "↩︎ "+id))
 }
 
+// renderBlockLineEnd terminates one item line of a block-rendered collection,
+// adding the trailing comma used by the JSON-like layout.
+func (d *Dumper) renderBlockLineEnd(sb *strings.Builder) {
+	if d.config.JSONLikeLayout {
+		fmt.Fprint(sb, ",")
+	}
+	fmt.Fprintln(sb)
+}
+
 // renderHeader prints the file and line number of the Dump() call.
 func (d *Dumper) renderHeader(out io.Writer) {
 	file, line, govarFuncName := findCallerInStack()
 	if file == "" {
 		return
 	}
-	relPath := file
-	if wd, err := os.Getwd(); err == nil {
-		if rel, err := filepath.Rel(wd, file); err == nil {
-			relPath = rel
-		}
-	}
-	headerTitle := d.ApplyFormat(ColorGoBlue, "[>] "+govarFuncName)
-	headerLocation := d.ApplyFormat(ColorSlateGray, fmt.Sprintf("  ⟵  %s:%d", relPath, line))
-	header := headerTitle + headerLocation
+	relPath := relativeSourcePath(file)
+	headerTitle := d.ApplyFormat(ColorGoBlue, "[>] "+govarFuncName+d.dumpCountersLabel(file, line))
+	headerLocation := d.ApplyFormat(ColorSlateGray, fmt.Sprintf("%s:%d", relPath, line))
+	header := headerTitle + d.ApplyFormat(ColorSlateGray, "  ⟵  ") + d.linkHeaderLocation(headerLocation, file, line)
 	fmt.Fprintln(out, header)
 }
 
@@ -743,27 +1498,41 @@ func (d *Dumper) renderPrimitive(v reflect.Value) string {
 		return d.formatBool(v)
 	case reflect.String:
 		return d.formatString(v)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64, reflect.Complex64, reflect.Complex128:
+		return d.ApplyFormat(ColorSkyBlue, d.numberText(v))
+	}
+	return "" // Should not be reached
+}
+
+// numberText formats a numeric value as plain text for the configured Locale,
+// anonymized if configured.
+func (d *Dumper) numberText(v reflect.Value) string {
+	switch v.Kind() {
 	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
-		return d.ApplyFormat(ColorSkyBlue, fmt.Sprint(v.Int()))
+		return d.anonymizeNumber(d.config.Locale.format(fmt.Sprint(v.Int())))
 	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
-		return d.ApplyFormat(ColorSkyBlue, fmt.Sprint(v.Uint()))
+		return d.anonymizeNumber(d.config.Locale.format(fmt.Sprint(v.Uint())))
 	case reflect.Float32, reflect.Float64:
-		return d.ApplyFormat(ColorSkyBlue, fmt.Sprintf("%f", v.Float()))
+		return d.anonymizeNumber(d.config.Locale.format(fmt.Sprintf("%f", v.Float())))
 	case reflect.Complex64, reflect.Complex128:
-		return d.ApplyFormat(ColorSkyBlue, fmt.Sprintf("%v", v.Complex()))
+		return d.anonymizeNumber(fmt.Sprintf("%v", v.Complex()))
 	}
-	return "" // Should not be reached
+	return ""
 }
 
 // renderStruct formats a struct, deciding between inline and block rendering.
 func (d *Dumper) renderStruct(sb *strings.Builder, v reflect.Value, level int) {
 	t := v.Type()
-	fmt.Fprint(sb, "{")
+	fmt.Fprint(sb, d.delim("{", level))
 
-	if d.shouldRenderInline(v) {
+	getters := d.callGetters(v)
+	fields, hidden := d.shownFields(t)
+	if len(getters) == 0 && d.shouldRenderInline(v, level) {
 		// --- INLINE RENDER ---
-		for i := 0; i < t.NumField(); i++ {
-			if i > 0 {
+		for n, i := range fields {
+			if n > 0 {
 				fmt.Fprint(sb, ", ")
 			}
 			field, fieldVal := t.Field(i), v.Field(i)
@@ -775,22 +1544,26 @@ func (d *Dumper) renderStruct(sb *strings.Builder, v reflect.Value, level int) {
 					if id, hasID := d.referenceIDs[rootKey]; hasID {
 						def, defExists := d.definitionPoints[rootKey]
 						if defExists && def.isPointerRef && deref(fieldVal).Type() == def.valueType {
-							d.renderStructField(sb, field, fieldVal, 0, 0, true)
+							d.renderStructField(sb, field, fieldVal, 0, 0, level, true)
 							d.renderBackref(sb, id)
 							continue
 						}
 					}
 				}
 			}
-			d.renderStructField(sb, field, fieldVal, 0, 0, true)
-			d.renderValue(sb, fieldVal, level, false)
+			d.renderStructField(sb, field, fieldVal, 0, 0, level, true)
+			d.renderFieldValue(sb, field, fieldVal, level)
+		}
+		if hidden > 0 {
+			fmt.Fprint(sb, ", ", d.ApplyFormat(ColorSlateGray, moreFieldsMarker(hidden)))
 		}
 	} else {
 		// --- BLOCK RENDER ---
 		fmt.Fprintln(sb)
 		maxKeyLen, maxTypeLen := d.calculateStructPadding(v)
+		maxKeyLen, maxTypeLen = d.getterPadding(getters, maxKeyLen, maxTypeLen)
 
-		for i := 0; i < t.NumField(); i++ {
+		for _, i := range fields {
 			field, fieldVal := t.Field(i), v.Field(i)
 
 			// Special check for embedded structs that are back-references.
@@ -802,29 +1575,62 @@ func (d *Dumper) renderStruct(sb *strings.Builder, v reflect.Value, level int) {
 						def, defExists := d.definitionPoints[rootKey]
 						if defExists && def.isPointerRef && deref(fieldVal).Type() == def.valueType {
 							d.renderIndent(sb, level+1, "")
-							d.renderStructField(sb, field, fieldVal, maxKeyLen, maxTypeLen, false)
+							d.renderStructField(sb, field, fieldVal, maxKeyLen, maxTypeLen, level, false)
 							d.renderBackref(sb, id)
-							fmt.Fprintln(sb)
+							d.renderBlockLineEnd(sb)
 							continue
 						}
 					}
 				}
 			}
 			d.renderIndent(sb, level+1, "")
-			d.renderStructField(sb, field, fieldVal, maxKeyLen, maxTypeLen, false)
-			d.renderValue(sb, fieldVal, level+1, false)
-			fmt.Fprintln(sb)
+			d.renderStructField(sb, field, fieldVal, maxKeyLen, maxTypeLen, level, false)
+			d.renderFieldValue(sb, field, fieldVal, level+1)
+			d.renderBlockLineEnd(sb)
+		}
+		if hidden > 0 {
+			d.renderIndent(sb, level+1, d.ApplyFormat(ColorSlateGray, moreFieldsMarker(hidden))+"\n")
 		}
+		d.renderGetters(sb, getters, maxKeyLen, maxTypeLen, level+1)
 		if d.config.EmbedTypeMethods {
 			d.renderTypeMethods(sb, t, level+1, maxKeyLen)
 		}
 		d.renderIndent(sb, level, "")
 	}
-	fmt.Fprint(sb, "}")
+	fmt.Fprint(sb, d.delim("}", level))
+}
+
+// shownFields returns the indices of the fields of struct type t that are
+// rendered, and how many more fields are cut by the MaxFields limit. Fields
+// omitted by SkipUnexported are neither rendered nor counted.
+func (d *Dumper) shownFields(t reflect.Type) ([]int, int) {
+	fields := make([]int, 0, t.NumField())
+	for i := range t.NumField() {
+		if !d.skipsField(t.Field(i)) {
+			fields = append(fields, i)
+		}
+	}
+	if d.config.MaxFields > 0 && len(fields) > d.config.MaxFields {
+		return fields[:d.config.MaxFields], len(fields) - d.config.MaxFields
+	}
+	return fields, 0
+}
+
+// skipsField reports whether a struct field is omitted by SkipUnexported.
+func (d *Dumper) skipsField(field reflect.StructField) bool {
+	return d.config.SkipUnexported && !field.IsExported()
+}
+
+// moreFieldsMarker is the placeholder for struct fields cut by MaxFields.
+func moreFieldsMarker(hidden int) string {
+	if hidden == 1 {
+		return "… 1 more field"
+	}
+	return fmt.Sprintf("… %d more fields", hidden)
 }
 
 // renderStructField is a helper to format the field part of a struct line.
-func (d *Dumper) renderStructField(sb *strings.Builder, field reflect.StructField, fieldVal reflect.Value, maxKeyLen, maxTypeLen int, isInline bool) {
+func (d *Dumper) renderStructField(sb *strings.Builder, field reflect.StructField, fieldVal reflect.Value, maxKeyLen, maxTypeLen, level int, isInline bool) {
 	renderVal := fieldVal
 	symbol := "⯀ "
 	if !field.IsExported() {
@@ -835,11 +1641,18 @@ func (d *Dumper) renderStructField(sb *strings.Builder, field reflect.StructFiel
 	unformattedFieldLen := utf8.RuneCountInString(symbol + field.Name)
 	unformattedTypeLen := utf8.RuneCountInString(d.formatTypeNoColors(renderVal, false))
 	symbol = d.ApplyFormat(ColorDarkGoBlue, symbol)
-	fieldName := d.ApplyFormat(ColorLightTeal, field.Name)
+	fieldName := d.formatLabel(field.Name, d.depthColor(level, ColorLightTeal), field.Name)
 	formattedType := d.formatType(renderVal, false)
 
 	var fieldRender string
-	if isInline {
+	if d.config.JSONLikeLayout {
+		// JSON-LIKE RENDER (no visibility symbols, no column alignment)
+		fieldRender = fieldName + ":"
+		if formattedType != "" {
+			fieldRender += " " + formattedType
+		}
+		fieldRender += " "
+	} else if isInline {
 		// INLINE RENDER
 		fieldRender = fmt.Sprintf("%s%s", symbol, fieldName)
 		if formattedType != "" {
@@ -849,7 +1662,7 @@ func (d *Dumper) renderStructField(sb *strings.Builder, field reflect.StructFiel
 	} else {
 		// BLOCK RENDER
 		fieldRender = padRight(symbol+fieldName, unformattedFieldLen, maxKeyLen)
-		if formattedType != "" {
+		if formattedType != "" || maxTypeLen > 0 {
 			fieldRender += "  " + padRight(formattedType, unformattedTypeLen, maxTypeLen)
 		}
 		fieldRender += " => "
@@ -858,9 +1671,74 @@ func (d *Dumper) renderStructField(sb *strings.Builder, field reflect.StructFiel
 	sb.WriteString(fieldRender)
 }
 
+// renderFieldValue renders the value of a struct field, applying the directives
+// of its govar tag: masking replaces the value, format directives change how it
+// is rendered, and hints are appended to it.
+func (d *Dumper) renderFieldValue(sb *strings.Builder, field reflect.StructField, fieldVal reflect.Value, level int) {
+	defer d.enterPath(field.Name)()
+	if mask := d.fieldMask(field); mask != MaskNone {
+		d.renderMaskedValue(sb, fieldVal, mask)
+		return
+	}
+	format := parseFieldOptions(field.Tag).format
+	if str, ok := d.formatFieldValue(fieldVal, format); ok {
+		sb.WriteString(str)
+	} else {
+		if format == "compact" {
+			d.forceInline++
+			defer func() { d.forceInline-- }()
+		}
+		d.renderValue(sb, fieldVal, level, false)
+	}
+	if hint := d.fieldHint(field, fieldVal); hint != "" {
+		sb.WriteString(" " + d.ApplyFormat(ColorDimGray, "|"+hint+"|"))
+	}
+}
+
+// freshStackLevels is how many nesting levels are rendered on one goroutine
+// before the rendering continues on a new one. Goroutine stacks are limited in
+// size, so this lets dumps with a very large MaxDepth render deep linked
+// structures without overflowing the stack.
+const freshStackLevels = 1000
+
+// needsFreshStack reports whether the value at level should be rendered on a
+// new goroutine: every freshStackLevels levels, unless that already happened.
+func (d *Dumper) needsFreshStack(level int) bool {
+	return level > 0 && level%freshStackLevels == 0 && d.freshStackLevel != level
+}
+
+// onFreshStack runs render on a new goroutine and waits for it, re-raising any
+// panic in the calling goroutine.
+func (d *Dumper) onFreshStack(level int, render func()) {
+	prev := d.freshStackLevel
+	d.freshStackLevel = level
+	defer func() { d.freshStackLevel = prev }()
+
+	done := make(chan any)
+	go func() {
+		defer func() { done <- recover() }()
+		render()
+	}()
+	if p := <-done; p != nil {
+		panic(p)
+	}
+}
+
 // renderValue is the main recursive rendering function. It handles printing a single value,
 // including its ID/back-reference if applicable, and then delegates to type-specific formatters.
 func (d *Dumper) renderValue(sb *strings.Builder, v reflect.Value, level int, skipRefCheck bool) {
+	if d.needsFreshStack(level) {
+		d.onFreshStack(level, func() { d.renderValue(sb, v, level, skipRefCheck) })
+		return
+	}
+	d.renderedNodes++
+	defer d.useTypeColor(v)()
+	if !skipRefCheck {
+		if closeTag := d.openPathSpan(sb); closeTag != "" {
+			defer sb.WriteString(closeTag)
+		}
+		defer d.renderDiffHint(sb)
+	}
 	if level > d.config.MaxDepth {
 		fmt.Fprint(sb, d.ApplyFormat(ColorSlateGray, "… (max depth reached)"))
 		return
@@ -870,81 +1748,228 @@ func (d *Dumper) renderValue(sb *strings.Builder, v reflect.Value, level int, sk
 		return
 	}
 	if isNil(v) {
-		fmt.Fprint(sb, d.ApplyFormat(ColorCoralRed, "<nil>"))
+		fmt.Fprint(sb, d.ApplyFormat(ColorCoralRed, d.nilLabel(v)))
+		return
+	}
+	// A non-nil interface holding a typed nil, e.g. an error wrapping a nil *MyErr.
+	if v.Kind() == reflect.Interface && isNil(v.Elem()) {
+		fmt.Fprint(sb, d.ApplyFormat(ColorCoralRed, d.typedNilLabel(v)))
 		return
 	}
 
 	// Handle ID and back-reference printing.
 	if d.config.TrackReferences && !skipRefCheck {
-		rawKey, keyOK := d.getRawKey(v)
-		if keyOK {
-			rootKey := d.findRoot(rawKey)
-			if id, hasID := d.referenceIDs[rootKey]; hasID {
-				def, defExists := d.definitionPoints[rootKey]
-				instKey, instKeyOK := d.getInstanceKey(v)
-				// Check if the current value is the chosen "definition point".
-				isTheChosenDefinition := defExists && instKeyOK && def.instanceKey == instKey
-
-				if isTheChosenDefinition {
-					// This is the definition point. If we've already rendered it (e.g., a cycle),
-					// print a back-reference. Otherwise, print the ID and render the value.
-					if d.renderedIDs[rootKey] {
-						d.renderBackref(sb, id)
-						return
-					}
-					d.renderID(sb, id)
-					d.renderedIDs[rootKey] = true
-				} else {
-					// This is not the definition point, so it must be a back-reference.
-					d.renderBackref(sb, id)
-					return
-				}
+		if id, isBackref := d.resolveReference(v); id != "" {
+			if isBackref {
+				d.renderBackref(sb, id)
+				return
 			}
+			d.renderID(sb, id)
 		}
 	}
 
-	// Simple cycle detection for when TrackReferences is false.
+	// Simple cycle detection for when TrackReferences is false. Only values on
+	// the current path count, keyed by address and type: a struct and its first
+	// field share an address, and a value shared by siblings is not a cycle.
 	if !d.config.TrackReferences {
-		addr := getValPtr(v)
-		if addr != nil {
-			if d.visitedPointers[addr] {
+		if addr := getValPtr(v); addr != nil {
+			key := canonicalKey{uintptr(addr), v.Type()}
+			if d.visitedPointers[key] {
 				sb.WriteString(d.ApplyFormat(ColorSlateGray, "<cycle>"))
 				return
 			}
-			d.visitedPointers[addr] = true
+			d.visitedPointers[key] = true
+			defer delete(d.visitedPointers, key)
 		}
 	}
 
+	if stack, ok := asStack(v); ok {
+		d.renderStack(sb, stack, level)
+		return
+	}
+	if d.renderHandle(sb, v, level) {
+		return
+	}
+	if d.renderHeavyTypeSummary(sb, v) {
+		return
+	}
+
 	// Check for fmt.Stringer or error interfaces.
 	exportedV := tryExport(v)
-	if exportedV.Kind() != reflect.Interface && !d.config.IgnoreStringer {
+	if exportedV.Kind() != reflect.Interface && d.useStringer(exportedV.Type()) {
 		if str := d.asStringerInterface(exportedV); str != "" {
-			if d.config.ShowMetaInformation {
+			if d.showMeta(MetaInterfaceHint) {
 				fmt.Fprint(sb, d.metaHint("Stringer:", ""))
 			}
 			fmt.Fprint(sb, str)
+			d.renderStringerFields(sb, exportedV, level)
+			return
+		}
+		if errs := d.joinedErrors(exportedV); errs != nil {
+			if d.showMeta(MetaInterfaceHint) {
+				fmt.Fprint(sb, d.metaHint("errors.Join:", ""))
+			}
+			fmt.Fprint(sb, d.formatArrayOrSlice(reflect.ValueOf(errs), level))
 			return
 		}
 		if str := d.asErrorInterface(exportedV); str != "" {
-			if d.config.ShowMetaInformation {
+			if d.showMeta(MetaInterfaceHint) {
 				fmt.Fprint(sb, d.metaHint("error:", ""))
 			}
 			fmt.Fprint(sb, str)
+			d.renderErrorStack(sb, exportedV, level)
+			d.renderErrorFields(sb, exportedV, level)
+			return
+		}
+		if str := d.asFormatterInterface(exportedV); str != "" {
+			if d.showMeta(MetaInterfaceHint) {
+				fmt.Fprint(sb, d.metaHint("Formatter:", ""))
+			}
+			fmt.Fprint(sb, str)
+			return
+		}
+		if str := d.asTextMarshalerInterface(exportedV); str != "" {
+			if d.showMeta(MetaInterfaceHint) {
+				fmt.Fprint(sb, d.metaHint("TextMarshaler:", ""))
+			}
+			fmt.Fprint(sb, str)
 			return
 		}
+		if str := d.asJSONMarshalerInterface(exportedV, level); str != "" {
+			if d.showMeta(MetaInterfaceHint) {
+				fmt.Fprint(sb, d.metaHint("JSONMarshaler:", ""))
+			}
+			fmt.Fprint(sb, str)
+			return
+		}
+	}
+
+	d.renderMemoryUsage(sb, v)
+	d.renderImplements(sb, v)
+
+	// Collapse deep subtrees of HTML dumps into lazily revealed sections.
+	if d.shouldCollapseHTML(v, level) {
+		d.renderCollapsedHTML(sb, v, level)
+		return
 	}
 
-	// Delegate to kind-specific rendering functions.
+	d.renderKind(sb, v, level)
+}
+
+// stringerFields returns the struct behind a Stringer or error value if the
+// StringerWithFields option is enabled and the struct has exported fields.
+// Structs with only unexported fields (e.g. errors.New) are left as text alone.
+func (d *Dumper) stringerFields(v reflect.Value) (reflect.Value, bool) {
+	if !d.config.StringerWithFields {
+		return v, false
+	}
+	for v.Kind() == reflect.Ptr && !v.IsNil() {
+		v = v.Elem()
+	}
+	return v, v.Kind() == reflect.Struct && v.NumField() > 0 && !hasOnlyUnexportedFields(v)
+}
+
+// renderStringerFields writes the fields of the struct behind a Stringer or
+// error value after its string representation, when StringerWithFields applies.
+func (d *Dumper) renderStringerFields(sb *strings.Builder, v reflect.Value, level int) {
+	if s, ok := d.stringerFields(v); ok {
+		fmt.Fprint(sb, " ")
+		d.renderStruct(sb, s, level)
+	}
+}
+
+// joinedErrors returns the errors wrapped by a multi-error, i.e. a value with an
+// Unwrap() []error method such as the results of errors.Join or fmt.Errorf with
+// several %w verbs, if the ExpandJoinedErrors option is enabled. Otherwise, it
+// returns nil.
+func (d *Dumper) joinedErrors(v reflect.Value) []error {
+	if !d.config.ExpandJoinedErrors || !v.CanInterface() {
+		return nil
+	}
+	if v.Kind() == reflect.Ptr && v.IsNil() {
+		return nil
+	}
+	if u, ok := v.Interface().(interface{ Unwrap() []error }); ok {
+		if _, isErr := u.(error); isErr {
+			return u.Unwrap()
+		}
+	}
+	return nil
+}
+
+// errorFields returns the struct behind an error value and the indices of its
+// exported fields if the ExpandErrorFields option is enabled and there are any.
+func (d *Dumper) errorFields(v reflect.Value) (reflect.Value, []int) {
+	if !d.config.ExpandErrorFields {
+		return v, nil
+	}
+	for v.Kind() == reflect.Ptr && !v.IsNil() {
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return v, nil
+	}
+	var exported []int
+	for i := range v.NumField() {
+		if v.Type().Field(i).IsExported() {
+			exported = append(exported, i)
+		}
+	}
+	return v, exported
+}
+
+// renderErrorFields writes the fields of the struct behind an error value after
+// its message: all of them with StringerWithFields, or only the exported ones
+// as an indented block with ExpandErrorFields.
+func (d *Dumper) renderErrorFields(sb *strings.Builder, v reflect.Value, level int) {
+	if _, ok := d.stringerFields(v); ok {
+		d.renderStringerFields(sb, v, level)
+		return
+	}
+	s, exported := d.errorFields(v)
+	if len(exported) == 0 {
+		return
+	}
+	maxKeyLen, maxTypeLen := 0, 0
+	for _, i := range exported {
+		if d.config.NoAlignment {
+			break
+		}
+		maxKeyLen = d.widenPadding(maxKeyLen, utf8.RuneCountInString(s.Type().Field(i).Name)+2) // +2 for visibility symbol
+		maxTypeLen = d.widenPadding(maxTypeLen, utf8.RuneCountInString(d.formatTypeNoColors(s.Field(i), false)))
+	}
+	fmt.Fprintln(sb, " "+d.delim("{", level))
+	for _, i := range exported {
+		d.renderIndent(sb, level+1, "")
+		d.renderStructField(sb, s.Type().Field(i), s.Field(i), maxKeyLen, maxTypeLen, level, false)
+		d.renderFieldValue(sb, s.Type().Field(i), s.Field(i), level+1)
+		d.renderBlockLineEnd(sb)
+	}
+	d.renderIndent(sb, level, "")
+	fmt.Fprint(sb, d.delim("}", level))
+}
+
+// renderKind delegates rendering of a value to its kind-specific formatter.
+func (d *Dumper) renderKind(sb *strings.Builder, v reflect.Value, level int) {
 	switch v.Kind() {
 	case reflect.Ptr, reflect.Interface:
+		if v.Kind() == reflect.Ptr && d.config.ShallowPointers && level > 0 && isCompositeOrInterface(v.Elem().Kind()) && v.Elem().Kind() != reflect.Interface {
+			fmt.Fprint(sb, d.formatShallowSummary(v))
+			return
+		}
+		if v.Kind() == reflect.Ptr && d.config.ShowIndirection {
+			// One star per pointer level, closed by an arrow once the pointee is reached.
+			marker := "*"
+			if v.Elem().Kind() != reflect.Ptr {
+				marker += "→"
+			}
+			fmt.Fprint(sb, d.ApplyFormat(ColorDimGray, marker))
+		}
 		d.renderValue(sb, v.Elem(), level, true) // Dereference and render, skipping the next ref check.
 	case reflect.Struct:
 		d.renderStruct(sb, v, level)
-	case reflect.Slice, reflect.Array:
-		renderVal := d.formatArrayOrSlice(v, level)
-		d.wrapAndRender(sb, renderVal, v.Type(), level)
-	case reflect.Map:
-		renderVal := d.formatMap(v, level)
+	case reflect.Slice, reflect.Array, reflect.Map:
+		renderVal := d.formatCollection(v, level)
 		d.wrapAndRender(sb, renderVal, v.Type(), level)
 	case reflect.Bool, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
 		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
@@ -952,7 +1977,7 @@ func (d *Dumper) renderValue(sb *strings.Builder, v reflect.Value, level int, sk
 		renderVal := d.renderPrimitive(v)
 		d.wrapAndRender(sb, renderVal, v.Type(), level)
 	case reflect.UnsafePointer:
-		fmt.Fprint(sb, d.ApplyFormat(ColorSlateGray, fmt.Sprintf("unsafe.Pointer(%#x)", v.Pointer())))
+		fmt.Fprint(sb, d.ApplyFormat(ColorSlateGray, "unsafe.Pointer("+d.formatAddress(v.Pointer())+")"))
 	case reflect.Func:
 		renderVal := d.formatFunc(v)
 		d.wrapAndRender(sb, renderVal, v.Type(), level)
@@ -964,28 +1989,141 @@ func (d *Dumper) renderValue(sb *strings.Builder, v reflect.Value, level int, sk
 	}
 }
 
+// shouldCollapseHTML reports whether a value sits at the configured HTML collapse
+// depth and is large enough to be rendered as a block, in which case its content
+// is hidden behind a <details> toggle.
+func (d *Dumper) shouldCollapseHTML(v reflect.Value, level int) bool {
+	if d.config.HTMLCollapseDepth <= 0 || level != d.config.HTMLCollapseDepth {
+		return false
+	}
+	if _, isHTML := d.Formatter.(*HTMLformatter); !isHTML {
+		return false
+	}
+	switch v.Kind() {
+	case reflect.Struct, reflect.Slice, reflect.Array, reflect.Map:
+		return !d.shouldRenderInline(v, level)
+	default:
+		return false
+	}
+}
+
+// renderCollapsedHTML renders a value inside a collapsed <details> element whose
+// summary shows only the size of the hidden subtree.
+func (d *Dumper) renderCollapsedHTML(sb *strings.Builder, v reflect.Value, level int) {
+	var summary string
+	if v.Kind() == reflect.Struct {
+		summary = fmt.Sprintf("… %d fields", v.NumField())
+	} else {
+		summary = fmt.Sprintf("… %d items", v.Len())
+	}
+	fmt.Fprint(sb, `<details class="govar-collapsed" style="display:inline">`)
+	fmt.Fprintf(sb, `<summary style="display:inline; cursor:pointer">%s</summary>`, d.ApplyFormat(ColorSlateGray, summary))
+	d.renderKind(sb, v, level)
+	fmt.Fprint(sb, `</details>`)
+}
+
 // shouldRenderInline determines if a value is simple enough to be rendered on a
 // single line. The decision is based on its kind, number of elements, and
 // estimated inline length.
-func (d *Dumper) shouldRenderInline(v reflect.Value) bool {
-	if !v.IsValid() {
+func (d *Dumper) shouldRenderInline(v reflect.Value, level int) bool {
+	if !v.IsValid() || d.forceInline > 0 || d.config.SingleLine {
 		return true
 	}
+	limit := d.inlineLimit(level)
 	switch v.Kind() {
 	case reflect.Array, reflect.Slice:
-		return isSimpleCollection(v) && v.Len() <= 10 && d.estimatedInlineLength(v) <= d.config.MaxInlineLength
+		return v.Len() <= 10 && isSimpleCollection(v) && d.estimatedInlineLength(v) <= limit
 	case reflect.Map:
-		return isSimpleMap(v) && v.Len() <= 10 && d.estimatedInlineLength(v) <= d.config.MaxInlineLength
+		return v.Len() <= 10 && isSimpleMap(v) && d.estimatedInlineLength(v) <= limit
 	case reflect.Struct:
 		if d.config.EmbedTypeMethods && len(findTypeMethods(v.Type())) > 0 {
 			return false
 		}
-		return d.isSimpleStruct(v) && v.NumField() <= 10 && d.estimatedInlineLength(v) <= d.config.MaxInlineLength
+		return d.isSimpleStruct(v) && v.NumField() <= 10 && d.estimatedInlineLength(v) <= limit
 	default:
 		return true
 	}
 }
 
+// minAdaptiveInline keeps short values inline on very narrow terminals.
+const minAdaptiveInline = 20
+
+// inlineLimit returns the maximum inline width of a value at the given nesting
+// level: MaxInlineLength, or with AdaptiveInline the terminal width left
+// after the indentation.
+func (d *Dumper) inlineLimit(level int) int {
+	if !d.config.AdaptiveInline || d.termWidth <= 0 {
+		return d.config.MaxInlineLength
+	}
+	return max(d.termWidth-(level+1)*d.config.IndentWidth, minAdaptiveInline)
+}
+
+// sortedMapKeys returns the keys of map v in the configured display order.
+// Large maps (see MapSampleThreshold) yield an unsorted sample of their keys.
+func (d *Dumper) sortedMapKeys(v reflect.Value) []reflect.Value {
+	if d.isSampledMap(v) {
+		return d.sampledMapKeys(v)
+	}
+	keys := sortMapKeys(v)
+	if d.config.NaturalMapKeys && len(keys) > 0 && keys[0].Kind() == reflect.String {
+		sort.SliceStable(keys, func(i, j int) bool {
+			return naturalLess(keys[i].String(), keys[j].String())
+		})
+	}
+	if d.config.SortMapsByValue {
+		// Entries with equal values keep their key order thanks to the stable sort.
+		switch v.Type().Elem().Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			sort.SliceStable(keys, func(i, j int) bool {
+				return v.MapIndex(keys[i]).Int() > v.MapIndex(keys[j]).Int()
+			})
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+			sort.SliceStable(keys, func(i, j int) bool {
+				return v.MapIndex(keys[i]).Uint() > v.MapIndex(keys[j]).Uint()
+			})
+		case reflect.Float32, reflect.Float64:
+			sort.SliceStable(keys, func(i, j int) bool {
+				return v.MapIndex(keys[i]).Float() > v.MapIndex(keys[j]).Float()
+			})
+		case reflect.String:
+			sort.SliceStable(keys, func(i, j int) bool {
+				return v.MapIndex(keys[i]).String() < v.MapIndex(keys[j]).String()
+			})
+		}
+	}
+	if d.config.Deterministic {
+		d.orderTiedMapKeys(v, keys)
+	}
+	return keys
+}
+
+// isSampledMap reports whether map v is too large to sort its keys and is
+// rendered as a sample instead. Deterministic output always sorts.
+func (d *Dumper) isSampledMap(v reflect.Value) bool {
+	return d.config.MapSampleThreshold > 0 && !d.config.Deterministic && v.Len() > d.config.MapSampleThreshold
+}
+
+// sampledMapKeys returns the first MaxItems keys of map v in iteration order.
+// Map iteration order is random, so the sample is remembered for the rest of the
+// dump to keep the reference analysis and the rendering in agreement.
+func (d *Dumper) sampledMapKeys(v reflect.Value) []reflect.Value {
+	if keys, ok := d.mapSamples[v.Pointer()]; ok {
+		return keys
+	}
+	keys := make([]reflect.Value, 0, min(d.config.MaxItems, v.Len()))
+	for iter := v.MapRange(); len(keys) < d.config.MaxItems && iter.Next(); {
+		keys = append(keys, iter.Key())
+	}
+	d.mapSamples[v.Pointer()] = keys
+	return keys
+}
+
+// sampleMarker describes the sampled keys of a large map, e.g.
+// "… (unsorted sample, 150 of 5000000 entries)".
+func sampleMarker(v reflect.Value, keys []reflect.Value) string {
+	return fmt.Sprintf("… (unsorted sample, %d of %d entries)", len(keys), v.Len())
+}
+
 // stringEscape truncates a string if it exceeds MaxStringLen and escapes
 // common non-printable characters.
 func (d *Dumper) stringEscape(str string) string {
@@ -1013,6 +2151,34 @@ func (d *Dumper) wrapAndRender(sb *strings.Builder, renderVal string, t reflect.
 	}
 }
 
+// depthColor returns the palette color of a nesting level when RainbowDepth is
+// enabled, and the fallback color otherwise.
+func (d *Dumper) depthColor(level int, fallback string) string {
+	if !d.config.RainbowDepth {
+		return fallback
+	}
+	return depthPalette[level%len(depthPalette)]
+}
+
+// delim returns a brace or bracket opening or closing a value at the given
+// nesting level, tinted by depth when RainbowDepth is enabled.
+func (d *Dumper) delim(s string, level int) string {
+	if !d.config.RainbowDepth {
+		return s
+	}
+	return d.ApplyFormat(d.depthColor(level, ""), s)
+}
+
+// widenPadding returns the alignment column width after a key or type of the
+// given width: the larger of both, unless width exceeds MaxPaddingWidth. Such
+// outliers do not widen the column; their lines simply run past it.
+func (d *Dumper) widenPadding(column, width int) int {
+	if d.config.MaxPaddingWidth > 0 && width > d.config.MaxPaddingWidth {
+		return column
+	}
+	return max(column, width)
+}
+
 // padRight adds spaces to the right of a string to reach a minimum width.
 // It correctly handles ANSI color codes, using the unformattedWidth for calculation.
 func padRight(s string, unformattedWidth int, maxWidth int) string {
@@ -1,8 +1,14 @@
 package govar
 
 import (
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"runtime/debug"
 	"strings"
 	"testing"
+	"time"
 )
 
 func TestDumpBasicTypes(t *testing.T) {
@@ -237,6 +243,14 @@ func TestDumpMaps(t *testing.T) {
 	}
 }
 
+type customError struct {
+	Code int
+}
+
+func (e *customError) Error() string {
+	return "custom error"
+}
+
 func TestDumpInterfaces(t *testing.T) {
 	type MyInterface interface {
 		Dummy()
@@ -249,6 +263,8 @@ func TestDumpInterfaces(t *testing.T) {
 	var ifaceWithStruct any = struct{ A int }{A: 7}
 	var ifaceWithPtr any = &struct{ B string }{B: "world"}
 	var ifaceWithIface any = any(123.45)
+	var typedNilErr *customError
+	ifaceWithTypedNil := struct{ Err error }{Err: typedNilErr}
 
 	tests := []struct {
 		name         string
@@ -290,6 +306,11 @@ func TestDumpInterfaces(t *testing.T) {
 			input:        ifaceWithIface,
 			wantContains: `float64 => 123.450000`,
 		},
+		{
+			name:         "interface holding typed nil pointer",
+			input:        ifaceWithTypedNil,
+			wantContains: `⯀ Err  ⧉ error(*govar.customError) => (*govar.customError)(nil)`,
+		},
 	}
 
 	for _, tt := range tests {
@@ -654,3 +675,1161 @@ func TestDumpStructsWithUnexportedFields(t *testing.T) {
 		})
 	}
 }
+
+func TestDumpJSONLikeLayout(t *testing.T) {
+	type Item struct {
+		Name  string
+		Tags  []string
+		Attrs map[string]int
+		Next  *Item
+	}
+
+	cfg := JSONLikeConfig
+	cfg.UseColors = false
+	d := NewDumper(cfg)
+
+	out := d.Sdump(Item{Name: "widget", Tags: []string{"a", "b"}, Attrs: map[string]int{"x": 1, "y": 2}})
+	wantContains := []string{
+		`Name: "widget",`,
+		`Tags: ["a", "b"],`,
+		`Attrs: {"x": 1, "y": 2},`,
+		`Next: null,`,
+	}
+	for _, want := range wantContains {
+		if !strings.Contains(out, want) {
+			t.Errorf("JSON-like layout: missing expected fragment:\nwant contains:\n%s\ngot:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "=>") || strings.Contains(out, "⯀") {
+		t.Errorf("JSON-like layout should not contain arrows or visibility symbols, got:\n%s", out)
+	}
+}
+
+func TestDumpFullTypePaths(t *testing.T) {
+	type Invoice struct {
+		ID    int
+		Lines []*Invoice
+	}
+
+	cfg := DefaultConfig
+	cfg.UseColors = false
+	cfg.FullTypePaths = true
+	d := NewDumper(cfg)
+
+	out := d.Sdump(Invoice{ID: 7})
+	wantContains := []string{
+		`github.com/janvaclavik/govar.Invoice => {`,
+		`[]*github.com/janvaclavik/govar.Invoice`,
+	}
+	for _, want := range wantContains {
+		if !strings.Contains(out, want) {
+			t.Errorf("FullTypePaths: missing expected fragment:\nwant contains:\n%s\ngot:\n%s", want, out)
+		}
+	}
+}
+
+type genericBox[T any] struct {
+	Item T
+}
+
+func TestDumpPackageAliases(t *testing.T) {
+	type Invoice struct {
+		ID int
+	}
+
+	tests := []struct {
+		name         string
+		configure    func(cfg *DumperConfig)
+		input        any
+		wantContains string
+	}{
+		{
+			name:         "alias for exact import path",
+			configure:    func(cfg *DumperConfig) { cfg.PackageAliases = map[string]string{"github.com/janvaclavik/govar": "gv"} },
+			input:        []*Invoice{{ID: 1}},
+			wantContains: `[]*gv.Invoice => |1| [`,
+		},
+		{
+			name: "longest prefix wins",
+			configure: func(cfg *DumperConfig) {
+				cfg.PackageAliases = map[string]string{"github.com": "gh", "github.com/janvaclavik": "jv"}
+			},
+			input:        Invoice{ID: 2},
+			wantContains: `jv/govar.Invoice => {`,
+		},
+		{
+			name:         "shorten generic type arguments",
+			configure:    func(cfg *DumperConfig) { cfg.ShortenTypePaths = true },
+			input:        genericBox[testData]{Item: testData{Name: "boxed"}},
+			wantContains: `govar.genericBox[govar.testData] => {`,
+		},
+		{
+			name:         "alias generic type arguments",
+			configure:    func(cfg *DumperConfig) { cfg.PackageAliases = map[string]string{"github.com/janvaclavik/govar": "gv"} },
+			input:        genericBox[[]*testData]{},
+			wantContains: `gv.genericBox[[]*gv.testData] => {`,
+		},
+		{
+			name: "alias and shorten generic type arguments",
+			configure: func(cfg *DumperConfig) {
+				cfg.PackageAliases = map[string]string{"github.com/janvaclavik": "jv"}
+				cfg.ShortenTypePaths = true
+			},
+			input:        genericBox[map[string]testData]{},
+			wantContains: `jv/govar.genericBox[map[string]jv/govar.testData] => {`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := DefaultConfig
+			cfg.UseColors = false
+			tt.configure(&cfg)
+			out := NewDumper(cfg).Sdump(tt.input)
+			if !strings.Contains(out, tt.wantContains) {
+				t.Errorf("Dump %s: got:\n%s\nwant contains:\n%s", tt.name, out, tt.wantContains)
+			}
+		})
+	}
+}
+
+func TestDumpHiddenMeta(t *testing.T) {
+	type Sample struct {
+		Name  string
+		Items []int
+		Err   error
+	}
+
+	cfg := DefaultConfig
+	cfg.UseColors = false
+	cfg.HiddenMeta = MetaInterfaceHint | MetaStringLength
+	d := NewDumper(cfg)
+
+	out := d.Sdump(Sample{Name: "abc", Items: []int{1, 2}, Err: &customError{}})
+	wantContains := []string{
+		`=> "abc"`,
+		`=> |2| [0 => 1, 1 => 2]`,
+		`=> "custom error"`,
+	}
+	for _, want := range wantContains {
+		if !strings.Contains(out, want) {
+			t.Errorf("HiddenMeta: missing expected fragment:\nwant contains:\n%s\ngot:\n%s", want, out)
+		}
+	}
+	for _, unwanted := range []string{"|R:", "|error:|"} {
+		if strings.Contains(out, unwanted) {
+			t.Errorf("HiddenMeta: output should not contain %q, got:\n%s", unwanted, out)
+		}
+	}
+}
+
+func TestDumpShowIndirection(t *testing.T) {
+	a, b := 1, 2
+	pb := &b
+	type Holder struct {
+		Value  int
+		Ptr    *int
+		PtrPtr **int
+	}
+
+	cfg := DefaultConfig
+	cfg.UseColors = false
+	cfg.ShowIndirection = true
+	d := NewDumper(cfg)
+
+	out := d.Sdump(Holder{Value: 42, Ptr: &a, PtrPtr: &pb})
+	wantContains := []string{
+		`⯀ Value   int   => 42`,
+		`⯀ Ptr     *int  => *→1`,
+		`⯀ PtrPtr  **int => **→2`,
+	}
+	for _, want := range wantContains {
+		if !strings.Contains(out, want) {
+			t.Errorf("ShowIndirection: missing expected fragment:\nwant contains:\n%s\ngot:\n%s", want, out)
+		}
+	}
+}
+
+func TestDumpShallowPointers(t *testing.T) {
+	type Customer struct {
+		ID    int
+		Name  string
+		Email string
+		Tags  []string
+	}
+	type Order struct {
+		Number   int
+		Customer *Customer
+		Items    *[]int
+	}
+
+	cfg := DefaultConfig
+	cfg.UseColors = false
+	cfg.ShallowPointers = true
+	d := NewDumper(cfg)
+
+	out := d.Sdump(&Order{Number: 1, Customer: &Customer{ID: 42, Name: "Ann", Tags: []string{"vip"}}, Items: &[]int{1, 2, 3}})
+	wantContains := []string{
+		`*govar.Order => {`,
+		`⯀ Customer  *govar.Customer => *govar.Customer{ID:42 Name:"Ann" Tags:…} @0x`,
+		`⯀ Items     *[]int          => *[]int{… 3 items} @0x`,
+	}
+	for _, want := range wantContains {
+		if !strings.Contains(out, want) {
+			t.Errorf("ShallowPointers: missing expected fragment:\nwant contains:\n%s\ngot:\n%s", want, out)
+		}
+	}
+
+	out = d.Sdump(&Order{Customer: &Customer{Name: "A\tB"}})
+	if !strings.Contains(out, `Name:"A\tB"`) {
+		t.Errorf("ShallowPointers: expected strings escaped once, got:\n%s", out)
+	}
+}
+
+func TestDumpNaturalMapKeys(t *testing.T) {
+	m := map[string]int{"item10": 10, "item2": 2, "item1": 1}
+
+	cfg := DefaultConfig
+	cfg.UseColors = false
+	cfg.ShowTypes = false
+	cfg.ShowMetaInformation = false
+
+	lexical := NewDumper(cfg).Sdump(m)
+	if !strings.Contains(lexical, `["item1"  => 1, "item10"  => 10, "item2"  => 2]`) {
+		t.Errorf("NaturalMapKeys off: expected lexical key order, got:\n%s", lexical)
+	}
+
+	cfg.NaturalMapKeys = true
+	natural := NewDumper(cfg).Sdump(m)
+	if !strings.Contains(natural, `["item1"  => 1, "item2"  => 2, "item10"  => 10]`) {
+		t.Errorf("NaturalMapKeys on: expected numeric-aware key order, got:\n%s", natural)
+	}
+}
+
+func TestDumpSortMapsByValue(t *testing.T) {
+	cfg := DefaultConfig
+	cfg.UseColors = false
+	cfg.ShowTypes = false
+	cfg.ShowMetaInformation = false
+	cfg.SortMapsByValue = true
+	d := NewDumper(cfg)
+
+	tests := []struct {
+		name         string
+		input        any
+		wantContains string
+	}{
+		{"int counters largest first", map[string]int{"a": 1, "b": 30, "c": 7, "d": 7}, `["b"  => 30, "c"  => 7, "d"  => 7, "a"  => 1]`},
+		{"float values", map[int]float64{1: 0.5, 2: 2.5}, `[2  => 2.500000, 1  => 0.500000]`},
+		{"string values alphabetically", map[int]string{1: "zeta", 2: "alpha"}, `[2  => "alpha", 1  => "zeta"]`},
+		{"non-primitive values keep key order", map[string][]int{"b": {1}, "a": {2, 3}}, `"a" => [0 => 2, 1 => 3]
+   "b" => [0 => 1]`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out := d.Sdump(tt.input)
+			if !strings.Contains(out, tt.wantContains) {
+				t.Errorf("SortMapsByValue %s: got:\n%s\nwant contains:\n%s", tt.name, out, tt.wantContains)
+			}
+		})
+	}
+}
+
+func TestDumpDistinguishNil(t *testing.T) {
+	type Sample struct {
+		NilSlice   []string
+		EmptySlice []string
+		NilMap     map[string]int
+		EmptyMap   map[string]int
+		NilPtr     *int
+	}
+	input := Sample{EmptySlice: []string{}, EmptyMap: map[string]int{}}
+
+	cfg := DefaultConfig
+	cfg.UseColors = false
+	cfg.DistinguishNil = true
+	out := NewDumper(cfg).Sdump(input)
+
+	wantContains := []string{
+		`NilSlice    []string       => <nil slice>`,
+		`EmptySlice  []string       => |0| []`,
+		`NilMap      map[string]int => <nil map>`,
+		`EmptyMap    map[string]int => |0| []`,
+		`NilPtr      *int           => <nil>`,
+	}
+	for _, want := range wantContains {
+		if !strings.Contains(out, want) {
+			t.Errorf("DistinguishNil: missing expected fragment:\nwant contains:\n%s\ngot:\n%s", want, out)
+		}
+	}
+}
+
+func TestDumpAnonymize(t *testing.T) {
+	type Account struct {
+		Owner   string
+		Balance int
+		Rate    float64
+		Tags    map[string]int
+		Err     error
+		Alias   *string
+	}
+	owner := "Alice Smith"
+	input := Account{
+		Owner:   owner,
+		Balance: -1250,
+		Rate:    0.5,
+		Tags:    map[string]int{"vip": 3},
+		Err:     &customError{},
+		Alias:   &owner,
+	}
+
+	cfg := DefaultConfig
+	cfg.UseColors = false
+	cfg.EmbedTypeMethods = false
+
+	t.Run("mask", func(t *testing.T) {
+		cfg.Anonymize = AnonymizeMask
+		out := NewDumper(cfg).Sdump(input)
+		wantContains := []string{
+			`=> &1 |R:11| "xxxxxxxxxxx"`,
+			`=> -####`,
+			`=> #.######`,
+			`"xxx"  => #`,
+			`|error:| "xxxxxxxxxxxx"`,
+			`*string                     => ↩︎ &1`,
+		}
+		for _, want := range wantContains {
+			if !strings.Contains(out, want) {
+				t.Errorf("AnonymizeMask: missing expected fragment:\nwant contains:\n%s\ngot:\n%s", want, out)
+			}
+		}
+		for _, secret := range []string{"Alice", "1250", "vip", "custom error"} {
+			if strings.Contains(out, secret) {
+				t.Errorf("AnonymizeMask: output leaks %q:\n%s", secret, out)
+			}
+		}
+	})
+
+	t.Run("hash is deterministic", func(t *testing.T) {
+		cfg.Anonymize = AnonymizeHash
+		first := NewDumper(cfg).Sdump([]string{"secret", "secret", "other!"})
+		second := NewDumper(cfg).Sdump([]string{"secret", "secret", "other!"})
+		if first != second {
+			t.Errorf("AnonymizeHash should be deterministic, got:\n%s\nand:\n%s", first, second)
+		}
+		if strings.Contains(first, "secret") {
+			t.Errorf("AnonymizeHash: output leaks the string:\n%s", first)
+		}
+		secret, other := NewDumper(cfg).anonymizeText("secret"), NewDumper(cfg).anonymizeText("other!")
+		if len(secret) != len("secret") || secret == other {
+			t.Errorf("AnonymizeHash: expected distinct same-length placeholders, got %q and %q", secret, other)
+		}
+		if strings.Count(first, `"`+secret+`"`) != 2 || !strings.Contains(first, `"`+other+`"`) {
+			t.Errorf("AnonymizeHash: equal strings should map to the same placeholder, got:\n%s", first)
+		}
+	})
+}
+
+// money implements only fmt.Formatter, as some libraries do.
+type money struct {
+	Cents int64
+}
+
+func (m money) Format(f fmt.State, verb rune) {
+	fmt.Fprintf(f, "$%d.%02d", m.Cents/100, m.Cents%100)
+}
+
+func TestDumpUseFormatter(t *testing.T) {
+	input := struct{ Price money }{Price: money{Cents: 1999}}
+
+	cfg := DefaultConfig
+	cfg.UseColors = false
+	cfg.EmbedTypeMethods = false
+	out := NewDumper(cfg).Sdump(input)
+	if !strings.Contains(out, `govar.money => {⯀ Cents int64 => 1999}`) {
+		t.Errorf("UseFormatter off: expected the struct fields, got:\n%s", out)
+	}
+
+	cfg.UseFormatter = true
+	out = NewDumper(cfg).Sdump(input)
+	if !strings.Contains(out, `govar.money => |Formatter:| "$19.99"`) {
+		t.Errorf("UseFormatter on: expected the Format output, got:\n%s", out)
+	}
+
+	cfg.IgnoreStringer = true
+	out = NewDumper(cfg).Sdump(input)
+	if strings.Contains(out, "$19.99") {
+		t.Errorf("IgnoreStringer should also disable Formatter output, got:\n%s", out)
+	}
+}
+
+// level implements only encoding.TextMarshaler.
+type level struct {
+	n int
+}
+
+func (l level) MarshalText() ([]byte, error) {
+	if l.n < 0 {
+		return nil, fmt.Errorf("invalid level")
+	}
+	return []byte(fmt.Sprintf("LEVEL-%d", l.n)), nil
+}
+
+func TestDumpUseTextMarshaler(t *testing.T) {
+	cfg := DefaultConfig
+	cfg.UseColors = false
+	cfg.EmbedTypeMethods = false
+	cfg.UseTextMarshaler = true
+	d := NewDumper(cfg)
+
+	tests := []struct {
+		name         string
+		input        any
+		wantContains string
+	}{
+		{"marshaled text", level{n: 3}, `govar.level => |TextMarshaler:| "LEVEL-3"`},
+		{"marshal error falls back to fields", level{n: -1}, `govar.level => {🞏 n int => -1}`},
+		{"stringer wins", time.Second, `|Stringer:| "1s"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out := d.Sdump(tt.input)
+			if !strings.Contains(out, tt.wantContains) {
+				t.Errorf("UseTextMarshaler %s: got:\n%s\nwant contains:\n%s", tt.name, out, tt.wantContains)
+			}
+		})
+	}
+}
+
+// opaqueConfig hides its state but implements json.Marshaler.
+type opaqueConfig struct {
+	host string
+	port int
+}
+
+func (c opaqueConfig) MarshalJSON() ([]byte, error) {
+	return []byte(fmt.Sprintf(`{"host":%q,"port":%d}`, c.host, c.port)), nil
+}
+
+// publicConfig has exported fields, so structural rendering is kept.
+type publicConfig struct {
+	Host string
+}
+
+func (c publicConfig) MarshalJSON() ([]byte, error) {
+	return []byte(`"ignored"`), nil
+}
+
+func TestDumpUseJSONMarshaler(t *testing.T) {
+	type Service struct {
+		Opaque opaqueConfig
+		Public publicConfig
+	}
+	input := Service{Opaque: opaqueConfig{host: "db", port: 5432}, Public: publicConfig{Host: "web"}}
+
+	cfg := DefaultConfig
+	cfg.UseColors = false
+	cfg.EmbedTypeMethods = false
+	cfg.UseJSONMarshaler = true
+	out := NewDumper(cfg).Sdump(input)
+
+	wantContains := []string{
+		"|JSONMarshaler:| {\n      \"host\": \"db\",\n      \"port\": 5432\n   }",
+		`govar.publicConfig => {⯀ Host string => |R:3| "web"}`,
+	}
+	for _, want := range wantContains {
+		if !strings.Contains(out, want) {
+			t.Errorf("UseJSONMarshaler: missing expected fragment:\nwant contains:\n%s\ngot:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "ignored") {
+		t.Errorf("UseJSONMarshaler should not replace structs with exported fields, got:\n%s", out)
+	}
+
+	cfg.Anonymize = AnonymizeMask
+	if out := NewDumper(cfg).Sdump(input); strings.Contains(out, "|JSONMarshaler:|") || strings.Contains(out, "5432") {
+		t.Errorf("UseJSONMarshaler should not reveal values under Anonymize, got:\n%s", out)
+	}
+}
+
+func TestDumpStringerTypeLists(t *testing.T) {
+	type Event struct {
+		Took time.Duration
+		Err  error
+	}
+	input := Event{Took: 1500 * time.Millisecond, Err: &customError{Code: 7}}
+
+	cfg := DefaultConfig
+	cfg.UseColors = false
+	cfg.EmbedTypeMethods = false
+
+	cfg.IgnoreStringerTypes = []string{"govar.customError"}
+	out := NewDumper(cfg).Sdump(input)
+	if !strings.Contains(out, `|Stringer:| "1.5s"`) || !strings.Contains(out, `{⯀ Code int => 7}`) {
+		t.Errorf("IgnoreStringerTypes: expected Duration as string and customError fields, got:\n%s", out)
+	}
+
+	cfg.IgnoreStringerTypes = nil
+	cfg.IgnoreStringer = true
+	cfg.StringerTypes = []string{"time.Duration"}
+	out = NewDumper(cfg).Sdump(input)
+	if !strings.Contains(out, `|Stringer:| "1.5s"`) || strings.Contains(out, "custom error") {
+		t.Errorf("StringerTypes: expected only Duration to keep its Stringer, got:\n%s", out)
+	}
+}
+
+func TestDumpStringerWithFields(t *testing.T) {
+	input := struct {
+		Err   error
+		Plain error
+		Took  time.Duration
+	}{Err: &customError{Code: 404}, Plain: errors.New("boom"), Took: time.Second}
+
+	cfg := DefaultConfig
+	cfg.UseColors = false
+	cfg.EmbedTypeMethods = false
+	cfg.StringerWithFields = true
+	out := NewDumper(cfg).Sdump(input)
+
+	for _, want := range []string{`"custom error" {⯀ Code int => 404}`, `|error:| "boom"`, `|Stringer:| "1s"`} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in output, got:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, `"boom" {`) {
+		t.Errorf("errors without exported fields should not be expanded, got:\n%s", out)
+	}
+
+	node := NewDumper(cfg).Tree(&customError{Code: 404})[0]
+	if node.Value != `"custom error"` || len(node.Children) != 1 || node.Children[0].Value != "404" {
+		t.Errorf("unexpected tree node: %+v", node)
+	}
+}
+
+type httpError struct {
+	Status int
+	Cause  error
+	op     string
+}
+
+func (e *httpError) Error() string { return e.op + ": status " + fmt.Sprint(e.Status) }
+
+func TestDumpExpandErrorFields(t *testing.T) {
+	err := &httpError{Status: 503, Cause: &httpError{Status: 504, op: "upstream"}, op: "fetch"}
+
+	cfg := DefaultConfig
+	cfg.UseColors = false
+	cfg.EmbedTypeMethods = false
+	cfg.ExpandErrorFields = true
+	out := NewDumper(cfg).Sdump(err)
+
+	for _, want := range []string{`"fetch: status 503" {`, "=> 503", `"upstream: status 504" {`, "Status  int     => 504"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in output, got:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "op ") {
+		t.Errorf("unexported fields should stay hidden, got:\n%s", out)
+	}
+
+	node := NewDumper(cfg).Tree(err)[0]
+	if len(node.Children) != 2 || node.Children[0].Key != "Status" || node.Children[1].Key != "Cause" {
+		t.Errorf("unexpected tree node children: %+v", node.Children)
+	}
+}
+
+func TestDumpExpandJoinedErrors(t *testing.T) {
+	err := errors.Join(errors.New("first"), &httpError{Status: 500, op: "save"})
+
+	cfg := DefaultConfig
+	cfg.UseColors = false
+	cfg.ExpandJoinedErrors = true
+	out := NewDumper(cfg).Sdump(err)
+
+	for _, want := range []string{"|errors.Join:| |2| [", `error(*errors.errorString) => |error:| "first"`, `error(*govar.httpError)    => |error:| "save: status 500"`} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in output, got:\n%s", want, out)
+		}
+	}
+
+	node := NewDumper(cfg).Tree(err)[0]
+	if len(node.Children) != 2 || node.Children[1].Value != `"save: status 500"` {
+		t.Errorf("unexpected tree node children: %+v", node.Children)
+	}
+
+	cfg.ExpandJoinedErrors = false
+	if out := NewDumper(cfg).Sdump(err); !strings.Contains(out, `"first\nsave: status 500"`) {
+		t.Errorf("expected the joined message without ExpandJoinedErrors, got:\n%s", out)
+	}
+}
+
+func TestDumpRainbowDepth(t *testing.T) {
+	input := struct {
+		Tags  []string
+		Inner struct{ Items map[string][]int }
+	}{Tags: []string{"a", "b"}}
+	input.Inner.Items = map[string][]int{"x": {1}}
+
+	cfg := DefaultConfig
+	cfg.UseColors = true
+	cfg.RainbowDepth = true
+	out := NewDumper(cfg).Sdump(input)
+
+	for level, delim := range []string{"{", "[", "["} {
+		want := depthPalette[level] + delim + ColorReset
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q tinted for level %d, got:\n%q", delim, level, out)
+		}
+	}
+	if !strings.Contains(out, depthPalette[0]+"Tags"+ColorReset) {
+		t.Errorf("expected top-level keys tinted like their braces, got:\n%q", out)
+	}
+
+	cfg.RainbowDepth = false
+	if out := NewDumper(cfg).Sdump(input); strings.Contains(out, depthPalette[0]+"{") {
+		t.Errorf("expected plain braces without RainbowDepth, got:\n%q", out)
+	}
+}
+
+func TestDumpAdaptiveInline(t *testing.T) {
+	// The width comes from COLUMNS, as test output is not a terminal.
+	words := []string{"alpha", "bravo", "charlie", "delta", "echo", "foxtrot", "golf", "hotel", "india"}
+	cfg := DefaultConfig
+	cfg.UseColors = false
+	cfg.ShowMetaInformation = false
+
+	tests := []struct {
+		name     string
+		adaptive bool
+		columns  string
+		inline   bool
+	}{
+		{"fixed width", false, "200", false},
+		{"wide terminal", true, "200", true},
+		{"narrow terminal", true, "60", false},
+		{"unknown width", true, "", false},
+	}
+	for _, tt := range tests {
+		t.Setenv("COLUMNS", tt.columns)
+		cfg.AdaptiveInline = tt.adaptive
+		out := NewDumper(cfg).Sdump(words)
+		if got := strings.Contains(out, `[0 => "alpha", `); got != tt.inline {
+			t.Errorf("%s: inline = %v, want %v, got:\n%s", tt.name, got, tt.inline, out)
+		}
+	}
+}
+
+func TestDumpMapSampling(t *testing.T) {
+	type Item struct{ ID int }
+	m := make(map[int]*Item, 1000)
+	for i := range 1000 {
+		m[i] = &Item{ID: i}
+	}
+	cfg := DefaultConfig
+	cfg.UseColors = false
+	cfg.EmbedTypeMethods = false
+	cfg.MaxItems = 5
+	cfg.MapSampleThreshold = 100
+
+	out := NewDumper(cfg).Sdump(m)
+	if !strings.Contains(out, "… (unsorted sample, 5 of 1000 entries)") {
+		t.Errorf("expected a sample marker, got:\n%s", out)
+	}
+	if strings.Count(out, "ID ") != 5 {
+		t.Errorf("expected 5 sampled entries, got:\n%s", out)
+	}
+	tree := NewDumper(cfg).Tree(m)[0]
+	if len(tree.Children) != 5 || !tree.Truncated {
+		t.Errorf("expected 5 truncated tree children, got %d (truncated=%v)", len(tree.Children), tree.Truncated)
+	}
+
+	cfg.MapSampleThreshold = 1000
+	out = NewDumper(cfg).Sdump(m)
+	if strings.Contains(out, "unsorted sample") || !strings.Contains(out, "0 => ") {
+		t.Errorf("maps up to the threshold should be sorted, got:\n%s", out)
+	}
+}
+
+func TestDumpMaxFields(t *testing.T) {
+	type Model struct {
+		A, B, C, D, E int
+		Name          string
+		Tags          []string
+	}
+	cfg := DefaultConfig
+	cfg.UseColors = false
+	cfg.EmbedTypeMethods = false
+	cfg.MaxFields = 3
+
+	out := NewDumper(cfg).Sdump(Model{A: 1, Name: "model", Tags: []string{"x"}})
+	if !strings.Contains(out, "… 4 more fields") {
+		t.Errorf("expected a more-fields marker, got:\n%s", out)
+	}
+	if !strings.Contains(out, "⯀ C") || strings.Contains(out, "⯀ D") || strings.Contains(out, "model") {
+		t.Errorf("expected only the first 3 fields, got:\n%s", out)
+	}
+	if tree := NewDumper(cfg).Tree(Model{})[0]; len(tree.Children) != 3 || !tree.Truncated {
+		t.Errorf("expected 3 truncated tree children, got %d (truncated=%v)", len(tree.Children), tree.Truncated)
+	}
+
+	cfg.MaxFields = 6
+	if out := NewDumper(cfg).Sdump(Model{}); !strings.Contains(out, "… 1 more field\n") {
+		t.Errorf("expected a single-field marker, got:\n%s", out)
+	}
+
+	cfg.MaxFields = 0
+	if out := NewDumper(cfg).Sdump(Model{}); strings.Contains(out, "more field") {
+		t.Errorf("MaxFields 0 should show all fields, got:\n%s", out)
+	}
+
+	type Item struct{ N int }
+	type Shared struct {
+		P *Item
+		V Item
+	}
+	shared := &Shared{}
+	shared.P = &shared.V
+	cfg.MaxFields = 1
+	if out := NewDumper(cfg).Sdump(shared); strings.Contains(out, "↩︎") {
+		t.Errorf("expected no reference to a value defined in a hidden field, got:\n%s", out)
+	}
+}
+
+func TestDumpSkipUnexported(t *testing.T) {
+	type Conn struct {
+		Addr   string
+		secret string
+		Port   int
+		state  *Conn
+	}
+	cfg := DefaultConfig
+	cfg.UseColors = false
+	cfg.EmbedTypeMethods = false
+	cfg.SkipUnexported = true
+
+	conn := Conn{Addr: "localhost", secret: "hunter2", Port: 5432}
+	conn.state = &conn
+	out := NewDumper(cfg).Sdump(conn)
+	if strings.Contains(out, "🞏") || strings.Contains(out, "hunter2") || strings.Contains(out, "state") {
+		t.Errorf("expected unexported fields to be omitted, got:\n%s", out)
+	}
+	if !strings.Contains(out, `⯀ Addr  string => |R:9| "localhost"`) || !strings.Contains(out, "⯀ Port  int    => 5432") {
+		t.Errorf("expected exported fields, got:\n%s", out)
+	}
+
+	cfg.MaxFields = 1
+	if out := NewDumper(cfg).Sdump(conn); !strings.Contains(out, "… 1 more field") {
+		t.Errorf("expected skipped fields not to count towards MaxFields, got:\n%s", out)
+	}
+	if tree := NewDumper(cfg).Tree(conn)[0]; len(tree.Children) != 1 || tree.Children[0].Key != "Addr" {
+		t.Errorf("expected only the Addr tree child, got %+v", tree.Children)
+	}
+}
+
+func TestDumpNoAlignment(t *testing.T) {
+	type Row struct {
+		ID       int
+		Name     string
+		Children []*Row
+	}
+	cfg := DefaultConfig
+	cfg.UseColors = false
+	cfg.EmbedTypeMethods = false
+	cfg.ShowMetaInformation = false
+	cfg.NoAlignment = true
+
+	out := NewDumper(cfg).Sdump(Row{ID: 1, Name: "root"}, map[string]int{"k": 1, "long-key": 2, "nested": 3, "x": 4, "y": 5, "z": 6, "w": 7, "v": 8, "u": 9, "t": 10, "s": 11})
+	for _, want := range []string{
+		"⯀ ID  int => 1\n",
+		"⯀ Name  string => \"root\"\n",
+		"⯀ Children  []*govar.Row => <nil>\n",
+		"\"k\" => 1\n",
+		"\"long-key\" => 2\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected unpadded line %q, got:\n%s", want, out)
+		}
+	}
+}
+
+func TestDumpSparseArrays(t *testing.T) {
+	histogram := make([]int, 40)
+	histogram[3], histogram[31] = 7, 2
+	dense := make([]int, 40)
+	for i := range dense {
+		dense[i] = i + 1
+	}
+	cfg := DefaultConfig
+	cfg.UseColors = false
+	cfg.SparseArrays = true
+
+	out := NewDumper(cfg).Sdump(histogram)
+	for _, want := range []string{"   3  => 7\n", "   31 => 2\n", "… 38 zeros"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in sparse output, got:\n%s", want, out)
+		}
+	}
+	if out := NewDumper(cfg).Sdump(dense); strings.Contains(out, "zeros") {
+		t.Errorf("dense slices should be rendered in full, got:\n%s", out)
+	}
+	if out := NewDumper(cfg).Sdump(make([]int, 8)); strings.Contains(out, "zeros") {
+		t.Errorf("short slices should be rendered in full, got:\n%s", out)
+	}
+	cfg.SparseArrays = false
+	if out := NewDumper(cfg).Sdump(histogram); strings.Contains(out, "zeros") {
+		t.Errorf("SparseArrays disabled should render in full, got:\n%s", out)
+	}
+
+	cfg.SparseArrays = true
+	cfg.SingleLine = true
+	if out := NewDumper(cfg).Sdump(histogram); strings.Contains(out, "zeros") || strings.Count(out, "\n") > 2 {
+		t.Errorf("SingleLine should render the slice inline, got:\n%s", out)
+	}
+	cfg.SingleLine = false
+	type Stats struct {
+		Histogram []int `govar:"format=compact"`
+	}
+	if out := NewDumper(cfg).Sdump(Stats{Histogram: histogram}); strings.Contains(out, "zeros") {
+		t.Errorf("compact fields should render the slice inline, got:\n%s", out)
+	}
+}
+
+func TestDumpMatrixGrid(t *testing.T) {
+	matrix := [3][3]int{{1, 2, 3}, {4, -50, 6}, {7, 8, 900}}
+	cfg := DefaultConfig
+	cfg.UseColors = false
+
+	want := `[3][3]int => |3×3| [
+      0    1    2
+   0  1    2    3
+   1  4  -50    6
+   2  7    8  900
+]`
+	if out := NewDumper(cfg).Sdump(matrix); !strings.Contains(out, want) {
+		t.Errorf("expected a matrix grid, got:\n%s", out)
+	}
+
+	cfg.MaxItems = 2
+	out := NewDumper(cfg).Sdump(matrix)
+	if !strings.Contains(out, "   1  4  -50  …\n") || !strings.Contains(out, "… (truncated)") {
+		t.Errorf("expected truncated rows and columns, got:\n%s", out)
+	}
+
+	cfg.MatrixGrid = false
+	if out := NewDumper(cfg).Sdump(matrix); strings.Contains(out, "3×3") {
+		t.Errorf("MatrixGrid disabled should render nested arrays, got:\n%s", out)
+	}
+	cfg.MatrixGrid = true
+	if out := NewDumper(cfg).Sdump([][]int{{1, 2}, {3, 4}}); strings.Contains(out, "×") {
+		t.Errorf("slices of slices should not be rendered as grids, got:\n%s", out)
+	}
+
+	cfg.SingleLine = true
+	if out := NewDumper(cfg).Sdump(matrix); strings.Contains(out, "×") || strings.Count(out, "\n") > 2 {
+		t.Errorf("SingleLine should render the matrix inline, got:\n%s", out)
+	}
+	cfg.SingleLine = false
+	type Board struct {
+		Cells [2][2]int `govar:"format=compact"`
+	}
+	if out := NewDumper(cfg).Sdump(Board{}); strings.Contains(out, "×") {
+		t.Errorf("compact fields should render the matrix inline, got:\n%s", out)
+	}
+}
+
+func TestDumpShowLegend(t *testing.T) {
+	cfg := DefaultConfig
+	cfg.UseColors = false
+	if out := NewDumper(cfg).Sdump(42); strings.Contains(out, "legend:") {
+		t.Errorf("no legend expected by default, got:\n%s", out)
+	}
+	cfg.ShowLegend = true
+	out := NewDumper(cfg).Sdump(42, "x")
+	if !strings.HasSuffix(out, "↩︎ &1 back-reference   <nil> nil\n") || strings.Count(out, "legend:") != 1 {
+		t.Errorf("expected a single legend at the end, got:\n%s", out)
+	}
+	cfg.UseColors = true
+	if out := NewDumper(cfg).Sdump(42); !strings.Contains(out, "\x1b[") || !strings.Contains(out, "exported field") {
+		t.Errorf("expected a colored legend, got:\n%q", out)
+	}
+}
+
+func TestDumpSmartTypes(t *testing.T) {
+	type Job struct {
+		ID      int
+		Name    string
+		Timeout time.Duration
+		Retries *int
+	}
+	retries := 3
+	cfg := DefaultConfig
+	cfg.UseColors = false
+	cfg.EmbedTypeMethods = false
+	cfg.ShowMetaInformation = false
+	cfg.SmartTypes = true
+
+	out := NewDumper(cfg).Sdump(Job{ID: 7, Name: "sync", Retries: &retries}, 42)
+	for _, want := range []string{
+		"⯀ ID                     => 7\n",
+		"⯀ Name                   => \"sync\"\n",
+		"⯀ Timeout  time.Duration => ",
+		"⯀ Retries  *int          => 3\n",
+		"\n42\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q, got:\n%s", want, out)
+		}
+	}
+
+	cfg.UseColors = true
+	out = NewDumper(cfg).Sdump(42)
+	if regexp.MustCompile(`\x1b\[[0-9;]*m\x1b\[0m`).MatchString(out) || strings.Contains(out, " =>") {
+		t.Errorf("expected no colored empty type name, got:\n%q", out)
+	}
+}
+
+func TestDumpInterfaceTypes(t *testing.T) {
+	type myErr struct{}
+	type Result struct {
+		Value any
+		Err   any
+	}
+	var nilErr *myErr
+	input := Result{Value: 42, Err: nilErr}
+	cfg := DefaultConfig
+	cfg.UseColors = false
+	cfg.EmbedTypeMethods = false
+
+	tests := []struct {
+		name      string
+		types     InterfaceTypeMode
+		typedNils TypedNilStyle
+		want      []string
+	}{
+		{"defaults", InterfaceBoth, TypedNilGo, []string{"Value  ⧉ any(int)          => 42", "Err    ⧉ any(*govar.myErr) => (*govar.myErr)(nil)\n"}},
+		{"static only", InterfaceStatic, TypedNilPlain, []string{"Value  ⧉ any => 42", "Err    ⧉ any => <nil>\n"}},
+		{"dynamic only", InterfaceDynamic, TypedNilAnnotated, []string{"Value  int          => 42", "Err    *govar.myErr => (*govar.myErr)(nil) ⚠ non-nil interface\n"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg.InterfaceTypes, cfg.TypedNils = tt.types, tt.typedNils
+			out := NewDumper(cfg).Sdump(input)
+			for _, want := range tt.want {
+				if !strings.Contains(out, want) {
+					t.Errorf("expected %q, got:\n%s", want, out)
+				}
+			}
+		})
+	}
+}
+
+func TestDumpCompositeMapKeys(t *testing.T) {
+	type Point struct{ Lat, Lon float64 }
+	type Shard struct {
+		Region string
+		Zone   string
+		Tags   [3]string
+	}
+	cfg := DefaultConfig
+	cfg.UseColors = false
+	cfg.EmbedTypeMethods = false
+	cfg.ShowMetaInformation = false
+
+	out := NewDumper(cfg).Sdump(map[Point]string{{50.1, 14.4}: "prague"})
+	if !strings.Contains(out, `{Lat: 50.1, Lon: 14.4} => "prague"`) {
+		t.Errorf("struct keys should show their field names, got:\n%s", out)
+	}
+
+	out = NewDumper(cfg).Sdump(map[*Point]int{{1, 2}: 1})
+	if !strings.Contains(out, "&{Lat: 1, Lon: 2} => 1") {
+		t.Errorf("pointer keys should show their target, got:\n%s", out)
+	}
+
+	cfg.MaxInlineLength = 40
+	out = NewDumper(cfg).Sdump(map[Shard]int{{"europe-central", "zone-a", [3]string{"primary", "ssd", "backup"}}: 1})
+	if !strings.Contains(out, "   govar.Shard {\n      ⯀ Region  string    => \"europe-central\"\n") || !strings.Contains(out, "\n   } => 1\n") {
+		t.Errorf("large keys should be rendered as blocks, got:\n%s", out)
+	}
+	tree := NewDumper(cfg).Tree(map[Shard]int{{"europe-central", "zone-a", [3]string{}}: 1})
+	if key := tree[0].Children[0].Key; key != `{Region: "europe-central", Zone: "zone-…` {
+		t.Errorf("keys outside block maps should be truncated, got %q", key)
+	}
+
+	out = NewDumper(cfg).Sdump(map[panickyKey]int{{}: 1})
+	if !strings.Contains(out, "PANIC=String method: boom") {
+		t.Errorf("keys with a panicking String method should be rendered, got:\n%s", out)
+	}
+}
+
+// panickyKey is a map key whose String method panics.
+type panickyKey struct{ ID int }
+
+func (panickyKey) String() string { panic("boom") }
+
+func TestDumpRenderStats(t *testing.T) {
+	cfg := DefaultConfig
+	cfg.UseColors = false
+	cfg.EmbedTypeMethods = false
+	cfg.ShowRenderStats = true
+	out := NewDumper(cfg).Sdump([]int{1, 2, 3}, "x")
+	// The slice and its three elements, and the string.
+	if !strings.Contains(out, "|rendered 5 nodes in ") {
+		t.Errorf("expected a render stats footer, got:\n%s", out)
+	}
+
+	cfg.MaxItems = 2000
+	big := make([]int, 1500)
+	out = NewDumper(cfg).Sdump(big)
+	if !strings.Contains(out, "|rendered 1,501 nodes in ") {
+		t.Errorf("expected a grouped node count, got:\n%s", out)
+	}
+}
+
+func TestDumpMaxPaddingWidth(t *testing.T) {
+	type Config struct {
+		ID       int
+		Handlers map[string]map[string][]func(*strings.Builder, string) error
+		Name     string
+	}
+	cfg := DefaultConfig
+	cfg.UseColors = false
+	cfg.EmbedTypeMethods = false
+	out := NewDumper(cfg).Sdump(Config{ID: 1, Name: "api"})
+	// The long type is left unaligned instead of widening the type column.
+	for _, want := range []string{"⯀ ID        int    => 1", "⯀ Name      string => |R:3| \"api\""} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q, got:\n%s", want, out)
+		}
+	}
+
+	cfg.MaxPaddingWidth = 0
+	out = NewDumper(cfg).Sdump(Config{ID: 1, Name: "api"})
+	if !strings.Contains(out, "⯀ ID        int"+strings.Repeat(" ", 58)+"=> 1") {
+		t.Errorf("expected uncapped alignment, got:\n%s", out)
+	}
+
+	// A column without any type short enough to align still shows every type.
+	type Handlers struct {
+		OnRead  map[string]map[string][]func(*strings.Builder, string) error
+		OnWrite map[string]map[string][]func(*strings.Builder, string, int) error
+	}
+	cfg.MaxPaddingWidth = 40
+	out = NewDumper(cfg).Sdump(Handlers{})
+	for _, want := range []string{
+		"⯀ OnRead   map[string]map[string][]func(*strings.Builder, string) error => ",
+		"⯀ OnWrite  map[string]map[string][]func(*strings.Builder, string, int) error => ",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q, got:\n%s", want, out)
+		}
+	}
+}
+
+// panickyItem panics when rendered, like a collection read while another
+// goroutine modifies it.
+type panickyItem struct{}
+
+func (panickyItem) String() string {
+	var shrunk []string
+	i := 0
+	return shrunk[i]
+}
+
+// brokenItem has a String method that fails for reasons unrelated to races.
+type brokenItem struct{}
+
+func (brokenItem) String() string { panic("broken String method") }
+
+func TestDumpRecoverRaces(t *testing.T) {
+	type Snapshot struct {
+		Items  []panickyItem
+		Counts map[string]int
+	}
+	cfg := DefaultConfig
+	cfg.UseColors = false
+	cfg.EmbedTypeMethods = false
+	cfg.RecoverRaces = true
+	out := NewDumper(cfg).Sdump(Snapshot{Items: []panickyItem{{}}, Counts: map[string]int{"a": 1}})
+	for _, want := range []string{"⯀ Items   []govar.panickyItem => <concurrently modified>", `["a"  => 1]`} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q, got:\n%s", want, out)
+		}
+	}
+
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Error("expected panics other than race symptoms to propagate")
+			}
+		}()
+		NewDumper(cfg).Sdump([]brokenItem{{}})
+	}()
+
+	cfg.RecoverRaces = false
+	defer func() {
+		if recover() == nil {
+			t.Error("expected the panic to propagate without RecoverRaces")
+		}
+	}()
+	NewDumper(cfg).Sdump([]panickyItem{{}})
+}
+
+func TestDumpVeryDeepStructure(t *testing.T) {
+	type link struct {
+		Next *link
+		ID   int
+	}
+	const depth = 20000
+	var head *link
+	for i := depth; i > 0; i-- {
+		head = &link{Next: head, ID: i}
+	}
+
+	// A small stack limit makes an unbounded recursion fail fast.
+	defer debug.SetMaxStack(debug.SetMaxStack(16 << 20))
+
+	cfg := DefaultConfig
+	cfg.UseColors = false
+	cfg.IndentWidth = 0
+	cfg.MaxDepth = 2 * depth
+	d := NewDumper(cfg)
+
+	out := d.Sdump(head)
+	if !strings.Contains(out, "ID    int         => 20000") {
+		t.Errorf("expected the last link to be rendered, got:\n%s", out[len(out)-300:])
+	}
+	if got, want := deepSize(reflect.ValueOf(head)), int64(8+depth*16); got != want {
+		t.Errorf("deepSize() = %d, want %d", got, want)
+	}
+	nodes := d.Tree(head)
+	for i := 1; i < depth; i++ {
+		nodes = nodes[0].Children[:1]
+	}
+	if got := nodes[0].Children[1].Value; got != "20000" {
+		t.Errorf("expected the last link in the tree, got %q", got)
+	}
+}
+
+func TestEstimatedInlineLengthMemoized(t *testing.T) {
+	type cell struct{ A, B int }
+	input := [][]cell{{{1, 2}, {3, 4}}, {{5, 6}}}
+
+	cfg := DefaultConfig
+	cfg.UseColors = false
+	d := NewDumper(cfg)
+	want := d.Sdump(input)
+	if len(d.inlineLengths) == 0 {
+		t.Fatal("expected estimates to be memoized during the render pass")
+	}
+
+	v := reflect.ValueOf(&input).Elem()
+	if got, fresh := d.estimatedInlineLength(v), d.measureInlineLength(v); got != fresh {
+		t.Errorf("memoized estimate = %d, want %d", got, fresh)
+	}
+
+	// Each pass starts from a clean cache, so changed values are measured again.
+	input[1][0].A = 123456789
+	if got := d.Sdump(input); got == want || !strings.Contains(got, "123456789") {
+		t.Errorf("expected the changed value to be rendered, got:\n%s", got)
+	}
+}
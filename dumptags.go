@@ -0,0 +1,90 @@
+// Package govar provides a powerful and highly configurable pretty-printer for Go
+// data structures. This file implements tagged dumps: DumpT only prints when its
+// tag is enabled by the GOVAR_TAGS environment variable or SetTags, so
+// instrumentation can stay in the code and be switched on selectively.
+package govar
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// TagsEnvVar is the environment variable holding the tags enabled for DumpT
+// when the program starts, in the syntax of SetTags.
+const TagsEnvVar = "GOVAR_TAGS"
+
+// tagFilter decides which tags are enabled.
+type tagFilter struct {
+	include []string // path.Match patterns of enabled tags.
+	exclude []string // path.Match patterns of tags disabled despite matching include.
+}
+
+var (
+	tagFilterOnce   sync.Once
+	activeTagFilter atomic.Pointer[tagFilter]
+)
+
+// parseTagFilter parses a comma-separated list of tag patterns.
+func parseTagFilter(spec string) *tagFilter {
+	f := &tagFilter{}
+	for _, pattern := range strings.Split(spec, ",") {
+		pattern = strings.TrimSpace(pattern)
+		if excluded, ok := strings.CutPrefix(pattern, "-"); ok {
+			f.exclude = append(f.exclude, excluded)
+		} else if pattern != "" {
+			f.include = append(f.include, pattern)
+		}
+	}
+	return f
+}
+
+// matchesTag reports whether tag matches one of the patterns.
+func matchesTag(patterns []string, tag string) bool {
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, tag); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// SetTags replaces the enabled tags, initially read from GOVAR_TAGS. The spec
+// is a comma-separated list of path.Match patterns; patterns prefixed with "-"
+// disable tags. For example "db,http.*" enables the tag "db" and the tags
+// starting with "http.", and "*,-sql" enables all tags except "sql". An empty
+// spec disables all tags.
+func SetTags(spec string) {
+	tagFilterOnce.Do(func() {}) // The environment must not override it later.
+	activeTagFilter.Store(parseTagFilter(spec))
+}
+
+// TagEnabled reports whether dumps with the given tag are printed. Use it to
+// skip preparing values that are only dumped.
+func TagEnabled(tag string) bool {
+	tagFilterOnce.Do(func() { activeTagFilter.Store(parseTagFilter(os.Getenv(TagsEnvVar))) })
+	f := activeTagFilter.Load()
+	return matchesTag(f.include, tag) && !matchesTag(f.exclude, tag)
+}
+
+// DumpT prints values to stdout like Dump, under a "[tag]" line, if the tag is
+// enabled (see SetTags); otherwise it does nothing.
+func (d *Dumper) DumpT(tag string, vs ...any) {
+	if !TagEnabled(tag) {
+		return
+	}
+	fmt.Fprintln(stdoutWriter(), d.sdumpT(tag, vs...))
+}
+
+// sdumpT renders the output of DumpT.
+func (d *Dumper) sdumpT(tag string, vs ...any) string {
+	d.Formatter = d.textFormatter(d.config.UseColors)
+	sb := &strings.Builder{}
+	d.renderHeader(sb)
+	sb.WriteString(d.ApplyFormat(ColorGoldenrod, "["+tag+"]") + "\n")
+	d.renderAllValues(sb, vs...)
+	return sb.String()
+}
@@ -0,0 +1,33 @@
+package govar
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTagEnabled(t *testing.T) {
+	defer SetTags("")
+
+	for spec, cases := range map[string]map[string]bool{
+		"":          {"db": false},
+		"db,http.*": {"db": true, "http.client": true, "http": false, "sql": false},
+		"*,-sql":    {"db": true, "sql": false},
+		" db , ":    {"db": true},
+	} {
+		SetTags(spec)
+		for tag, want := range cases {
+			if got := TagEnabled(tag); got != want {
+				t.Errorf("with tags %q, TagEnabled(%q) = %v, want %v", spec, tag, got, want)
+			}
+		}
+	}
+}
+
+func TestSdumpT(t *testing.T) {
+	cfg := DefaultConfig
+	cfg.UseColors = false
+	out := NewDumper(cfg).sdumpT("db", 42)
+	if !strings.Contains(out, "[db]\nint => 42\n") {
+		t.Errorf("expected the tag above the values, got:\n%s", out)
+	}
+}
@@ -0,0 +1,39 @@
+// Package govar provides a powerful and highly configurable pretty-printer for Go
+// data structures. This file implements the global kill-switch: when dumping is
+// disabled (GOVAR_DISABLE=1 or SetEnabled(false)), the package-level dump
+// functions return before doing any work, so instrumented builds run at
+// near-zero cost.
+package govar
+
+import (
+	"os"
+	"strconv"
+	"sync/atomic"
+)
+
+// DisableEnvVar is the environment variable that disables the package-level
+// dump functions when set to a true value ("1", "true", …) at program start.
+const DisableEnvVar = "GOVAR_DISABLE"
+
+// dumpingDisabled is checked by the package-level dump functions before they
+// create a Dumper.
+var dumpingDisabled atomic.Bool
+
+func init() {
+	disabled, _ := strconv.ParseBool(os.Getenv(DisableEnvVar))
+	dumpingDisabled.Store(disabled)
+}
+
+// SetEnabled turns the package-level dump functions (Dump, Edump, Fdump,
+// Sdump, Trace, Count, Watch, …) on or off, overriding GOVAR_DISABLE. While
+// disabled, they return immediately and the Sdump variants return "", while
+// Sprintf, Printf, and Fprintf format like package fmt. Die still exits,
+// without dumping, and Dumper methods are not affected.
+func SetEnabled(enabled bool) {
+	dumpingDisabled.Store(!enabled)
+}
+
+// Enabled reports whether the package-level dump functions produce output.
+func Enabled() bool {
+	return !dumpingDisabled.Load()
+}
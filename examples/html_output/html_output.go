@@ -19,5 +19,8 @@ func main() {
 	}
 	defer file.Close()
 
-	file.WriteString(govar.SdumpHTML(data))
+	// Write a complete HTML document that can be opened in a browser directly
+	cfg := govar.DefaultConfig
+	cfg.HTMLDocument = true
+	file.WriteString(govar.NewDumper(cfg).SdumpHTML(data))
 }
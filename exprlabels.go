@@ -0,0 +1,90 @@
+// Package govar provides a powerful and highly configurable pretty-printer for Go
+// data structures. This file implements the ShowExpressions option: the source
+// file of the dump call is parsed to label each top-level value with the
+// expression it was passed as (`people → []*main.Person …`).
+package govar
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// callExpressionsCache holds the results of callExpressions.
+var callExpressionsCache sync.Map // "file:line:funcName" -> []string
+
+// expressionLabels returns the source expressions of the last n arguments of
+// the dump call that is being rendered, if ShowExpressions is enabled and they
+// can be determined. Literal arguments get an empty label.
+func (d *Dumper) expressionLabels(n int) []string {
+	if !d.config.ShowExpressions {
+		return nil
+	}
+	file, line, govarFuncName := findCallerInStack()
+	if file == "" {
+		return nil
+	}
+	args := callExpressions(file, line, govarFuncName[strings.LastIndex(govarFuncName, ".")+1:])
+	if len(args) < n {
+		return nil // e.g. values spread from a slice with vs...
+	}
+	return args[len(args)-n:]
+}
+
+// callExpressions returns the source expressions of the arguments of the call
+// to a function or method named funcName on the given line of a Go source
+// file, with "" for literals. It returns nil if the file cannot be parsed, if
+// the line holds no such call or several, or if the last argument is spread
+// with "...".
+func callExpressions(file string, line int, funcName string) []string {
+	key := file + ":" + strconv.Itoa(line) + ":" + funcName
+	if cached, ok := callExpressionsCache.Load(key); ok {
+		return cached.([]string)
+	}
+	var args []string
+	fset := token.NewFileSet()
+	if f, err := parser.ParseFile(fset, file, nil, parser.SkipObjectResolution); err == nil {
+		var calls []*ast.CallExpr
+		ast.Inspect(f, func(n ast.Node) bool {
+			call, ok := n.(*ast.CallExpr)
+			if !ok || calledName(call.Fun) != funcName {
+				return true
+			}
+			// The reported line may be anywhere from the function name to the closing parenthesis.
+			if fset.Position(call.Fun.End()).Line <= line && line <= fset.Position(call.Rparen).Line {
+				calls = append(calls, call)
+			}
+			return true
+		})
+		if len(calls) == 1 && !calls[0].Ellipsis.IsValid() {
+			args = make([]string, len(calls[0].Args))
+			for i, arg := range calls[0].Args {
+				if _, literal := arg.(*ast.BasicLit); !literal {
+					args[i] = types.ExprString(arg)
+				}
+			}
+		}
+	}
+	callExpressionsCache.Store(key, args)
+	return args
+}
+
+// calledName returns the name of the function or method called by an
+// expression like Dump, govar.Dump, d.Dump, or DumpTypeOf[T].
+func calledName(fun ast.Expr) string {
+	switch fun := ast.Unparen(fun).(type) {
+	case *ast.Ident:
+		return fun.Name
+	case *ast.SelectorExpr:
+		return fun.Sel.Name
+	case *ast.IndexExpr:
+		return calledName(fun.X)
+	case *ast.IndexListExpr:
+		return calledName(fun.X)
+	}
+	return ""
+}
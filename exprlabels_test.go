@@ -0,0 +1,47 @@
+package govar
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestCallExpressions(t *testing.T) {
+	src := `package main
+
+func main() {
+	govar.Dump(people, cfg.Timeout, 42)
+	d.Fdump(os.Stderr,
+		users[0],
+		len(users))
+	govar.Dump(all...)
+	govar.Dump(a); govar.Dump(b)
+}
+`
+	file := filepath.Join(t.TempDir(), "main.go")
+	if err := os.WriteFile(file, []byte(src), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tc := range []struct {
+		line     int
+		funcName string
+		want     []string
+	}{
+		{4, "Dump", []string{"people", "cfg.Timeout", ""}},
+		{6, "Fdump", []string{"os.Stderr", "users[0]", "len(users)"}},
+		{4, "Sdump", nil},
+		{8, "Dump", nil},
+		{9, "Dump", nil},
+		{1, "Dump", nil},
+	} {
+		if got := callExpressions(file, tc.line, tc.funcName); !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("callExpressions(line %d, %s) = %q, want %q", tc.line, tc.funcName, got, tc.want)
+		}
+	}
+
+	if got := callExpressions(filepath.Join(t.TempDir(), "missing.go"), 1, "Dump"); got != nil {
+		t.Errorf("expected nil for a missing file, got %q", got)
+	}
+}
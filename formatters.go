@@ -25,9 +25,16 @@ func (f *PlainFormatter) ApplyFormat(colorCode string, str string) string {
 
 // ANSIcolorFormatter implements the Formatter interface using ANSI
 // escape codes to apply terminal color formatting.
-type ANSIcolorFormatter struct{}
+type ANSIcolorFormatter struct {
+	// Palette optionally replaces color codes, e.g. with LightPalette.
+	// Codes missing from it are used as they are.
+	Palette map[string]string
+}
 
 func (f *ANSIcolorFormatter) ApplyFormat(colorCode string, str string) string {
+	if replacement, ok := f.Palette[colorCode]; ok {
+		colorCode = replacement
+	}
 	return colorCode + str + ColorReset
 }
 
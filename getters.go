@@ -0,0 +1,163 @@
+// Package govar provides a powerful and highly configurable pretty-printer for Go
+// data structures. This file implements the CallGetters option: accessor-like
+// methods of structs (Len, Err, IsZero, …, plus those listed in GetterNames)
+// are called and their results shown after the fields, for types whose state is
+// only reachable through methods. Calls that panic or hang are reported instead
+// of breaking the dump.
+package govar
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// getterTimeout is how long a getter may run before it is reported as timed
+// out. The call itself cannot be stopped and keeps running in the background.
+const getterTimeout = 100 * time.Millisecond
+
+// hungGetters records the getters that timed out, keyed by getterKey. They are
+// not called again, so each can leave at most one goroutine behind.
+var hungGetters sync.Map
+
+// getterKey identifies a getter of a type in hungGetters.
+type getterKey struct {
+	typ  reflect.Type
+	name string
+}
+
+// getterNames are the accessor names called by CallGetters, in addition to
+// those matched by isGetterName's prefix rules.
+var getterNames = map[string]bool{
+	"Len": true, "Cap": true, "Size": true, "Count": true,
+	"Err": true, "Error": true, "String": true, "Name": true, "ID": true,
+}
+
+// getterResult is the outcome of calling a getter: its value, or a description
+// of why there is none (a panic or a timeout).
+type getterResult struct {
+	name    string
+	value   reflect.Value
+	failure string
+}
+
+// isGetterName reports whether a method name looks like a side-effect free
+// accessor: one of getterNames or a predicate or count (IsZero, HasItems,
+// CanRetry, NumFields). Names like Close, Next, or Reset are never called, and
+// neither are GetX or XString methods, which often fetch or build something.
+func isGetterName(name string) bool {
+	if getterNames[name] {
+		return true
+	}
+	for _, prefix := range []string{"Is", "Has", "Can", "Num"} {
+		if rest, ok := strings.CutPrefix(name, prefix); ok && rest != "" {
+			if r, _ := utf8.DecodeRuneInString(rest); unicode.IsUpper(r) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// callGetters calls the getters of the struct v: exported methods with a
+// getter-like name or one listed in GetterNames, no arguments and a single
+// result. Methods with pointer receivers are called only if v is addressable.
+// Results are sorted by name.
+func (d *Dumper) callGetters(v reflect.Value) []getterResult {
+	if !d.config.CallGetters || v.Kind() != reflect.Struct {
+		return nil
+	}
+	if !v.CanInterface() {
+		v = tryExport(v)
+		if !v.CanInterface() {
+			return nil
+		}
+	}
+	recv := v
+	if v.CanAddr() {
+		recv = v.Addr()
+	}
+
+	var results []getterResult
+	for _, m := range findTypeMethods(v.Type()) {
+		if !isGetterName(m.Name) && !slices.Contains(d.config.GetterNames, m.Name) {
+			continue
+		}
+		method := recv.MethodByName(m.Name)
+		if !method.IsValid() || method.Type().NumIn() != 0 || method.Type().NumOut() != 1 {
+			continue
+		}
+		results = append(results, callGetter(getterKey{v.Type(), m.Name}, method))
+	}
+	sort.Slice(results, func(i, j int) bool { return results[i].name < results[j].name })
+	return results
+}
+
+// callGetter calls method on its own goroutine, guarding against panics and
+// calls that do not return within getterTimeout. Getters that timed out before
+// are not called again.
+func callGetter(key getterKey, method reflect.Value) getterResult {
+	name := key.name
+	if _, hung := hungGetters.Load(key); hung {
+		return getterResult{name: name, failure: "<skipped, timed out before>"}
+	}
+	done := make(chan getterResult, 1)
+	go func() {
+		defer func() {
+			if p := recover(); p != nil {
+				done <- getterResult{name: name, failure: fmt.Sprintf("<panic: %v>", p)}
+			}
+		}()
+		done <- getterResult{name: name, value: method.Call(nil)[0]}
+	}()
+	select {
+	case result := <-done:
+		return result
+	case <-time.After(getterTimeout):
+		hungGetters.Store(key, true)
+		return getterResult{name: name, failure: "<timed out after " + getterTimeout.String() + ">"}
+	}
+}
+
+// renderGetters writes the results of getters as synthetic fields of a struct
+// rendered as a block, aligned with its fields (e.g. "⦿ Len()  int => 3").
+func (d *Dumper) renderGetters(sb *strings.Builder, getters []getterResult, maxKeyLen, maxTypeLen, level int) {
+	for _, getter := range getters {
+		label := getter.name + "()"
+		line := padRight(d.ApplyFormat(ColorDarkTeal, "⦿ ")+d.ApplyFormat(ColorMutedBlue, label), utf8.RuneCountInString(label)+2, maxKeyLen)
+		if getter.failure == "" {
+			if typ := d.formatType(getter.value, false); typ != "" || maxTypeLen > 0 {
+				line += "  " + padRight(typ, utf8.RuneCountInString(d.formatTypeNoColors(getter.value, false)), maxTypeLen)
+			}
+		} else if maxTypeLen > 0 {
+			line += "  " + strings.Repeat(" ", maxTypeLen)
+		}
+		d.renderIndent(sb, level, line+" => ")
+		if getter.failure != "" {
+			sb.WriteString(d.ApplyFormat(ColorCoralRed, getter.failure))
+		} else {
+			d.renderValue(sb, getter.value, level, false)
+		}
+		d.renderBlockLineEnd(sb)
+	}
+}
+
+// getterPadding widens the key and type columns of a struct for its getters.
+func (d *Dumper) getterPadding(getters []getterResult, maxKeyLen, maxTypeLen int) (int, int) {
+	if d.config.NoAlignment {
+		return maxKeyLen, maxTypeLen
+	}
+	for _, getter := range getters {
+		maxKeyLen = d.widenPadding(maxKeyLen, utf8.RuneCountInString(getter.name)+4) // symbol and "()"
+		if getter.failure == "" {
+			maxTypeLen = d.widenPadding(maxTypeLen, utf8.RuneCountInString(d.formatTypeNoColors(getter.value, false)))
+		}
+	}
+	return maxKeyLen, maxTypeLen
+}
@@ -0,0 +1,67 @@
+package govar
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+type queue struct {
+	items []string
+	err   error
+}
+
+func (q *queue) Count() int       { return len(q.items) }
+func (q *queue) Len() int         { return len(q.items) }
+func (q queue) Err() error        { return q.err }
+func (q queue) IsEmpty() bool     { return len(q.items) == 0 }
+func (q queue) GetHead() string   { return q.items[0] }
+func (q queue) HasDeadline() bool { time.Sleep(time.Second); return false }
+func (q *queue) Pop() string      { q.items = q.items[1:]; return "" }
+
+func TestIsGetterName(t *testing.T) {
+	for name, want := range map[string]bool{
+		"Len": true, "Err": true, "IsZero": true, "NumField": true,
+		"GetOwner": false, "DebugString": false, "Close": false, "Next": false, "Reset": false, "Issue": false, "Get": false, "Pop": false,
+	} {
+		if got := isGetterName(name); got != want {
+			t.Errorf("isGetterName(%q) = %v, want %v", name, got, want)
+		}
+	}
+}
+
+func TestDumpCallGetters(t *testing.T) {
+	cfg := DefaultConfig
+	cfg.UseColors = false
+	cfg.EmbedTypeMethods = false
+	cfg.CallGetters = true
+	cfg.GetterNames = []string{"GetHead"}
+	q := &queue{}
+	out := NewDumper(cfg).Sdump(q)
+	for _, want := range []string{
+		"⦿ Err()          ⧉ error  => <nil>",
+		"⦿ GetHead()               => <panic: runtime error: index out of range [0] with length 0>",
+		"⦿ HasDeadline()           => <timed out after 100ms>",
+		"⦿ IsEmpty()      bool     => true",
+		"⦿ Len()          int      => 0",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q, got:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "Pop()") {
+		t.Errorf("methods that are not getters must not be called, got:\n%s", out)
+	}
+	if i, j := strings.Index(out, "Count()"), strings.Index(out, "Err()"); i < 0 || j < i {
+		t.Errorf("getters should be sorted by name, got:\n%s", out)
+	}
+
+	out = NewDumper(cfg).Sdump(q)
+	if !strings.Contains(out, "⦿ HasDeadline()           => <skipped, timed out before>") {
+		t.Errorf("getters that timed out should not be called again, got:\n%s", out)
+	}
+	cfg.GetterNames = nil
+	if out = NewDumper(cfg).Sdump(q); strings.Contains(out, "GetHead()") {
+		t.Errorf("GetX methods should only be called when listed in GetterNames, got:\n%s", out)
+	}
+}
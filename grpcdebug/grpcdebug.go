@@ -0,0 +1,258 @@
+// Package grpcdebug provides gRPC client and server interceptors that dump the
+// messages and metadata of calls with govar. They are meant for development:
+// install them with grpc.ChainUnaryInterceptor / grpc.ChainStreamInterceptor
+// (or the matching dial options) and switch them on and off with WithEnabled.
+//
+// This package is a separate module, so depending on govar does not pull in
+// gRPC.
+package grpcdebug
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/janvaclavik/govar"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// defaultMaxSize caps the size of each rendered message unless WithMaxSize
+// selects another limit.
+const defaultMaxSize = 64 << 10
+
+// Option configures the interceptors.
+type Option func(*options)
+
+// options holds the settings applied by Option values.
+type options struct {
+	cfg     govar.DumperConfig
+	out     io.Writer
+	enabled func() bool
+	methods []string
+	maxSize int
+
+	mu sync.Mutex
+}
+
+// WithConfig renders dumps with cfg. By default, govar.DefaultConfig is used
+// without embedded type methods, which would list every generated protobuf
+// accessor.
+func WithConfig(cfg govar.DumperConfig) Option {
+	return func(o *options) { o.cfg = cfg }
+}
+
+// WithWriter writes dumps to w instead of stderr.
+func WithWriter(w io.Writer) Option {
+	return func(o *options) { o.out = w }
+}
+
+// WithEnabled makes the interceptors dump a call only if enabled returns true
+// when the call starts, e.g. a flag toggled at runtime. Calls that are not
+// dumped pass through without overhead.
+func WithEnabled(enabled func() bool) Option {
+	return func(o *options) { o.enabled = enabled }
+}
+
+// WithMethods restricts dumping to the methods whose full name matches one of
+// the path.Match patterns, e.g. "/shop.Cart/AddItem" or "/shop.Cart/*".
+func WithMethods(patterns ...string) Option {
+	return func(o *options) { o.methods = append(o.methods, patterns...) }
+}
+
+// WithMaxSize cuts each rendered message and metadata dump after n bytes
+// (64 KiB by default; 0 disables the limit).
+func WithMaxSize(n int) Option {
+	return func(o *options) { o.maxSize = n }
+}
+
+// newOptions applies opts to the defaults.
+func newOptions(opts []Option) *options {
+	o := &options{cfg: govar.DefaultConfig, out: os.Stderr, maxSize: defaultMaxSize}
+	o.cfg.EmbedTypeMethods = false
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// enabledFor reports whether calls of the given full method name are dumped.
+func (o *options) enabledFor(method string) bool {
+	if o.enabled != nil && !o.enabled() {
+		return false
+	}
+	if len(o.methods) == 0 {
+		return true
+	}
+	for _, pattern := range o.methods {
+		if ok, _ := path.Match(pattern, method); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// dump writes a line describing an event of a call, followed by the call's
+// metadata, if any, and the given values.
+func (o *options) dump(method, event string, md metadata.MD, values ...any) {
+	sb := &strings.Builder{}
+	fmt.Fprintf(sb, "[grpc] %s %s\n", method, event)
+	if len(md) > 0 {
+		sb.WriteString(o.render(md))
+	}
+	for _, v := range values {
+		sb.WriteString(o.render(v))
+	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	io.WriteString(o.out, sb.String())
+}
+
+// render renders v, cut after maxSize bytes. Each call gets its own Dumper, as
+// interceptors run concurrently. The cut never splits a character or a color
+// escape sequence, and colors are reset after it.
+func (o *options) render(v any) string {
+	text := govar.NewDumper(o.cfg).Record(govar.RecordText, v).Text
+	if o.maxSize <= 0 || len(text) <= o.maxSize {
+		return text
+	}
+	cut := o.maxSize
+	for cut > 0 && !utf8.RuneStart(text[cut]) {
+		cut--
+	}
+	if esc := strings.LastIndexByte(text[:cut], '\x1b'); esc >= 0 && !strings.Contains(text[esc:cut], "m") {
+		cut = esc
+	}
+	reset := ""
+	if strings.Contains(text[:cut], "\x1b[") {
+		reset = govar.ColorReset
+	}
+	return fmt.Sprintf("%s%s… (%d more bytes)\n", text[:cut], reset, len(text)-cut)
+}
+
+// UnaryServerInterceptor returns a server interceptor dumping the incoming
+// metadata and request of unary calls, and their response or error.
+func UnaryServerInterceptor(opts ...Option) grpc.UnaryServerInterceptor {
+	o := newOptions(opts)
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if !o.enabledFor(info.FullMethod) {
+			return handler(ctx, req)
+		}
+		md, _ := metadata.FromIncomingContext(ctx)
+		o.dump(info.FullMethod, "← request", md, req)
+		resp, err := handler(ctx, req)
+		if err != nil {
+			o.dump(info.FullMethod, "→ error", nil, err)
+		} else {
+			o.dump(info.FullMethod, "→ response", nil, resp)
+		}
+		return resp, err
+	}
+}
+
+// StreamServerInterceptor returns a server interceptor dumping the incoming
+// metadata of streaming calls, every message sent and received, and the
+// error the call ends with.
+func StreamServerInterceptor(opts ...Option) grpc.StreamServerInterceptor {
+	o := newOptions(opts)
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if !o.enabledFor(info.FullMethod) {
+			return handler(srv, ss)
+		}
+		md, _ := metadata.FromIncomingContext(ss.Context())
+		o.dump(info.FullMethod, "← stream opened", md)
+		err := handler(srv, &serverStream{ServerStream: ss, o: o, method: info.FullMethod})
+		if err != nil {
+			o.dump(info.FullMethod, "→ error", nil, err)
+		}
+		return err
+	}
+}
+
+// UnaryClientInterceptor returns a client interceptor dumping the outgoing
+// metadata and request of unary calls, and their response or error.
+func UnaryClientInterceptor(opts ...Option) grpc.UnaryClientInterceptor {
+	o := newOptions(opts)
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, callOpts ...grpc.CallOption) error {
+		if !o.enabledFor(method) {
+			return invoker(ctx, method, req, reply, cc, callOpts...)
+		}
+		md, _ := metadata.FromOutgoingContext(ctx)
+		o.dump(method, "→ request", md, req)
+		err := invoker(ctx, method, req, reply, cc, callOpts...)
+		if err != nil {
+			o.dump(method, "← error", nil, err)
+		} else {
+			o.dump(method, "← response", nil, reply)
+		}
+		return err
+	}
+}
+
+// StreamClientInterceptor returns a client interceptor dumping the outgoing
+// metadata of streaming calls, every message sent and received, and errors.
+func StreamClientInterceptor(opts ...Option) grpc.StreamClientInterceptor {
+	o := newOptions(opts)
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, callOpts ...grpc.CallOption) (grpc.ClientStream, error) {
+		if !o.enabledFor(method) {
+			return streamer(ctx, desc, cc, method, callOpts...)
+		}
+		md, _ := metadata.FromOutgoingContext(ctx)
+		o.dump(method, "→ stream opened", md)
+		cs, err := streamer(ctx, desc, cc, method, callOpts...)
+		if err != nil {
+			o.dump(method, "← error", nil, err)
+			return nil, err
+		}
+		return &clientStream{ClientStream: cs, o: o, method: method}, nil
+	}
+}
+
+// serverStream dumps the messages of a server-side stream.
+type serverStream struct {
+	grpc.ServerStream
+	o      *options
+	method string
+}
+
+func (s *serverStream) SendMsg(m any) error {
+	s.o.dump(s.method, "→ message", nil, m)
+	return s.ServerStream.SendMsg(m)
+}
+
+func (s *serverStream) RecvMsg(m any) error {
+	err := s.ServerStream.RecvMsg(m)
+	if err == nil {
+		s.o.dump(s.method, "← message", nil, m)
+	}
+	return err
+}
+
+// clientStream dumps the messages of a client-side stream.
+type clientStream struct {
+	grpc.ClientStream
+	o      *options
+	method string
+}
+
+func (s *clientStream) SendMsg(m any) error {
+	s.o.dump(s.method, "→ message", nil, m)
+	return s.ClientStream.SendMsg(m)
+}
+
+func (s *clientStream) RecvMsg(m any) error {
+	err := s.ClientStream.RecvMsg(m)
+	switch {
+	case err == nil:
+		s.o.dump(s.method, "← message", nil, m)
+	case err != io.EOF:
+		s.o.dump(s.method, "← error", nil, err)
+	}
+	return err
+}
@@ -0,0 +1,139 @@
+package grpcdebug
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/janvaclavik/govar"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+type cartItem struct {
+	SKU string
+	Qty int
+}
+
+func plainConfig() govar.DumperConfig {
+	cfg := govar.DefaultConfig
+	cfg.UseColors = false
+	cfg.EmbedTypeMethods = false
+	return cfg
+}
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	buf := &bytes.Buffer{}
+	intercept := UnaryServerInterceptor(WithWriter(buf), WithConfig(plainConfig()), WithMethods("/shop.Cart/*"))
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-request-id", "42"))
+	handler := func(ctx context.Context, req any) (any, error) { return cartItem{SKU: "apple", Qty: 2}, nil }
+
+	if _, err := intercept(ctx, cartItem{SKU: "apple", Qty: 1}, &grpc.UnaryServerInfo{FullMethod: "/shop.Cart/Add"}, handler); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	out := buf.String()
+	for _, want := range []string{"[grpc] /shop.Cart/Add ← request\n", `"x-request-id"`, "[grpc] /shop.Cart/Add → response\n", "⯀ Qty int => 2"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q, got:\n%s", want, out)
+		}
+	}
+
+	buf.Reset()
+	if _, err := intercept(ctx, cartItem{}, &grpc.UnaryServerInfo{FullMethod: "/shop.Billing/Pay"}, handler); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if buf.Len() != 0 {
+		t.Errorf("methods not matching WithMethods should not be dumped, got:\n%s", buf)
+	}
+}
+
+func TestUnaryClientInterceptorEnabledAndMaxSize(t *testing.T) {
+	buf := &bytes.Buffer{}
+	enabled := false
+	intercept := UnaryClientInterceptor(WithWriter(buf), WithConfig(plainConfig()), WithEnabled(func() bool { return enabled }), WithMaxSize(40))
+	invoker := func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+		return errors.New("unavailable")
+	}
+
+	intercept(context.Background(), "/shop.Cart/Add", strings.Repeat("x", 100), nil, nil, invoker)
+	if buf.Len() != 0 {
+		t.Errorf("disabled interceptor should not dump, got:\n%s", buf)
+	}
+
+	enabled = true
+	intercept(context.Background(), "/shop.Cart/Add", strings.Repeat("x", 100), nil, nil, invoker)
+	out := buf.String()
+	for _, want := range []string{"→ request\n", "more bytes)\n", "← error\n", `"unavail…`} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q, got:\n%s", want, out)
+		}
+	}
+}
+
+func TestMaxSizeKeepsColorSequences(t *testing.T) {
+	cfg := plainConfig()
+	cfg.UseColors = true
+	text := govar.NewDumper(cfg).Record(govar.RecordText, cartItem{SKU: "pear", Qty: 3}).Text
+	for size := 1; size < len(text); size++ {
+		o := newOptions([]Option{WithConfig(cfg), WithMaxSize(size)})
+		out, _, _ := strings.Cut(o.render(cartItem{SKU: "pear", Qty: 3}), "… (")
+		if i := strings.LastIndexByte(out, '\x1b'); i >= 0 && !strings.Contains(out[i:], "m") {
+			t.Fatalf("cut after %d bytes splits an escape sequence: %q", size, out)
+		}
+		if strings.Contains(out, "\x1b[") && !strings.HasSuffix(out, govar.ColorReset) {
+			t.Fatalf("cut after %d bytes does not reset colors: %q", size, out)
+		}
+	}
+}
+
+// fakeServerStream is a grpc.ServerStream receiving a single message.
+type fakeServerStream struct {
+	grpc.ServerStream
+	received bool
+	sent     []any
+}
+
+func (s *fakeServerStream) Context() context.Context { return context.Background() }
+
+func (s *fakeServerStream) SendMsg(m any) error {
+	s.sent = append(s.sent, m)
+	return nil
+}
+
+func (s *fakeServerStream) RecvMsg(m any) error {
+	if s.received {
+		return io.EOF
+	}
+	s.received = true
+	*m.(*cartItem) = cartItem{SKU: "pear", Qty: 3}
+	return nil
+}
+
+func TestStreamServerInterceptor(t *testing.T) {
+	buf := &bytes.Buffer{}
+	intercept := StreamServerInterceptor(WithWriter(buf), WithConfig(plainConfig()))
+	handler := func(srv any, ss grpc.ServerStream) error {
+		item := &cartItem{}
+		if err := ss.RecvMsg(item); err != nil {
+			return err
+		}
+		return ss.SendMsg(*item)
+	}
+
+	ss := &fakeServerStream{}
+	if err := intercept(nil, ss, &grpc.StreamServerInfo{FullMethod: "/shop.Cart/Watch"}, handler); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(ss.sent) != 1 {
+		t.Errorf("message should be forwarded to the stream, got %v", ss.sent)
+	}
+	out := buf.String()
+	for _, want := range []string{"← stream opened\n", "← message\n", "→ message\n", `"pear"`} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q, got:\n%s", want, out)
+		}
+	}
+}
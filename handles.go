@@ -0,0 +1,77 @@
+// Package govar provides a powerful and highly configurable pretty-printer for Go
+// data structures. This file renders weak.Pointer and unique.Handle by what they
+// refer to instead of as the opaque structs they are implemented with. The
+// types are matched by name, so the packages are not imported (weak needs Go
+// 1.24).
+package govar
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// isGenericInstance reports whether t is an instantiation of the generic
+// struct type name declared in the package with the given import path.
+func isGenericInstance(t reflect.Type, pkgPath, name string) bool {
+	return t.Kind() == reflect.Struct && t.PkgPath() == pkgPath && strings.HasPrefix(t.Name(), name+"[")
+}
+
+// handleTarget returns what the weak.Pointer or unique.Handle v refers to: a
+// pointer to the target of a weak.Pointer, invalid if the target was garbage
+// collected, or the canonical value of a unique.Handle. Zero handles yield a
+// nil pointer. ok is false for other types and if v cannot be read.
+func handleTarget(v reflect.Value) (target reflect.Value, ok bool) {
+	switch t := v.Type(); {
+	case isGenericInstance(t, "unique", "Handle"):
+		ptr := v.FieldByName("value")
+		if !ptr.IsValid() || ptr.Kind() != reflect.Ptr {
+			return reflect.Value{}, false
+		}
+		if ptr.IsNil() {
+			return ptr, true
+		}
+		return ptr.Elem(), true
+	case isGenericInstance(t, "weak", "Pointer"):
+		method, found := t.MethodByName("Value")
+		if !found || method.Type.NumOut() != 1 {
+			return reflect.Value{}, false
+		}
+		if u := v.FieldByName("u"); u.IsValid() && u.Kind() == reflect.UnsafePointer && u.IsNil() {
+			return reflect.Zero(method.Type.Out(0)), true
+		}
+		exported := tryExport(v)
+		if !exported.CanInterface() {
+			return reflect.Value{}, false
+		}
+		ptr := exported.Method(method.Index).Call(nil)[0]
+		if ptr.IsNil() {
+			return reflect.Value{}, true
+		}
+		return ptr, true
+	}
+	return reflect.Value{}, false
+}
+
+// renderHandle renders a weak.Pointer or unique.Handle as the value it refers
+// to, or as <collected> for weak pointers whose target was garbage collected,
+// reporting false if v is neither.
+func (d *Dumper) renderHandle(sb *strings.Builder, v reflect.Value, level int) bool {
+	target, ok := handleTarget(v)
+	if !ok {
+		return false
+	}
+	if !target.IsValid() {
+		fmt.Fprint(sb, d.ApplyFormat(ColorSlateGray, "<collected>"))
+		return true
+	}
+	if d.showMeta(MetaInterfaceHint) && !isNil(target) {
+		if v.Type().PkgPath() == "weak" {
+			fmt.Fprint(sb, d.metaHint("weak: alive", ""))
+		} else {
+			fmt.Fprint(sb, d.metaHint("unique", ""))
+		}
+	}
+	d.renderValue(sb, target, level, false)
+	return true
+}
@@ -0,0 +1,48 @@
+//go:build go1.24
+
+package govar
+
+import (
+	"runtime"
+	"strings"
+	"testing"
+	"unique"
+	"weak"
+)
+
+func TestDumpWeakAndUnique(t *testing.T) {
+	cfg := DefaultConfig
+	cfg.UseColors = false
+	cfg.EmbedTypeMethods = false
+
+	alive := &[]string{"ada"}
+	input := struct {
+		Cache weak.Pointer[[]string]
+		Zero  weak.Pointer[[]string]
+		Name  unique.Handle[string]
+	}{weak.Make(alive), weak.Pointer[[]string]{}, unique.Make("prod")}
+
+	out := NewDumper(cfg).Sdump(input)
+	for _, want := range []string{
+		`=> |weak: alive| |1| [0 => |R:3| "ada"]`,
+		"Zero   weak.Pointer[[]string] => <nil>",
+		`=> |unique| |R:4| "prod"`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q, got:\n%s", want, out)
+		}
+	}
+	runtime.KeepAlive(alive)
+
+	collected := weak.Make(&[]string{"gone"})
+	runtime.GC()
+	if collected.Value() != nil {
+		t.Skip("target not collected")
+	}
+	if out := NewDumper(cfg).Sdump(collected); !strings.Contains(out, "<collected>") {
+		t.Errorf("expected <collected>, got:\n%s", out)
+	}
+	if node := NewDumper(cfg).Tree(input.Name)[0]; !strings.Contains(node.Value, `"prod"`) || node.Type != "unique.Handle[string]" {
+		t.Errorf("unexpected tree node %+v", node)
+	}
+}
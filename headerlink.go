@@ -0,0 +1,40 @@
+// Package govar provides a powerful and highly configurable pretty-printer for Go
+// data structures. This file turns the file:line location in the dump header
+// into a link built from the HeaderLinkTemplate option: an OSC 8 hyperlink in
+// colored terminal output, which supporting terminals make clickable, and an
+// <a> element in HTML output.
+package govar
+
+import (
+	"fmt"
+	"html"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// headerLinkURL fills the {path} and {line} placeholders of the template with
+// the absolute, slash-separated path of file and the line number.
+func headerLinkURL(template, file string, line int) string {
+	if abs, err := filepath.Abs(file); err == nil {
+		file = abs
+	}
+	return strings.NewReplacer("{path}", filepath.ToSlash(file), "{line}", strconv.Itoa(line)).Replace(template)
+}
+
+// linkHeaderLocation wraps the formatted header location in a link to file and
+// line if HeaderLinkTemplate is set and the output format supports links.
+// Plain text is returned unchanged, so it stays free of escape sequences.
+func (d *Dumper) linkHeaderLocation(location, file string, line int) string {
+	if d.config.HeaderLinkTemplate == "" {
+		return location
+	}
+	url := headerLinkURL(d.config.HeaderLinkTemplate, file, line)
+	switch d.Formatter.(type) {
+	case *ANSIcolorFormatter:
+		return "\033]8;;" + url + "\033\\" + location + "\033]8;;\033\\"
+	case *HTMLformatter:
+		return fmt.Sprintf(`<a href="%s">%s</a>`, html.EscapeString(url), location)
+	}
+	return location
+}
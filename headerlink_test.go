@@ -0,0 +1,38 @@
+package govar
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestHeaderLinkURL(t *testing.T) {
+	file := filepath.Join(string(filepath.Separator), "src", "shop", "main.go")
+	got := headerLinkURL("vscode://file/{path}:{line}", file, 42)
+	if want := "vscode://file/" + filepath.ToSlash(file) + ":42"; got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+}
+
+func TestLinkHeaderLocation(t *testing.T) {
+	cfg := DefaultConfig
+	cfg.HeaderLinkTemplate = "vscode://file/{path}:{line}"
+	d := NewDumper(cfg)
+	file := filepath.Join(string(filepath.Separator), "src", "main.go")
+	url := headerLinkURL(cfg.HeaderLinkTemplate, file, 7)
+
+	d.Formatter = &ANSIcolorFormatter{}
+	if got, want := d.linkHeaderLocation("main.go:7", file, 7), "\033]8;;"+url+"\033\\main.go:7\033]8;;\033\\"; got != want {
+		t.Errorf("expected OSC 8 hyperlink %q, got %q", want, got)
+	}
+
+	d.Formatter = &HTMLformatter{HTMLtagToken: "span"}
+	if got := d.linkHeaderLocation("main.go:7", file, 7); !strings.HasPrefix(got, `<a href="`+url+`">`) {
+		t.Errorf("expected an <a> element, got %q", got)
+	}
+
+	d.Formatter = &PlainFormatter{}
+	if got := d.linkHeaderLocation("main.go:7", file, 7); got != "main.go:7" {
+		t.Errorf("plain output should not be linked, got %q", got)
+	}
+}
@@ -0,0 +1,169 @@
+// Package govar provides a powerful and highly configurable pretty-printer for Go
+// data structures. This file summarizes well-known standard library types whose
+// full traversal produces huge, mostly irrelevant dumps (test runners, HTTP and
+// TLS internals, connection pools, template trees, sync primitives), as well as
+// the internal state of generated protobuf messages. Types are matched by name,
+// so none of their packages are imported.
+package govar
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// heavyTypeDetails maps the full names of summarized types to functions that
+// pick the few details worth showing from a (dereferenced) value.
+var heavyTypeDetails = map[string]func(d *Dumper, v reflect.Value) []string{
+	"testing.T":              nameDetails,
+	"testing.B":              nameDetails,
+	"testing.F":              nameDetails,
+	"net/http.Client":        fieldDetails("Timeout"),
+	"net/http.Transport":     fieldDetails("MaxIdleConns", "MaxConnsPerHost", "IdleConnTimeout", "TLSHandshakeTimeout"),
+	"net/http.Server":        fieldDetails("Addr", "ReadTimeout", "WriteTimeout"),
+	"crypto/tls.Config":      fieldDetails("ServerName", "MinVersion", "InsecureSkipVerify"),
+	"database/sql.DB":        sqlDBDetails,
+	"text/template.Template": nameDetails,
+	"html/template.Template": nameDetails,
+	"sync.Once":              onceDetails,
+	"sync.Cond":              condDetails,
+
+	// The unexported state field of every generated protobuf message leads to
+	// the descriptors of its whole file.
+	"google.golang.org/protobuf/internal/impl.MessageState": noDetails,
+}
+
+// heavyTypeSummary returns the one-line summary of a value of a known heavy
+// type, or "" if the type is not summarized or ExpandHeavyTypes is enabled.
+func (d *Dumper) heavyTypeSummary(v reflect.Value) string {
+	if d.config.ExpandHeavyTypes || v.Kind() != reflect.Struct {
+		return ""
+	}
+	details, ok := heavyTypeDetails[v.Type().PkgPath()+"."+v.Type().Name()]
+	if !ok {
+		return ""
+	}
+	return "{" + strings.Join(append(details(d, tryExport(v)), "…"), ", ") + "}"
+}
+
+// renderHeavyTypeSummary writes the summary of a known heavy type, reporting
+// false if v is not summarized.
+func (d *Dumper) renderHeavyTypeSummary(sb *strings.Builder, v reflect.Value) bool {
+	summary := d.heavyTypeSummary(v)
+	if summary == "" {
+		return false
+	}
+	if d.showMeta(MetaInterfaceHint) {
+		fmt.Fprint(sb, d.metaHint("summary:", ""))
+	}
+	fmt.Fprint(sb, d.ApplyFormat(ColorSlateGray, summary))
+	return true
+}
+
+// fieldDetails returns a details function listing the given exported fields.
+func fieldDetails(names ...string) func(d *Dumper, v reflect.Value) []string {
+	return func(d *Dumper, v reflect.Value) []string {
+		var details []string
+		for _, name := range names {
+			if f := v.FieldByName(name); f.IsValid() && f.CanInterface() {
+				details = append(details, name+": "+d.formatDetail(f))
+			}
+		}
+		return details
+	}
+}
+
+// noDetails shows no details, for types summarized as {…}.
+func noDetails(d *Dumper, v reflect.Value) []string {
+	return nil
+}
+
+// nameDetails shows the result of the Name method, e.g. of a test or template.
+func nameDetails(d *Dumper, v reflect.Value) []string {
+	if name, ok := callStringMethod(v, "Name"); ok {
+		return []string{"Name: " + strconv.Quote(d.anonymizeText(name))}
+	}
+	return nil
+}
+
+// sqlDBDetails shows the connection pool statistics of a database handle.
+func sqlDBDetails(d *Dumper, v reflect.Value) []string {
+	if !v.CanAddr() {
+		return nil
+	}
+	stats := v.Addr().MethodByName("Stats")
+	if !stats.IsValid() || !stats.CanInterface() || stats.Type().NumIn() != 0 || stats.Type().NumOut() != 1 {
+		return nil
+	}
+	return fieldDetails("OpenConnections", "InUse", "Idle")(d, stats.Call(nil)[0])
+}
+
+// onceDetails shows whether a sync.Once has run its function.
+func onceDetails(d *Dumper, v reflect.Value) []string {
+	if done, ok := uintField(v, "done"); ok {
+		return []string{"done: " + strconv.FormatBool(done != 0)}
+	}
+	return nil
+}
+
+// condDetails shows the Locker of a sync.Cond and how many goroutines wait on it.
+func condDetails(d *Dumper, v reflect.Value) []string {
+	locker := "L: <nil>"
+	if l := v.FieldByName("L"); l.IsValid() && !l.IsNil() {
+		locker = "L: " + l.Elem().Type().String()
+	}
+	details := []string{locker}
+	notify := v.FieldByName("notify")
+	if !notify.IsValid() {
+		return details
+	}
+	wait, waitOK := uintField(notify, "wait")
+	notified, notifiedOK := uintField(notify, "notify")
+	if waitOK && notifiedOK {
+		details = append(details, "waiters: "+d.anonymizeNumber(strconv.FormatUint(uint64(uint32(wait-notified)), 10)))
+	}
+	return details
+}
+
+// uintField reads the unsigned integer or bool field name of the struct v,
+// looking through sync/atomic wrappers (atomic.Uint32, atomic.Bool, …), which
+// keep the value in a field v. It reports false if there is no such field.
+func uintField(v reflect.Value, name string) (uint64, bool) {
+	f := v.FieldByName(name)
+	if f.Kind() == reflect.Struct {
+		f = f.FieldByName("v")
+	}
+	switch f.Kind() {
+	case reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return f.Uint(), true
+	case reflect.Bool:
+		if f.Bool() {
+			return 1, true
+		}
+		return 0, true
+	}
+	return 0, false
+}
+
+// callStringMethod calls a niladic method returning a string on v or, if v is
+// addressable, on its address.
+func callStringMethod(v reflect.Value, name string) (string, bool) {
+	m := v.MethodByName(name)
+	if !m.IsValid() && v.CanAddr() {
+		m = v.Addr().MethodByName(name)
+	}
+	if !m.IsValid() || !m.CanInterface() || m.Type().NumIn() != 0 || m.Type().NumOut() != 1 || m.Type().Out(0).Kind() != reflect.String {
+		return "", false
+	}
+	return m.Call(nil)[0].String(), true
+}
+
+// formatDetail formats the value of a detail field, quoted if it is a string.
+// Strings and the digits of other values are blurred under Anonymize.
+func (d *Dumper) formatDetail(v reflect.Value) string {
+	if v.Kind() == reflect.String {
+		return strconv.Quote(d.anonymizeText(v.String()))
+	}
+	return d.anonymizeNumber(fmt.Sprint(v.Interface()))
+}
@@ -0,0 +1,81 @@
+package govar
+
+import (
+	"crypto/tls"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"text/template"
+	"time"
+)
+
+func TestHeavyTypeSummaries(t *testing.T) {
+	input := struct {
+		T      *testing.T
+		Client *http.Client
+		TLS    *tls.Config
+		Tmpl   *template.Template
+	}{
+		T:      t,
+		Client: &http.Client{Timeout: 30 * time.Second},
+		TLS:    &tls.Config{ServerName: "example.com"},
+		Tmpl:   template.New("page"),
+	}
+
+	cfg := DefaultConfig
+	cfg.UseColors = false
+	cfg.EmbedTypeMethods = false
+	out := NewDumper(cfg).Sdump(input)
+
+	for _, want := range []string{
+		`|summary:| {Name: "TestHeavyTypeSummaries", …}`,
+		`{Timeout: 30s, …}`,
+		`{ServerName: "example.com", MinVersion: 0, InsecureSkipVerify: false, …}`,
+		`{Name: "page", …}`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in output, got:\n%s", want, out)
+		}
+	}
+
+	masked := cfg
+	masked.Anonymize = AnonymizeMask
+	out = NewDumper(masked).Sdump(input.TLS, input.Tmpl, &http.Server{Addr: ":8080"})
+	for _, leak := range []string{"example.com", `"page"`, ":8080"} {
+		if strings.Contains(out, leak) {
+			t.Errorf("expected %q to be anonymized, got:\n%s", leak, out)
+		}
+	}
+
+	cfg.ExpandHeavyTypes = true
+	out = NewDumper(cfg).Sdump(input.Client)
+	if strings.Contains(out, "summary:") || !strings.Contains(out, "Transport") {
+		t.Errorf("expected full traversal with ExpandHeavyTypes, got:\n%s", out)
+	}
+}
+
+func TestSyncSummaries(t *testing.T) {
+	var mu sync.Mutex
+	input := struct {
+		Pending sync.Once
+		Done    sync.Once
+		Ready   *sync.Cond
+	}{Ready: sync.NewCond(&mu)}
+	input.Done.Do(func() {})
+
+	cfg := DefaultConfig
+	cfg.UseColors = false
+	cfg.EmbedTypeMethods = false
+	out := NewDumper(cfg).Sdump(&input)
+
+	for _, want := range []string{
+		"Pending  sync.Once  => |summary:| {done: false, …}",
+		"Done     sync.Once  => |summary:| {done: true, …}",
+		"{L: *sync.Mutex, waiters: 0, …}",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in output, got:\n%s", want, out)
+		}
+	}
+}
@@ -0,0 +1,84 @@
+// Package govar provides a powerful and highly configurable pretty-printer for Go
+// data structures. This file implements search term highlighting: substrings of
+// the rendered output matching a needle or pattern are shown in an inverse,
+// bold style, so a specific value can be spotted in a long dump at a glance.
+package govar
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// ANSI styles used for highlighted matches, combined with the match's color.
+const (
+	styleBold    = "\033[1m"
+	styleInverse = "\033[7m"
+)
+
+// WithHighlight highlights every occurrence of needle in the dumps rendered by
+// d, replacing any configured HighlightPattern, and returns d for chaining. An
+// empty needle turns highlighting off. Highlighting only shows in colored and
+// HTML output.
+func (d *Dumper) WithHighlight(needle string) *Dumper {
+	d.highlight = nil
+	if needle != "" {
+		d.highlight = regexp.MustCompile(regexp.QuoteMeta(needle))
+	}
+	return d
+}
+
+// compileHighlight compiles the HighlightPattern option. Patterns that are not
+// valid regular expressions are matched literally.
+func compileHighlight(pattern string) *regexp.Regexp {
+	if pattern == "" {
+		return nil
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return regexp.MustCompile(regexp.QuoteMeta(pattern))
+	}
+	return re
+}
+
+// ApplyFormat applies the color to str using the active Formatter, rendering
+// the parts of str that match the highlight pattern in an inverse, bold style.
+// While a value of a TypeColors type renders, its color replaces colorCode.
+func (d *Dumper) ApplyFormat(colorCode string, str string) string {
+	if d.colorOverride != "" {
+		colorCode = d.colorOverride
+	}
+	if d.highlight == nil {
+		return d.Formatter.ApplyFormat(colorCode, str)
+	}
+	matches := d.highlight.FindAllStringIndex(str, -1)
+	if len(matches) == 0 {
+		return d.Formatter.ApplyFormat(colorCode, str)
+	}
+	out, last := "", 0
+	for _, m := range matches {
+		if m[0] == m[1] {
+			continue // Empty matches have nothing to highlight.
+		}
+		if m[0] > last {
+			out += d.Formatter.ApplyFormat(colorCode, str[last:m[0]])
+		}
+		out += d.highlightMatch(colorCode, str[m[0]:m[1]])
+		last = m[1]
+	}
+	if last < len(str) {
+		out += d.Formatter.ApplyFormat(colorCode, str[last:])
+	}
+	return out
+}
+
+// highlightMatch renders a single highlighted match with the active Formatter.
+func (d *Dumper) highlightMatch(colorCode string, match string) string {
+	switch f := d.Formatter.(type) {
+	case *ANSIcolorFormatter:
+		return styleBold + styleInverse + f.ApplyFormat(colorCode, match)
+	case *HTMLformatter:
+		return fmt.Sprintf("<mark>%s</mark>", f.ApplyFormat(colorCode, match))
+	default:
+		return d.Formatter.ApplyFormat(colorCode, match)
+	}
+}
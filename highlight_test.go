@@ -0,0 +1,36 @@
+package govar
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestHighlight(t *testing.T) {
+	input := map[string]string{"order": "ord-4711", "user": "usr-1"}
+
+	cfg := DefaultConfig
+	cfg.UseColors = true
+	out := NewDumper(cfg).WithHighlight("4711").Sdump(input)
+	if !strings.Contains(out, styleBold+styleInverse+ColorLime+"4711"+ColorReset) {
+		t.Errorf("expected the match in an inverse bold style, got:\n%q", out)
+	}
+	if !strings.Contains(out, ColorLime+"ord-"+ColorReset) {
+		t.Errorf("expected the rest of the value in its usual color, got:\n%q", out)
+	}
+
+	cfg.HighlightPattern = `usr-\d+`
+	out = NewDumper(cfg).Sdump(input)
+	if !strings.Contains(out, styleBold+styleInverse+ColorLime+"usr-1"+ColorReset) {
+		t.Errorf("expected HighlightPattern matches highlighted, got:\n%q", out)
+	}
+
+	html := NewDumper(cfg).WithHighlight("order").SdumpHTML(input)
+	if !strings.Contains(html, "<mark>") || !strings.Contains(html, "order") {
+		t.Errorf("expected <mark> around matches in HTML output, got:\n%s", html)
+	}
+
+	cfg.UseColors = false
+	if out := NewDumper(cfg).WithHighlight("4711").Sdump(input); strings.Contains(out, styleInverse) {
+		t.Errorf("expected no styles in plain output, got:\n%q", out)
+	}
+}
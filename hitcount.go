@@ -0,0 +1,115 @@
+// Package govar provides a powerful and highly configurable pretty-printer for Go
+// data structures. This file implements hit counters: Count tracks how many
+// times a code path ran, identified by a label and its call site, and reports
+// the count on the first hit and then at most once per countReportInterval;
+// CountSummary lists all counters.
+package govar
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// countReportInterval is the minimum time between two reports of a counter.
+const countReportInterval = time.Second
+
+// hitCounter counts the hits of one Count call site.
+type hitCounter struct {
+	mu         sync.Mutex
+	label      string
+	location   string    // "file:line" of the call site, or "" if unknown.
+	hits       uint64    // Hits so far.
+	reported   uint64    // Hits at the last report.
+	lastReport time.Time // Time of the last report.
+}
+
+// hitCounters holds the counters of all Count call sites.
+var hitCounters sync.Map // "label\x00file:line" -> *hitCounter
+
+// Count counts an execution of the code path it is called from and prints the
+// count to stdout (e.g. "[#] cache miss ×17 (+5)  ⟵  store.go:42") on the first
+// hit and then at most once per second, so hot paths do not flood the output.
+// Counters are identified by the label and the call site; CountSummary lists
+// them all.
+func (d *Dumper) Count(label string) {
+	file, line, _ := findCallerInStack()
+	location := ""
+	if file != "" {
+		location = relativeSourcePath(file) + ":" + strconv.Itoa(line)
+	}
+	d.count(stdoutWriter(), label, location, time.Now())
+}
+
+// count records a hit of the counter for label at location, and writes its
+// report to w if one is due at now.
+func (d *Dumper) count(w io.Writer, label, location string, now time.Time) {
+	counter, _ := hitCounters.LoadOrStore(label+"\x00"+location, &hitCounter{label: label, location: location})
+	c := counter.(*hitCounter)
+	c.mu.Lock()
+	c.hits++
+	if c.hits > 1 && now.Sub(c.lastReport) < countReportInterval {
+		c.mu.Unlock()
+		return
+	}
+	hits, delta := c.hits, uint64(0)
+	if hits > 1 {
+		delta = hits - c.reported
+	}
+	c.reported, c.lastReport = c.hits, now
+	c.mu.Unlock()
+
+	d.Formatter = d.textFormatter(d.config.UseColors)
+	sb := &strings.Builder{}
+	d.writeCountLine(sb, label, location, hits, delta)
+	fmt.Fprintln(w, sb.String())
+}
+
+// CountSummary prints the hit counts of all Count call sites to stdout, sorted
+// by label and call site.
+func (d *Dumper) CountSummary() {
+	fmt.Fprint(stdoutWriter(), d.countSummary())
+}
+
+// countSummary returns the lines printed by CountSummary.
+func (d *Dumper) countSummary() string {
+	var counters []*hitCounter
+	hitCounters.Range(func(_, counter any) bool {
+		counters = append(counters, counter.(*hitCounter))
+		return true
+	})
+	sort.Slice(counters, func(i, j int) bool {
+		if counters[i].label != counters[j].label {
+			return counters[i].label < counters[j].label
+		}
+		return counters[i].location < counters[j].location
+	})
+
+	d.Formatter = d.textFormatter(d.config.UseColors)
+	sb := &strings.Builder{}
+	for _, c := range counters {
+		c.mu.Lock()
+		hits := c.hits
+		c.mu.Unlock()
+		d.writeCountLine(sb, c.label, c.location, hits, 0)
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+// writeCountLine writes the report of a counter without a line break, e.g.
+// "[#] cache miss ×17 (+5)  ⟵  store.go:42", where the number of hits since
+// the previous report is omitted if delta is 0.
+func (d *Dumper) writeCountLine(sb *strings.Builder, label, location string, hits, delta uint64) {
+	sb.WriteString(d.ApplyFormat(ColorGoBlue, "[#] "+label) + " " + d.ApplyFormat(ColorSkyBlue, "×"+strconv.FormatUint(hits, 10)))
+	if delta > 0 {
+		sb.WriteString(" " + d.ApplyFormat(ColorDimGray, "(+"+strconv.FormatUint(delta, 10)+")"))
+	}
+	if location != "" {
+		sb.WriteString(d.ApplyFormat(ColorSlateGray, "  ⟵  "+location))
+	}
+}
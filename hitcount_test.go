@@ -0,0 +1,34 @@
+package govar
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCount(t *testing.T) {
+	cfg := DefaultConfig
+	cfg.UseColors = false
+	d := NewDumper(cfg)
+	buf := &bytes.Buffer{}
+	start := time.Now()
+
+	d.count(buf, "cache miss", "store.go:42", start)
+	d.count(buf, "cache miss", "store.go:42", start.Add(100*time.Millisecond))
+	d.count(buf, "cache miss", "store.go:42", start.Add(200*time.Millisecond))
+	d.count(buf, "cache miss", "store.go:42", start.Add(1500*time.Millisecond))
+	d.count(buf, "cache miss", "other.go:7", start)
+
+	want := "[#] cache miss ×1  ⟵  store.go:42\n" +
+		"[#] cache miss ×4 (+3)  ⟵  store.go:42\n" +
+		"[#] cache miss ×1  ⟵  other.go:7\n"
+	if got := buf.String(); got != want {
+		t.Errorf("expected reports:\n%s\ngot:\n%s", want, got)
+	}
+
+	summary := d.countSummary()
+	if !strings.Contains(summary, "[#] cache miss ×1  ⟵  other.go:7\n[#] cache miss ×4  ⟵  store.go:42\n") {
+		t.Errorf("expected a sorted summary, got:\n%s", summary)
+	}
+}
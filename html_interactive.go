@@ -0,0 +1,90 @@
+// Package govar provides a powerful and highly configurable pretty-printer for Go
+// data structures. This file renders the interactive HTML output: the
+// structured render tree embedded as JSON plus a small self-contained script
+// offering search, type filtering, and collapse-all/expand-all controls.
+package govar
+
+import (
+	"encoding/json"
+	"fmt"
+	"html"
+	"strings"
+)
+
+// interactiveHTMLStyle is the stylesheet embedded into every interactive dump.
+// Colors are resolved from ColorPaletteHTML when the output is generated.
+const interactiveHTMLStyle = `.govar-interactive{background:black;color:white;padding:4px;border-radius:4px;font-family:monospace}
+.govar-interactive .gv-toolbar{display:flex;gap:6px;margin-bottom:6px}
+.govar-interactive .gv-toolbar input{background:#111;color:white;border:1px solid %[1]s;font-family:monospace}
+.govar-interactive .gv-toolbar button{background:#111;color:%[2]s;border:1px solid %[1]s;cursor:pointer}
+.govar-interactive ul{list-style:none;margin:0;padding-left:1.5em}
+.govar-interactive summary{cursor:pointer}
+.govar-interactive .gv-key{color:%[3]s}
+.govar-interactive .gv-type{color:%[1]s}
+.govar-interactive .gv-value{color:%[4]s}
+.govar-interactive .gv-id{color:%[5]s}
+.govar-interactive .gv-ref{color:%[6]s}
+.govar-interactive .gv-hidden{display:none}
+.govar-interactive .gv-match>summary>.gv-label,.govar-interactive .gv-match>.gv-label{outline:1px dashed %[5]s}`
+
+// interactiveHTMLScript builds the tree from the embedded JSON and wires up the toolbar.
+const interactiveHTMLScript = `(function(){
+var root=document.currentScript.parentNode;
+var data=JSON.parse(root.querySelector('script[type="application/json"]').textContent);
+var tree=root.querySelector('.gv-tree');
+function el(tag,cls,text){var e=document.createElement(tag);if(cls)e.className=cls;if(text)e.textContent=text;return e;}
+function label(n){var l=el('span','gv-label');
+if(n.key)l.appendChild(el('span','gv-key',n.key+' '));
+if(n.type)l.appendChild(el('span','gv-type',n.type+' '));
+l.appendChild(document.createTextNode('=> '));
+if(n.id)l.appendChild(el('span','gv-id',n.id+' '));
+if(n.ref)l.appendChild(el('span','gv-ref','↩︎ '+n.ref));
+else if(n.value!==undefined)l.appendChild(el('span','gv-value',n.value));
+else if(n.children)l.appendChild(el('span','gv-type','|'+n.children.length+(n.truncated?'+':'')+'|'));
+return l;}
+function build(n){var li=el('li');li.dataset.type=n.type||'';li.dataset.text=((n.key||'')+' '+(n.value||'')).toLowerCase();
+if(n.children&&n.children.length){var d=el('details');d.open=true;var s=el('summary');s.appendChild(label(n));d.appendChild(s);
+var ul=el('ul');n.children.forEach(function(c){ul.appendChild(build(c));});d.appendChild(ul);li.appendChild(d);}
+else li.appendChild(label(n));return li;}
+data.forEach(function(n){tree.appendChild(build(n));});
+var search=root.querySelector('.gv-search'),typeFilter=root.querySelector('.gv-type-filter');
+function filter(li,q,t){var own=(!q||li.dataset.text.indexOf(q)>=0)&&(!t||li.dataset.type.toLowerCase().indexOf(t)>=0);
+var any=false,ul=li.querySelector(':scope>details>ul');
+if(ul)Array.prototype.forEach.call(ul.children,function(c){if(filter(c,q,t))any=true;});
+var visible=own||any;li.classList.toggle('gv-hidden',!visible);li.classList.toggle('gv-match',own&&!!(q||t));
+if(any&&(q||t))li.querySelector(':scope>details').open=true;return visible;}
+function apply(){var q=search.value.toLowerCase(),t=typeFilter.value.toLowerCase();
+Array.prototype.forEach.call(tree.children,function(li){filter(li,q,t);});}
+search.addEventListener('input',apply);typeFilter.addEventListener('input',apply);
+root.querySelector('.gv-expand').addEventListener('click',function(){tree.querySelectorAll('details').forEach(function(d){d.open=true;});});
+root.querySelector('.gv-collapse').addEventListener('click',function(){tree.querySelectorAll('details').forEach(function(d){d.open=false;});});
+})();`
+
+// SdumpHTMLInteractive returns a self-contained HTML fragment that embeds the
+// structured render tree of the values as JSON, together with a small script
+// providing search, type filtering, and collapse-all/expand-all controls.
+func (d *Dumper) SdumpHTMLInteractive(vs ...any) string {
+	header := &strings.Builder{}
+	d.Formatter = &PlainFormatter{}
+	d.renderHeader(header)
+
+	payload, err := json.Marshal(d.Tree(vs...))
+	if err != nil {
+		payload = []byte("[]")
+	}
+
+	sb := &strings.Builder{}
+	fmt.Fprintln(sb, `<div class="govar-interactive">`)
+	fmt.Fprintf(sb, "<style>"+interactiveHTMLStyle+"</style>\n",
+		ColorPaletteHTML[ColorDarkGray], ColorPaletteHTML[ColorGoBlue], ColorPaletteHTML[ColorLightTeal],
+		ColorPaletteHTML[ColorLime], ColorPaletteHTML[ColorGoldenrod], ColorPaletteHTML[ColorPink])
+	if header.Len() > 0 {
+		fmt.Fprintf(sb, `<div style="color:%s">%s</div>`+"\n", ColorPaletteHTML[ColorGoBlue], html.EscapeString(strings.TrimSpace(header.String())))
+	}
+	fmt.Fprintln(sb, `<div class="gv-toolbar"><input class="gv-search" placeholder="search keys and values"><input class="gv-type-filter" placeholder="filter by type"><button class="gv-expand">expand all</button><button class="gv-collapse">collapse all</button></div>`)
+	fmt.Fprintln(sb, `<ul class="gv-tree"></ul>`)
+	fmt.Fprintf(sb, `<script type="application/json">%s</script>`+"\n", payload)
+	fmt.Fprintf(sb, "<script>%s</script>\n", interactiveHTMLScript)
+	fmt.Fprint(sb, `</div>`)
+	return sb.String()
+}
@@ -0,0 +1,63 @@
+// Package govar provides a powerful and highly configurable pretty-printer for Go
+// data structures. This file tracks the path of each value rendered by SdumpHTML
+// (e.g. `Users[2].Name`, in the syntax accepted by Get) and exposes it as a
+// data-govar-path attribute, so scripts can map DOM nodes back to values.
+package govar
+
+import (
+	"fmt"
+	"html"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// enterPath appends a step to the path of the value being rendered while
+// SdumpHTML or SdumpAgainst is running. The returned function restores the
+// previous path.
+func (d *Dumper) enterPath(step string) (leave func()) {
+	if !d.htmlPaths && d.overlay == nil {
+		return func() {}
+	}
+	prev := d.valuePath
+	d.valuePath = appendPathStep(prev, step)
+	return func() { d.valuePath = prev }
+}
+
+// appendPathStep appends a step to path. Steps starting with "[" are appended as is,
+// others are joined with a dot.
+func appendPathStep(path, step string) string {
+	if strings.HasPrefix(step, "[") || path == "" {
+		return path + step
+	}
+	return path + "." + step
+}
+
+// indexPathStep returns the path step of a slice or array element.
+func indexPathStep(i int) string {
+	return "[" + strconv.Itoa(i) + "]"
+}
+
+// mapKeyPathStep returns the path step of a map entry: `["key"]` for string
+// keys and the key's fmt representation in brackets otherwise.
+func mapKeyPathStep(key reflect.Value) string {
+	key = tryExport(key)
+	if key.Kind() == reflect.String {
+		return "[" + strconv.Quote(key.String()) + "]"
+	}
+	if key.CanInterface() {
+		return "[" + fmt.Sprint(key.Interface()) + "]"
+	}
+	return "[?]"
+}
+
+// openPathSpan starts an element carrying the path of the value being
+// rendered, returning the matching closing tag, or "" outside SdumpHTML and for
+// top-level values.
+func (d *Dumper) openPathSpan(sb *strings.Builder) string {
+	if !d.htmlPaths || d.valuePath == "" {
+		return ""
+	}
+	fmt.Fprintf(sb, `<%s data-govar-path="%s">`, d.config.HTMLtagToken, html.EscapeString(d.valuePath))
+	return "</" + d.config.HTMLtagToken + ">"
+}
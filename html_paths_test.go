@@ -0,0 +1,45 @@
+package govar
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSdumpHTMLDataPaths(t *testing.T) {
+	type user struct {
+		Name string
+		Tags []string
+	}
+	input := struct {
+		Users  []user
+		Labels map[string]int
+	}{
+		Users:  []user{{Name: "Ann"}, {Name: "Bob", Tags: []string{"admin"}}},
+		Labels: map[string]int{"app": 1},
+	}
+
+	cfg := DefaultConfig
+	cfg.EmbedTypeMethods = false
+	out := NewDumper(cfg).SdumpHTML(input)
+
+	for _, path := range []string{"Users", "Users[0]", "Users[1].Name", "Users[1].Tags[0]", `Labels[&#34;app&#34;]`} {
+		if !strings.Contains(out, `data-govar-path="`+path+`"`) {
+			t.Errorf("expected data-govar-path %q in output, got:\n%s", path, out)
+		}
+	}
+	if strings.Count(out, "<span") != strings.Count(out, "</span>") {
+		t.Errorf("expected balanced spans, got:\n%s", out)
+	}
+
+	// Every attributed path resolves with Get.
+	for _, part := range strings.Split(out, `data-govar-path="`)[1:] {
+		path := strings.ReplaceAll(part[:strings.Index(part, `"`)], "&#34;", `"`)
+		if _, err := Get(input, path); err != nil {
+			t.Errorf("Get(%q) error: %v", path, err)
+		}
+	}
+
+	if plain := NewDumper(cfg).Sdump(input); strings.Contains(plain, "data-govar-path") {
+		t.Errorf("expected no paths outside HTML output, got:\n%s", plain)
+	}
+}
@@ -0,0 +1,82 @@
+// Package govar provides a powerful and highly configurable pretty-printer for Go
+// data structures. This file annotates dumped named struct types with the
+// project-local interfaces they implement, as found by a registered
+// InterfaceResolver (typically backed by the who package).
+package govar
+
+import (
+	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// InterfaceResolver lists the interfaces implemented by the type with the
+// given fully-qualified name (e.g. "github.com/acme/shop.Order").
+type InterfaceResolver func(typeFullName string) ([]string, error)
+
+// interfaceResolver is the resolver used by ShowImplements, nil until one is
+// registered with SetInterfaceResolver.
+var interfaceResolver atomic.Pointer[InterfaceResolver]
+
+// implementsCache memoizes the interfaces implemented by each type, as loading
+// and type-checking the project's packages takes a while.
+var implementsCache sync.Map // reflect.Type -> []string
+
+// SetInterfaceResolver registers the resolver that ShowImplements uses to find
+// the interfaces implemented by dumped types, and clears the results cached
+// from any previous one. Without a resolver ShowImplements adds nothing, which
+// keeps govar itself free of package loading. To use the who package:
+//
+//	govar.SetInterfaceResolver(func(name string) ([]string, error) {
+//		return who.Interfaces(name)
+//	})
+//
+// Pass nil to remove the resolver.
+func SetInterfaceResolver(resolver InterfaceResolver) {
+	if resolver == nil {
+		interfaceResolver.Store(nil)
+	} else {
+		interfaceResolver.Store(&resolver)
+	}
+	implementsCache.Clear()
+}
+
+// implementedInterfaces returns the fully-qualified names of the interfaces
+// implemented by the named type t, as found by the registered resolver,
+// caching the result.
+func implementedInterfaces(t reflect.Type) []string {
+	resolver := interfaceResolver.Load()
+	if resolver == nil {
+		return nil
+	}
+	if cached, ok := implementsCache.Load(t); ok {
+		return cached.([]string)
+	}
+	ifaces, err := (*resolver)(t.PkgPath() + "." + t.Name())
+	if err != nil {
+		ifaces = nil
+	}
+	implementsCache.Store(t, ifaces)
+	return ifaces
+}
+
+// renderImplements writes the project-local interfaces implemented by a named
+// struct type, e.g. "|implements: shop.Pricer, shop.Validator|".
+func (d *Dumper) renderImplements(sb *strings.Builder, v reflect.Value) {
+	if !d.config.ShowImplements || v.Kind() != reflect.Struct || v.Type().Name() == "" {
+		return
+	}
+	ifaces := implementedInterfaces(v.Type())
+	if len(ifaces) == 0 {
+		return
+	}
+	names := make([]string, len(ifaces))
+	for i, iface := range ifaces {
+		names[i] = iface
+		if !d.config.FullTypePaths {
+			names[i] = shortenImportPaths(iface)
+		}
+	}
+	sb.WriteString(d.metaHint("implements: "+strings.Join(names, ", "), ""))
+}
@@ -0,0 +1,48 @@
+package govar
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/janvaclavik/govar/who"
+)
+
+func TestShowImplements(t *testing.T) {
+	type plain struct{ A int }
+
+	SetInterfaceResolver(func(name string) ([]string, error) {
+		return who.Interfaces(name)
+	})
+	defer SetInterfaceResolver(nil)
+
+	cfg := DefaultConfig
+	cfg.UseColors = false
+	cfg.EmbedTypeMethods = false
+	cfg.ShowImplements = true
+	out := NewDumper(cfg).Sdump(PlainFormatter{}, plain{A: 1})
+
+	if !strings.Contains(out, "|implements: govar.Formatter") {
+		t.Errorf("expected implemented interfaces for PlainFormatter, got:\n%s", out)
+	}
+	if strings.Count(out, "implements:") != 1 {
+		t.Errorf("expected no interfaces for a type without methods, got:\n%s", out)
+	}
+
+	cfg.FullTypePaths = true
+	out = NewDumper(cfg).Sdump(PlainFormatter{})
+	if !strings.Contains(out, "|implements: github.com/janvaclavik/govar.Formatter") {
+		t.Errorf("expected full interface paths, got:\n%s", out)
+	}
+}
+
+func TestShowImplementsWithoutResolver(t *testing.T) {
+	cfg := DefaultConfig
+	cfg.UseColors = false
+	cfg.EmbedTypeMethods = false
+	cfg.ShowImplements = true
+	out := NewDumper(cfg).Sdump(PlainFormatter{})
+
+	if strings.Contains(out, "implements:") {
+		t.Errorf("expected no interfaces without a registered resolver, got:\n%s", out)
+	}
+}
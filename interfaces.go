@@ -0,0 +1,55 @@
+// Package govar provides a powerful and highly configurable pretty-printer for Go
+// data structures. This file controls how interface values are labeled: which
+// of their static and dynamic types are shown, and how an interface holding a
+// nil pointer (a "typed nil") is rendered.
+package govar
+
+import (
+	"reflect"
+)
+
+// InterfaceTypeMode selects which types DumperConfig.InterfaceTypes shows for
+// values stored in interfaces.
+type InterfaceTypeMode int
+
+const (
+	InterfaceBoth    InterfaceTypeMode = iota // The static and the dynamic type (⧉ any(*T)).
+	InterfaceStatic                           // Only the static type of the field or element (⧉ any).
+	InterfaceDynamic                          // Only the type of the stored value (*T); nil interfaces keep the static type.
+)
+
+// TypedNilStyle selects how DumperConfig.TypedNils renders non-nil interfaces
+// holding a nil value, e.g. an error wrapping a nil *MyErr.
+type TypedNilStyle int
+
+const (
+	TypedNilGo        TypedNilStyle = iota // Go syntax with the dynamic type: (*T)(nil).
+	TypedNilPlain                          // Like a nil interface: <nil>.
+	TypedNilAnnotated                      // Go syntax plus a warning that the interface itself is not nil.
+)
+
+// interfaceTypeName combines the already formatted static type of the interface
+// value v with its dynamic type, as selected by InterfaceTypes.
+func (d *Dumper) interfaceTypeName(v reflect.Value, static string) string {
+	if v.IsNil() || d.config.InterfaceTypes == InterfaceStatic {
+		return static
+	}
+	dynamic := d.typeName(v.Elem().Type())
+	if d.config.InterfaceTypes == InterfaceDynamic {
+		return dynamic
+	}
+	return static + "(" + dynamic + ")"
+}
+
+// typedNilLabel renders the interface value v, which holds a nil value, as
+// selected by TypedNils.
+func (d *Dumper) typedNilLabel(v reflect.Value) string {
+	switch d.config.TypedNils {
+	case TypedNilPlain:
+		return d.nilLabel(v.Elem())
+	case TypedNilAnnotated:
+		return "(" + d.typeName(v.Elem().Type()) + ")(nil) ⚠ non-nil interface"
+	default:
+		return "(" + d.typeName(v.Elem().Type()) + ")(nil)"
+	}
+}
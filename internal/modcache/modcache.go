@@ -0,0 +1,71 @@
+// Package modcache locates modules in the Go module cache, downloading them
+// with "go mod download" when they are missing. It is shared by the packages
+// that load released versions of modules from source.
+package modcache
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"golang.org/x/mod/module"
+)
+
+// cacheRoot returns the root directory of the module cache, as reported by
+// "go env GOMODCACHE".
+var cacheRoot = sync.OnceValues(func() (string, error) {
+	out, err := exec.Command("go", "env", "GOMODCACHE").Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to locate the module cache: %w", err)
+	}
+	return strings.TrimSpace(string(out)), nil
+})
+
+// Dir returns the directory of the module modPath at version in the module
+// cache, or "" if that version has not been downloaded.
+func Dir(modPath, version string) (string, error) {
+	root, err := cacheRoot()
+	if err != nil {
+		return "", err
+	}
+	escPath, err := module.EscapePath(modPath)
+	if err != nil {
+		return "", err
+	}
+	escVersion, err := module.EscapeVersion(version)
+	if err != nil {
+		return "", err
+	}
+	dir := filepath.Join(root, filepath.FromSlash(escPath)+"@"+escVersion)
+	if _, err := os.Stat(filepath.Join(dir, "go.mod")); err != nil {
+		return "", nil
+	}
+	return dir, nil
+}
+
+// Download downloads the module modPath at version into the module cache with
+// "go mod download" and returns its directory.
+func Download(modPath, version string) (string, error) {
+	moduleVersion := modPath + "@" + version
+	var stderr bytes.Buffer
+	cmd := exec.Command("go", "mod", "download", "-json", moduleVersion)
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	var info struct{ Dir, Error string }
+	if jsonErr := json.Unmarshal(out, &info); jsonErr != nil && err == nil {
+		err = jsonErr
+	}
+	switch {
+	case info.Error != "":
+		return "", errors.New(info.Error)
+	case err != nil:
+		return "", fmt.Errorf("failed to download %s: %w: %s", moduleVersion, err, strings.TrimSpace(stderr.String()))
+	}
+	return info.Dir, nil
+}
@@ -0,0 +1,245 @@
+// Package introspect provides utilities for looking up declarations of Go
+// packages from source. This file compares the exported API of two versions of
+// a package, for reviewing whether a release needs a new major version.
+package introspect
+
+import (
+	"errors"
+	"fmt"
+	"go/types"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/janvaclavik/govar/internal/modcache"
+	"golang.org/x/mod/module"
+	"golang.org/x/tools/go/packages"
+)
+
+// APIDiff lists the differences between the exported APIs of two versions of a
+// package. Declarations are written in Go syntax, with types of the package
+// itself unqualified, and the lists are sorted by name. It is meant to be
+// dumped with govar (e.g. govar.Dump(diff)) or inspected by tools.
+type APIDiff struct {
+	Added   []APIEntry  // Declarations only in the new version.
+	Removed []APIEntry  // Declarations only in the old version.
+	Changed []APIChange // Declarations whose signature, type, or kind changed.
+}
+
+// APIEntry is an exported declaration: a type, function, variable, constant,
+// or a member (field or method) of an exported type.
+type APIEntry struct {
+	Name string // Qualified within the package, e.g. "NewClient", "Client" or "Client.Timeout".
+	Decl string // Declaration, e.g. "func NewClient(addr string) *Client" or "field Timeout time.Duration".
+}
+
+// APIChange is a declaration present in both versions with different forms.
+type APIChange struct {
+	Name string // Qualified within the package, as in APIEntry.
+	Old  string // Declaration in the old version.
+	New  string // Declaration in the new version.
+}
+
+// Breaking reports whether the diff contains removed or changed declarations,
+// which break existing users of the package. Changes to interface method sets
+// count too, as they break implementers or callers.
+func (d APIDiff) Breaking() bool {
+	return len(d.Removed) > 0 || len(d.Changed) > 0
+}
+
+// DiffAPI loads two versions of a package and compares their exported APIs:
+// exported types (with their exported fields, methods, and interface method
+// sets), functions, variables, and constants.
+//
+// Each version is given as an import path or directory pattern loaded from the
+// working directory (e.g. "./store"), or as an import path with a module
+// version (e.g. "github.com/acme/shop/store@v1.4.0"), loaded from the module
+// cache and downloaded if needed.
+//
+// Returns an error if either package fails to load.
+func DiffAPI(oldPkg, newPkg string) (APIDiff, error) {
+	oldAPI, err := loadAPI(oldPkg)
+	if err != nil {
+		return APIDiff{}, err
+	}
+	newAPI, err := loadAPI(newPkg)
+	if err != nil {
+		return APIDiff{}, err
+	}
+
+	var diff APIDiff
+	for _, name := range sortedKeys(oldAPI) {
+		newDecl, ok := newAPI[name]
+		switch {
+		case !ok:
+			diff.Removed = append(diff.Removed, APIEntry{Name: name, Decl: oldAPI[name]})
+		case newDecl != oldAPI[name]:
+			diff.Changed = append(diff.Changed, APIChange{Name: name, Old: oldAPI[name], New: newDecl})
+		}
+	}
+	for _, name := range sortedKeys(newAPI) {
+		if _, ok := oldAPI[name]; !ok {
+			diff.Added = append(diff.Added, APIEntry{Name: name, Decl: newAPI[name]})
+		}
+	}
+	return diff, nil
+}
+
+// loadAPI loads the package given as described by DiffAPI and returns its
+// exported declarations, keyed by name.
+func loadAPI(spec string) (map[string]string, error) {
+	cfg := &packages.Config{Mode: packages.NeedName | packages.NeedTypes | packages.NeedDeps | packages.NeedImports}
+	pattern := spec
+	if pkgPath, version, ok := strings.Cut(spec, "@"); ok {
+		dir, err := packageModuleDir(pkgPath, version)
+		if err != nil {
+			return nil, err
+		}
+		cfg.Dir, pattern = dir, pkgPath
+	}
+
+	pkgs, err := packages.Load(cfg, pattern)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load packages: %w", err)
+	}
+	if len(pkgs) != 1 || pkgs[0].Types == nil {
+		return nil, fmt.Errorf("%s must match exactly one package", spec)
+	}
+	if len(pkgs[0].Errors) > 0 {
+		return nil, fmt.Errorf("failed to load %s: %w", spec, pkgs[0].Errors[0])
+	}
+	return exportedAPI(pkgs[0].Types), nil
+}
+
+// exportedAPI returns the exported declarations of pkg, keyed by name.
+func exportedAPI(pkg *types.Package) map[string]string {
+	qualifier := types.RelativeTo(pkg)
+	typeString := func(t types.Type) string { return types.TypeString(t, qualifier) }
+	signature := func(sig *types.Signature) string {
+		return strings.TrimPrefix(types.TypeString(types.NewSignatureType(nil, nil, nil, sig.Params(), sig.Results(), sig.Variadic()), qualifier), "func")
+	}
+
+	api := map[string]string{}
+	scope := pkg.Scope()
+	for _, name := range scope.Names() {
+		obj := scope.Lookup(name)
+		if !obj.Exported() {
+			continue
+		}
+		switch obj := obj.(type) {
+		case *types.Func:
+			api[name] = "func " + name + signature(obj.Type().(*types.Signature))
+		case *types.Var:
+			api[name] = "var " + name + " " + typeString(obj.Type())
+		case *types.Const:
+			api[name] = "const " + name + " " + typeString(obj.Type())
+		case *types.TypeName:
+			addTypeAPI(api, obj, typeString, signature)
+		}
+	}
+	return api
+}
+
+// addTypeAPI adds the declaration of the exported type obj to api, followed by
+// its exported fields and methods or, for interfaces, its method set.
+func addTypeAPI(api map[string]string, obj *types.TypeName, typeString func(types.Type) string, signature func(*types.Signature) string) {
+	name := obj.Name()
+	typeParams := ""
+	if named, ok := obj.Type().(*types.Named); ok && named.TypeParams().Len() > 0 {
+		params := make([]string, named.TypeParams().Len())
+		for i := range params {
+			param := named.TypeParams().At(i)
+			params[i] = param.Obj().Name() + " " + typeString(param.Constraint())
+		}
+		typeParams = "[" + strings.Join(params, ", ") + "]"
+	}
+
+	if obj.IsAlias() {
+		api[name] = "type " + name + " = " + typeString(obj.Type())
+		return
+	}
+
+	switch underlying := obj.Type().Underlying().(type) {
+	case *types.Interface:
+		api[name] = "type " + name + typeParams + " interface"
+		for i := range underlying.NumMethods() {
+			if m := underlying.Method(i); m.Exported() {
+				api[name+"."+m.Name()] = "method " + m.Name() + signature(m.Type().(*types.Signature))
+			}
+		}
+		for i := range underlying.NumEmbeddeds() {
+			// Embedded interfaces contribute methods, listed above; the other
+			// embedded elements are type set terms of constraints.
+			if embedded := underlying.EmbeddedType(i); !types.IsInterface(embedded) {
+				api[name+".("+typeString(embedded)+")"] = "type set " + typeString(embedded)
+			}
+		}
+		return
+	case *types.Struct:
+		api[name] = "type " + name + typeParams + " struct"
+		for i := range underlying.NumFields() {
+			if f := underlying.Field(i); f.Exported() {
+				api[name+"."+f.Name()] = "field " + f.Name() + " " + typeString(f.Type())
+			}
+		}
+	default:
+		api[name] = "type " + name + typeParams + " " + typeString(underlying)
+	}
+
+	// Methods of T and *T, with the receiver they are declared on.
+	mset := types.NewMethodSet(types.NewPointer(obj.Type()))
+	for i := range mset.Len() {
+		m := mset.At(i).Obj().(*types.Func)
+		if !m.Exported() || len(mset.At(i).Index()) > 1 {
+			continue // Promoted methods are covered by the embedded field.
+		}
+		sig := m.Type().(*types.Signature)
+		receiver := name
+		if _, isPtr := sig.Recv().Type().(*types.Pointer); isPtr {
+			receiver = "*" + name
+		}
+		api[name+"."+m.Name()] = "func (" + receiver + ") " + m.Name() + signature(sig)
+	}
+}
+
+// sortedKeys returns the keys of m in ascending order.
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+// packageModuleDir returns the directory, in the module cache, of the module
+// providing the package pkgPath at the given version. The longest prefix of
+// pkgPath that is a cached module is used; otherwise the prefixes are
+// downloaded with "go mod download", longest first, until one succeeds.
+func packageModuleDir(pkgPath, version string) (string, error) {
+	var prefixes []string
+	for prefix := pkgPath; prefix != "." && prefix != "/"; prefix = filepath.ToSlash(filepath.Dir(prefix)) {
+		if module.Check(prefix, version) == nil {
+			prefixes = append(prefixes, prefix)
+		}
+	}
+	if len(prefixes) == 0 {
+		return "", fmt.Errorf("invalid package version: %s@%s", pkgPath, version)
+	}
+
+	for _, prefix := range prefixes {
+		if dir, err := modcache.Dir(prefix, version); err != nil || dir != "" {
+			return dir, err
+		}
+	}
+
+	var errs []error
+	for _, prefix := range prefixes {
+		dir, err := modcache.Download(prefix, version)
+		if err == nil {
+			return dir, nil
+		}
+		errs = append(errs, err)
+	}
+	return "", fmt.Errorf("failed to download a module providing %s@%s: %w", pkgPath, version, errors.Join(errs...))
+}
@@ -0,0 +1,97 @@
+package introspect
+
+import (
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestDiffAPI(t *testing.T) {
+	cache := t.TempDir()
+	t.Setenv("GOMODCACHE", cache)
+	t.Setenv("GOFLAGS", "-mod=mod")
+
+	goMod := "module example.com/shop\n\ngo 1.20\n"
+	oldDir := filepath.Join(cache, "example.com", "shop@v1.0.0")
+	mustWriteFile(t, oldDir, "go.mod", goMod)
+	mustWriteFile(t, oldDir, "store/store.go", `package store
+
+import "time"
+
+type Store interface {
+	Get(key string) (string, error)
+}
+
+type Client struct {
+	Addr    string
+	Timeout int
+	retries int
+}
+
+func (c *Client) Get(key string) (string, error) { return "", nil }
+
+func NewClient(addr string) *Client { return &Client{Addr: addr} }
+
+func Legacy() {}
+
+var DefaultTTL time.Duration
+`)
+	newDir := filepath.Join(cache, "example.com", "shop@v1.1.0")
+	mustWriteFile(t, newDir, "go.mod", goMod)
+	mustWriteFile(t, newDir, "store/store.go", `package store
+
+import "time"
+
+type Store interface {
+	Get(key string) (string, error)
+	Delete(key string) error
+}
+
+type Client struct {
+	Addr    string
+	Timeout time.Duration
+}
+
+func (c *Client) Get(key string) (string, error) { return "", nil }
+
+func (c *Client) Delete(key string) error { return nil }
+
+func NewClient(addr string, opts ...Option) *Client { return &Client{Addr: addr} }
+
+type Option func(*Client)
+
+var DefaultTTL time.Duration
+`)
+
+	diff, err := DiffAPI("example.com/shop/store@v1.0.0", "example.com/shop/store@v1.1.0")
+	if err != nil {
+		t.Fatalf("DiffAPI error: %v", err)
+	}
+
+	wantAdded := []APIEntry{
+		{Name: "Client.Delete", Decl: "func (*Client) Delete(key string) error"},
+		{Name: "Option", Decl: "type Option func(*Client)"},
+		{Name: "Store.Delete", Decl: "method Delete(key string) error"},
+	}
+	if !slices.Equal(diff.Added, wantAdded) {
+		t.Errorf("Added = %v, want %v", diff.Added, wantAdded)
+	}
+	wantRemoved := []APIEntry{{Name: "Legacy", Decl: "func Legacy()"}}
+	if !slices.Equal(diff.Removed, wantRemoved) {
+		t.Errorf("Removed = %v, want %v", diff.Removed, wantRemoved)
+	}
+	wantChanged := []APIChange{
+		{Name: "Client.Timeout", Old: "field Timeout int", New: "field Timeout time.Duration"},
+		{Name: "NewClient", Old: "func NewClient(addr string) *Client", New: "func NewClient(addr string, opts ...Option) *Client"},
+	}
+	if !slices.Equal(diff.Changed, wantChanged) {
+		t.Errorf("Changed = %v, want %v", diff.Changed, wantChanged)
+	}
+	if !diff.Breaking() {
+		t.Error("expected the diff to be breaking")
+	}
+
+	if _, err := DiffAPI("example.com/shop/store@v1.0.0", "example.com/shop/nosuchpkg@v1.0.0"); err == nil {
+		t.Error("expected an error for a missing package")
+	}
+}
@@ -0,0 +1,158 @@
+// Package introspect provides utilities for looking up declarations of Go
+// packages from source. This file generates reflection-free debug String
+// methods that render a struct in govar's block layout.
+package introspect
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"go/types"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// GenerateDebugString generates the source of a Go file declaring a method
+// with the given name (typically "String" or "DebugString") for the struct type
+// identified by the fully-qualified name. The method renders the struct in
+// govar's block layout, one "⯀ Field  type => value" line per field, without
+// using reflection, for hot paths where reflective dumping is too slow.
+//
+// Booleans, numbers, strings, and fields whose type has a String() string
+// method are formatted directly; other fields fall back to fmt's %v.
+//
+// Returns an error if the type cannot be resolved, is not a struct, or is
+// generic.
+func GenerateDebugString(typeFullName, methodName string) ([]byte, error) {
+	typePkgPath, typeName, err := splitTypeName(typeFullName)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := &packages.Config{
+		Mode: packages.NeedName | packages.NeedTypes | packages.NeedTypesInfo | packages.NeedImports | packages.NeedDeps | packages.NeedSyntax,
+	}
+	pkgs, err := packages.Load(cfg, typePkgPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load packages: %w", err)
+	}
+
+	var named *types.Named
+	var pkg *types.Package
+	for _, p := range pkgs {
+		if p.PkgPath != typePkgPath || p.Types == nil {
+			continue
+		}
+		if obj, ok := p.Types.Scope().Lookup(typeName).(*types.TypeName); ok {
+			named, _ = obj.Type().(*types.Named)
+			pkg = p.Types
+			break
+		}
+	}
+	if named == nil {
+		return nil, fmt.Errorf("type %s not found in package %s", typeName, typePkgPath)
+	}
+	st, ok := named.Underlying().(*types.Struct)
+	if !ok {
+		return nil, fmt.Errorf("type %s is not a struct", typeFullName)
+	}
+	if named.TypeParams().Len() > 0 {
+		return nil, fmt.Errorf("type %s is generic", typeFullName)
+	}
+
+	qualifier := func(p *types.Package) string { return p.Name() }
+	maxKeyLen, maxTypeLen := 0, 0
+	for i := range st.NumFields() {
+		maxKeyLen = max(maxKeyLen, utf8.RuneCountInString(st.Field(i).Name()))
+		maxTypeLen = max(maxTypeLen, utf8.RuneCountInString(types.TypeString(st.Field(i).Type(), qualifier)))
+	}
+
+	imports := map[string]bool{"strings": true}
+	body := &bytes.Buffer{}
+	fmt.Fprintf(body, "\tvar sb strings.Builder\n")
+	fmt.Fprintf(body, "\tsb.WriteString(%q)\n", pkg.Name()+"."+typeName+" => {\n")
+	for i := range st.NumFields() {
+		field := st.Field(i)
+		symbol := "⯀ "
+		if !field.Exported() {
+			symbol = "🞏 "
+		}
+		typeString := types.TypeString(field.Type(), qualifier)
+		prefix := "   " + symbol +
+			field.Name() + strings.Repeat(" ", maxKeyLen-utf8.RuneCountInString(field.Name())) + "  " +
+			typeString + strings.Repeat(" ", maxTypeLen-utf8.RuneCountInString(typeString)) + " => "
+		expr, imp := debugStringExpr("v."+field.Name(), field.Type())
+		imports[imp] = true
+		fmt.Fprintf(body, "\tsb.WriteString(%q)\n", prefix)
+		fmt.Fprintf(body, "\tsb.WriteString(%s)\n", expr)
+		fmt.Fprintf(body, "\tsb.WriteString(\"\\n\")\n")
+	}
+	fmt.Fprintf(body, "\tsb.WriteString(\"}\")\n")
+	fmt.Fprintf(body, "\treturn sb.String()\n")
+
+	delete(imports, "")
+	importPaths := make([]string, 0, len(imports))
+	for imp := range imports {
+		importPaths = append(importPaths, fmt.Sprintf("%q", imp))
+	}
+	slices.Sort(importPaths)
+
+	src := &bytes.Buffer{}
+	fmt.Fprintf(src, "// Code generated by govar introspect.GenerateDebugString; DO NOT EDIT.\n\n")
+	fmt.Fprintf(src, "package %s\n\n", pkg.Name())
+	fmt.Fprintf(src, "import (\n\t%s\n)\n\n", strings.Join(importPaths, "\n\t"))
+	fmt.Fprintf(src, "// %s renders %s in govar's block layout, without reflection.\n", methodName, typeName)
+	fmt.Fprintf(src, "func (v %s) %s() string {\n%s}\n", typeName, methodName, body)
+
+	return format.Source(src.Bytes())
+}
+
+// debugStringExpr returns a string expression formatting the value of expr,
+// of type t, like govar does for its kind, and the import the expression needs.
+func debugStringExpr(expr string, t types.Type) (string, string) {
+	if hasStringMethod(t) {
+		return expr + ".String()", ""
+	}
+	basic, ok := t.Underlying().(*types.Basic)
+	if !ok {
+		return fmt.Sprintf("fmt.Sprintf(\"%%v\", %s)", expr), "fmt"
+	}
+	info := basic.Info()
+	switch {
+	case info&types.IsBoolean != 0:
+		return fmt.Sprintf("strconv.FormatBool(bool(%s))", expr), "strconv"
+	case info&types.IsString != 0:
+		return fmt.Sprintf("strconv.Quote(string(%s))", expr), "strconv"
+	case info&types.IsUnsigned != 0:
+		return fmt.Sprintf("strconv.FormatUint(uint64(%s), 10)", expr), "strconv"
+	case info&types.IsInteger != 0:
+		return fmt.Sprintf("strconv.FormatInt(int64(%s), 10)", expr), "strconv"
+	case info&types.IsFloat != 0:
+		return fmt.Sprintf("strconv.FormatFloat(float64(%s), 'f', 6, 64)", expr), "strconv"
+	default:
+		return fmt.Sprintf("fmt.Sprintf(\"%%v\", %s)", expr), "fmt"
+	}
+}
+
+// hasStringMethod reports whether values of type t have a String() string
+// method. Pointer types are excluded, as their method would panic when nil.
+func hasStringMethod(t types.Type) bool {
+	if _, isPtr := t.Underlying().(*types.Pointer); isPtr {
+		return false
+	}
+	if _, isIface := t.Underlying().(*types.Interface); isIface {
+		return false
+	}
+	sel := types.NewMethodSet(t).Lookup(nil, "String")
+	if sel == nil {
+		return false
+	}
+	sig, ok := sel.Type().(*types.Signature)
+	if !ok || sig.Params().Len() != 0 || sig.Results().Len() != 1 {
+		return false
+	}
+	return types.Identical(sig.Results().At(0).Type(), types.Typ[types.String])
+}
@@ -0,0 +1,88 @@
+package introspect
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"golang.org/x/tools/go/packages"
+)
+
+func TestGenerateDebugString(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	goMod := `module testmod
+
+go 1.20
+`
+
+	// --- model/model.go ---
+	modelCode := `package model
+
+import "time"
+
+type Status int
+
+func (s Status) String() string { return "status" }
+
+type User struct {
+	Name    string
+	Age     int
+	Admin   bool
+	Status  Status
+	Timeout time.Duration
+	Tags    []string
+	score   float64
+}
+`
+
+	mustWriteFile(t, tmpDir, "go.mod", goMod)
+	mustWriteFile(t, tmpDir, "model/model.go", modelCode)
+
+	oldWd, _ := os.Getwd()
+	defer os.Chdir(oldWd)
+	if err := os.Chdir(tmpDir); err != nil {
+		t.Fatalf("chdir failed: %v", err)
+	}
+
+	src, err := GenerateDebugString("testmod/model.User", "DebugString")
+	if err != nil {
+		t.Fatalf("GenerateDebugString error: %v", err)
+	}
+	out := string(src)
+	t.Logf("Generated:\n%s", out)
+
+	for _, want := range []string{
+		"// Code generated by govar introspect.GenerateDebugString; DO NOT EDIT.",
+		"package model",
+		"func (v User) DebugString() string {",
+		`sb.WriteString("model.User => {\n")`,
+		`sb.WriteString("   ⯀ Name     string        => ")`,
+		"sb.WriteString(strconv.Quote(string(v.Name)))",
+		"sb.WriteString(strconv.FormatInt(int64(v.Age), 10))",
+		"sb.WriteString(v.Status.String())",
+		"sb.WriteString(v.Timeout.String())",
+		`sb.WriteString(fmt.Sprintf("%v", v.Tags))`,
+		`sb.WriteString("   🞏 score    float64       => ")`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in generated source", want)
+		}
+	}
+
+	// The generated file must compile alongside the type.
+	mustWriteFile(t, tmpDir, "model/user_debug.go", out)
+	pkgs, err := packages.Load(&packages.Config{Mode: packages.NeedTypes | packages.NeedSyntax | packages.NeedDeps | packages.NeedImports}, "./model")
+	if err != nil {
+		t.Fatalf("load error: %v", err)
+	}
+	if packages.PrintErrors(pkgs) > 0 {
+		t.Errorf("generated source does not compile")
+	}
+
+	for _, bad := range []string{"testmod/model.Status", "testmod/model.Missing"} {
+		if _, err := GenerateDebugString(bad, "DebugString"); err == nil {
+			t.Errorf("GenerateDebugString(%s): expected an error", bad)
+		}
+	}
+}
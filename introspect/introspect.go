@@ -0,0 +1,135 @@
+// Package introspect provides utilities for looking up declarations of Go
+// packages from source, such as the named constants declared for enum-like
+// types, for use by the dumper and by external tools. It can also generate
+// reflection-free debug String methods that mirror the dumper's layout,
+// compare the exported APIs of two versions of a package, and tell which
+// embedded types the methods of a type are promoted from.
+package introspect
+
+import (
+	"fmt"
+	"go/constant"
+	"go/token"
+	"go/types"
+	"reflect"
+	"slices"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// Const is a named constant declared for a type.
+type Const struct {
+	Name  string         // Constant name, e.g. "StatusActive".
+	Value constant.Value // Constant value; use ExactString for a printable form.
+}
+
+// Consts returns the constants of the type identified by the fully-qualified
+// name (e.g. "time.Month" or "mypkg.Status"), in declaration order.
+//
+// Returns an error if the type cannot be resolved or the package fails to load.
+func Consts(typeFullName string) ([]Const, error) {
+	typePkgPath, typeName, err := splitTypeName(typeFullName)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := &packages.Config{
+		Mode: packages.NeedName | packages.NeedTypes | packages.NeedTypesInfo | packages.NeedImports | packages.NeedDeps | packages.NeedSyntax,
+	}
+	pkgs, err := packages.Load(cfg, typePkgPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load packages: %w", err)
+	}
+
+	for _, pkg := range pkgs {
+		if pkg.PkgPath != typePkgPath || pkg.Types == nil {
+			continue
+		}
+		scope := pkg.Types.Scope()
+		typeObj, ok := scope.Lookup(typeName).(*types.TypeName)
+		if !ok {
+			continue
+		}
+
+		var consts []*types.Const
+		for _, name := range scope.Names() {
+			if c, ok := scope.Lookup(name).(*types.Const); ok && types.Identical(c.Type(), typeObj.Type()) {
+				consts = append(consts, c)
+			}
+		}
+		slices.SortFunc(consts, func(a, b *types.Const) int { return int(a.Pos() - b.Pos()) })
+
+		result := make([]Const, len(consts))
+		for i, c := range consts {
+			result[i] = Const{Name: c.Name(), Value: c.Val()}
+		}
+		return result, nil
+	}
+
+	return nil, fmt.Errorf("type %s not found in package %s", typeName, typePkgPath)
+}
+
+// ConstName returns the name of the first declared constant of the type
+// identified by the fully-qualified name whose value equals value, e.g.
+// ConstName("time.Month", time.March) returns "March". The value may be of the
+// type itself or of any type with the same kind of underlying value.
+//
+// Returns an error if the type cannot be resolved, the package fails to load,
+// or no constant has the value.
+func ConstName(typeFullName string, value any) (string, error) {
+	want, ok := constantOf(value)
+	if !ok {
+		return "", fmt.Errorf("unsupported constant value: %v (%T)", value, value)
+	}
+
+	consts, err := Consts(typeFullName)
+	if err != nil {
+		return "", err
+	}
+	for _, c := range consts {
+		if sameKind(c.Value, want) && constant.Compare(c.Value, token.EQL, want) {
+			return c.Name, nil
+		}
+	}
+
+	return "", fmt.Errorf("no constant of type %s has value %v", typeFullName, value)
+}
+
+// constantOf converts a boolean, numeric, or string value to a constant.Value.
+func constantOf(value any) (constant.Value, bool) {
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Bool:
+		return constant.MakeBool(v.Bool()), true
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return constant.MakeInt64(v.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return constant.MakeUint64(v.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return constant.MakeFloat64(v.Float()), true
+	case reflect.String:
+		return constant.MakeString(v.String()), true
+	default:
+		return nil, false
+	}
+}
+
+// sameKind reports whether two constants can be compared: both are booleans,
+// both are strings, or both are numbers.
+func sameKind(a, b constant.Value) bool {
+	isNumber := func(k constant.Kind) bool { return k == constant.Int || k == constant.Float }
+	return a.Kind() == b.Kind() || (isNumber(a.Kind()) && isNumber(b.Kind()))
+}
+
+// splitTypeName splits a fully-qualified type string such as "mypkg.MyType"
+// into its package path ("mypkg") and type name ("MyType") components.
+//
+// Returns an error if the format is invalid (e.g. missing a dot separator).
+func splitTypeName(full string) (pkgPath, typeName string, err error) {
+	lastDot := strings.LastIndex(full, ".")
+	if lastDot < 0 {
+		return "", "", fmt.Errorf("invalid type name: %s", full)
+	}
+	return full[:lastDot], full[lastDot+1:], nil
+}
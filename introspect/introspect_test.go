@@ -0,0 +1,119 @@
+package introspect
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func mustWriteFile(t *testing.T, root, relPath, content string) {
+	fullPath := filepath.Join(root, relPath)
+	if err := os.MkdirAll(filepath.Dir(fullPath), 0755); err != nil {
+		t.Fatalf("mkdir failed: %v", err)
+	}
+	if err := os.WriteFile(fullPath, []byte(content), 0644); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+}
+
+func TestConstsStdlib(t *testing.T) {
+	consts, err := Consts("time.Month")
+	if err != nil {
+		t.Fatalf("Consts error: %v", err)
+	}
+	if len(consts) != 12 {
+		t.Fatalf("expected 12 months, got %d: %v", len(consts), consts)
+	}
+	if consts[0].Name != "January" || consts[0].Value.ExactString() != "1" {
+		t.Errorf("expected January = 1 first, got %s = %s", consts[0].Name, consts[0].Value)
+	}
+	if consts[11].Name != "December" {
+		t.Errorf("expected December last, got %s", consts[11].Name)
+	}
+}
+
+func TestConstName(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	goMod := `module testmod
+
+go 1.20
+`
+
+	// --- status/status.go ---
+	statusCode := `package status
+
+type Status int
+
+const (
+	Unknown Status = iota
+	Active
+	Suspended
+	Default = Active
+)
+
+type Level string
+
+const (
+	Low  Level = "low"
+	High Level = "high"
+)
+
+const Unrelated = 2
+`
+
+	mustWriteFile(t, tmpDir, "go.mod", goMod)
+	mustWriteFile(t, tmpDir, "status/status.go", statusCode)
+
+	oldWd, _ := os.Getwd()
+	defer os.Chdir(oldWd)
+	if err := os.Chdir(tmpDir); err != nil {
+		t.Fatalf("chdir failed: %v", err)
+	}
+
+	tests := []struct {
+		typeName string
+		value    any
+		want     string
+	}{
+		{"testmod/status.Status", 0, "Unknown"},
+		{"testmod/status.Status", uint8(1), "Active"}, // Declared before the Default alias.
+		{"testmod/status.Status", 2, "Suspended"},
+		{"testmod/status.Level", "high", "High"},
+		{"time.Month", time.March, "March"},
+	}
+	for _, tt := range tests {
+		got, err := ConstName(tt.typeName, tt.value)
+		if err != nil {
+			t.Errorf("ConstName(%s, %v) error: %v", tt.typeName, tt.value, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ConstName(%s, %v) = %q, want %q", tt.typeName, tt.value, got, tt.want)
+		}
+	}
+
+	consts, err := Consts("testmod/status.Status")
+	if err != nil {
+		t.Fatalf("Consts error: %v", err)
+	}
+	if len(consts) != 4 {
+		t.Errorf("expected 4 Status constants, got %v", consts)
+	}
+
+	for _, bad := range []struct {
+		typeName string
+		value    any
+	}{
+		{"testmod/status.Status", 7},
+		{"testmod/status.Status", "active"},
+		{"testmod/status.Missing", 0},
+		{"nodot", 0},
+		{"testmod/status.Status", []int{1}},
+	} {
+		if _, err := ConstName(bad.typeName, bad.value); err == nil {
+			t.Errorf("ConstName(%s, %v): expected an error", bad.typeName, bad.value)
+		}
+	}
+}
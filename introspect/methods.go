@@ -0,0 +1,117 @@
+// Package introspect provides utilities for looking up declarations of Go
+// packages from source. This file reports where the methods of a type come
+// from: the type itself or, at some embedding depth, one of its embedded types.
+package introspect
+
+import (
+	"fmt"
+	"go/types"
+	"slices"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// MethodOrigin describes a method in the method set of a type.
+type MethodOrigin struct {
+	Name        string   // Method name, e.g. "Close".
+	Signature   string   // Parameters and results, e.g. "(ctx context.Context) error".
+	Provider    string   // Fully-qualified type declaring the method, e.g. "io.PipeReader".
+	Via         []string // Embedded fields leading to the provider, outermost first (empty for own methods).
+	Depth       int      // Embedding depth: 0 for methods declared on the type itself.
+	PointerOnly bool     // The method is only in the method set of *T (its receiver is a pointer).
+}
+
+// Methods returns the method set of *T for the named type identified by the
+// fully-qualified name (e.g. "bufio.ReadWriter" or "mypkg.Service"), sorted by
+// name, reporting for each method which type declares it and through which
+// embedded fields it is promoted. Unexported methods are included.
+//
+// Promotion follows the compiler's rules: a method at a shallower depth hides
+// those of the same name deeper down, and methods found more than once at the
+// shallowest depth are ambiguous and not part of the method set.
+//
+// Returns an error if the type cannot be resolved or the package fails to load.
+func Methods(typeFullName string) ([]MethodOrigin, error) {
+	typePkgPath, typeName, err := splitTypeName(typeFullName)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := &packages.Config{
+		Mode: packages.NeedName | packages.NeedTypes | packages.NeedTypesInfo | packages.NeedImports | packages.NeedDeps | packages.NeedSyntax,
+	}
+	pkgs, err := packages.Load(cfg, typePkgPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load packages: %w", err)
+	}
+
+	for _, pkg := range pkgs {
+		if pkg.PkgPath != typePkgPath || pkg.Types == nil {
+			continue
+		}
+		obj, ok := pkg.Types.Scope().Lookup(typeName).(*types.TypeName)
+		if !ok {
+			continue
+		}
+		return methodOrigins(obj.Type(), pkg.Types), nil
+	}
+
+	return nil, fmt.Errorf("type %s not found in package %s", typeName, typePkgPath)
+}
+
+// methodOrigins returns the origins of the methods in the method set of *t
+// (or of t, for interfaces), with types of pkg written unqualified in
+// signatures.
+func methodOrigins(t types.Type, pkg *types.Package) []MethodOrigin {
+	valueSet := types.NewMethodSet(t)
+	fullSet := valueSet
+	if !types.IsInterface(t) {
+		fullSet = types.NewMethodSet(types.NewPointer(t))
+	}
+
+	var result []MethodOrigin
+	for i := range fullSet.Len() {
+		sel := fullSet.At(i)
+		m := sel.Obj().(*types.Func)
+		sig := m.Type().(*types.Signature)
+		origin := MethodOrigin{
+			Name:        m.Name(),
+			Signature:   strings.TrimPrefix(types.TypeString(types.NewSignatureType(nil, nil, nil, sig.Params(), sig.Results(), sig.Variadic()), types.RelativeTo(pkg)), "func"),
+			Provider:    types.TypeString(derefType(sig.Recv().Type()), nil),
+			Via:         embeddingPath(t, sel.Index()),
+			Depth:       len(sel.Index()) - 1,
+			PointerOnly: valueSet.Lookup(m.Pkg(), m.Name()) == nil,
+		}
+		if origin.Via == nil {
+			origin.Via = []string{}
+		}
+		result = append(result, origin)
+	}
+	slices.SortFunc(result, func(a, b MethodOrigin) int { return strings.Compare(a.Name, b.Name) })
+	return result
+}
+
+// embeddingPath returns the names of the embedded fields selected by all but
+// the last entry of index (see types.Selection.Index), starting at type t.
+func embeddingPath(t types.Type, index []int) []string {
+	var path []string
+	for _, i := range index[:len(index)-1] {
+		st, ok := derefType(t).Underlying().(*types.Struct)
+		if !ok {
+			break
+		}
+		field := st.Field(i)
+		path = append(path, field.Name())
+		t = field.Type()
+	}
+	return path
+}
+
+// derefType returns the element type of pointer types and t itself otherwise.
+func derefType(t types.Type) types.Type {
+	if ptr, ok := t.(*types.Pointer); ok {
+		return ptr.Elem()
+	}
+	return t
+}
@@ -0,0 +1,78 @@
+package introspect
+
+import (
+	"os"
+	"slices"
+	"testing"
+)
+
+func TestMethods(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	// --- svc/svc.go ---
+	svcCode := `package svc
+
+import "context"
+
+type Logger struct{}
+
+func (Logger) Log(msg string) {}
+func (Logger) Close() error   { return nil }
+
+type Conn struct{}
+
+func (*Conn) Close() error { return nil }
+func (*Conn) Ping(ctx context.Context) error { return nil }
+
+type Pool struct {
+	*Conn
+}
+
+func (*Pool) Size() int { return 0 }
+
+type Closer interface {
+	Close() error
+}
+
+type Service struct {
+	Logger
+	Pool
+	Closer
+}
+
+func (s Service) Name() string { return "svc" }
+`
+
+	mustWriteFile(t, tmpDir, "go.mod", "module testmod\n\ngo 1.20\n")
+	mustWriteFile(t, tmpDir, "svc/svc.go", svcCode)
+
+	oldWd, _ := os.Getwd()
+	defer os.Chdir(oldWd)
+	if err := os.Chdir(tmpDir); err != nil {
+		t.Fatalf("chdir failed: %v", err)
+	}
+
+	methods, err := Methods("testmod/svc.Service")
+	if err != nil {
+		t.Fatalf("Methods error: %v", err)
+	}
+
+	// Close is ambiguous at depth 1 (Logger and Closer), which hides the
+	// deeper Conn.Close, so it is not in the method set at all.
+	want := []MethodOrigin{
+		{Name: "Log", Signature: "(msg string)", Provider: "testmod/svc.Logger", Via: []string{"Logger"}, Depth: 1},
+		{Name: "Name", Signature: "() string", Provider: "testmod/svc.Service", Via: []string{}, Depth: 0},
+		{Name: "Ping", Signature: "(ctx context.Context) error", Provider: "testmod/svc.Conn", Via: []string{"Pool", "Conn"}, Depth: 2},
+		{Name: "Size", Signature: "() int", Provider: "testmod/svc.Pool", Via: []string{"Pool"}, Depth: 1, PointerOnly: true},
+	}
+	if !slices.EqualFunc(methods, want, func(a, b MethodOrigin) bool {
+		return a.Name == b.Name && a.Signature == b.Signature && a.Provider == b.Provider &&
+			slices.Equal(a.Via, b.Via) && a.Depth == b.Depth && a.PointerOnly == b.PointerOnly
+	}) {
+		t.Errorf("Methods() = %+v, want %+v", methods, want)
+	}
+
+	if _, err := Methods("testmod/svc.Missing"); err == nil {
+		t.Error("expected an error for an unknown type")
+	}
+}
@@ -0,0 +1,26 @@
+// Package govar provides a powerful and highly configurable pretty-printer for Go
+// data structures. This file renders the optional legend printed after a dump,
+// which explains the colors and symbols of the output to readers who are new
+// to govar (e.g. in shared terminal recordings).
+package govar
+
+import (
+	"strings"
+)
+
+// renderLegend writes a one-line legend with a sample of each token kind,
+// formatted exactly like the token appears in a dump.
+func (d *Dumper) renderLegend(sb *strings.Builder) {
+	entries := []string{
+		d.ApplyFormat(ColorGoldenrod, `"`) + d.ApplyFormat(ColorLime, "text") + d.ApplyFormat(ColorGoldenrod, `"`) + " string",
+		d.ApplyFormat(ColorSkyBlue, "42") + " number",
+		d.ApplyFormat(ColorDarkGray, "int") + " type",
+		d.ApplyFormat(ColorDarkGoBlue, "⯀") + " exported field",
+		d.ApplyFormat(ColorDarkGoBlue, "🞏") + " unexported field",
+		d.ApplyFormat(ColorDimGray, "|3|") + " metadata",
+		d.ApplyFormat(ColorGoldenrod, "&1") + " reference ID",
+		d.ApplyFormat(ColorPink, "↩︎ &1") + " back-reference",
+		d.ApplyFormat(ColorCoralRed, "<nil>") + " nil",
+	}
+	sb.WriteString(d.ApplyFormat(ColorSlateGray, "legend:") + " " + strings.Join(entries, "   ") + "\n")
+}
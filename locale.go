@@ -0,0 +1,65 @@
+// Package govar provides a powerful and highly configurable pretty-printer for Go
+// data structures. This file implements locale-aware number formatting: the
+// decimal separator and digit grouping of rendered numbers and meta hints can
+// follow conventions other than Go's.
+package govar
+
+import (
+	"strconv"
+	"strings"
+)
+
+// NumberLocale describes how numbers are written. The zero value keeps Go's
+// formatting (a "." decimal separator and no digit grouping).
+type NumberLocale struct {
+	Decimal  string // Decimal separator ("." if empty).
+	Grouping string // Separator inserted between groups of three integer digits ("" disables grouping).
+}
+
+// Common number locales.
+var (
+	LocaleEnglish = NumberLocale{Decimal: ".", Grouping: ","}      // 1,234,567.89
+	LocaleGerman  = NumberLocale{Decimal: ",", Grouping: "."}      // 1.234.567,89
+	LocaleFrench  = NumberLocale{Decimal: ",", Grouping: "\u202f"} // 1 234 567,89 (narrow no-break space)
+	LocaleSwiss   = NumberLocale{Decimal: ".", Grouping: "'"}      // 1'234'567.89
+)
+
+// format rewrites a number formatted by Go (e.g. "-1234.500000") for the
+// locale. Strings that are not plain decimal numbers (NaN, ±Inf, exponents)
+// are returned unchanged.
+func (l NumberLocale) format(s string) string {
+	if l == (NumberLocale{}) {
+		return s
+	}
+	sign, digits := "", s
+	if strings.HasPrefix(digits, "-") || strings.HasPrefix(digits, "+") {
+		sign, digits = digits[:1], digits[1:]
+	}
+	intPart, frac, hasFrac := strings.Cut(digits, ".")
+	if intPart == "" || strings.Trim(intPart, "0123456789") != "" || strings.Trim(frac, "0123456789") != "" {
+		return s
+	}
+	if l.Grouping != "" {
+		grouped := &strings.Builder{}
+		for i, digit := range intPart {
+			if i > 0 && (len(intPart)-i)%3 == 0 {
+				grouped.WriteString(l.Grouping)
+			}
+			grouped.WriteRune(digit)
+		}
+		intPart = grouped.String()
+	}
+	if !hasFrac {
+		return sign + intPart
+	}
+	decimal := l.Decimal
+	if decimal == "" {
+		decimal = "."
+	}
+	return sign + intPart + decimal + frac
+}
+
+// localizeInt formats a count shown in a meta hint for the configured Locale.
+func (d *Dumper) localizeInt(n int) string {
+	return d.config.Locale.format(strconv.Itoa(n))
+}
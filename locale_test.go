@@ -0,0 +1,49 @@
+package govar
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNumberLocaleFormat(t *testing.T) {
+	tests := []struct {
+		locale NumberLocale
+		in     string
+		want   string
+	}{
+		{NumberLocale{}, "1234567.5", "1234567.5"},
+		{LocaleEnglish, "1234567.500000", "1,234,567.500000"},
+		{LocaleGerman, "-1234.5", "-1.234,5"},
+		{LocaleGerman, "123", "123"},
+		{LocaleSwiss, "1000", "1'000"},
+		{LocaleFrench, "12345", "12\u202f345"},
+		{NumberLocale{Decimal: ","}, "1234.5", "1234,5"},
+		{LocaleGerman, "NaN", "NaN"},
+		{LocaleGerman, "+Inf", "+Inf"},
+	}
+	for _, tt := range tests {
+		if got := tt.locale.format(tt.in); got != tt.want {
+			t.Errorf("%+v.format(%q) = %q, want %q", tt.locale, tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestDumpLocale(t *testing.T) {
+	type Stats struct {
+		Requests int
+		Latency  float64
+		Samples  []int
+	}
+	cfg := DefaultConfig
+	cfg.UseColors = false
+	cfg.EmbedTypeMethods = false
+	cfg.MaxItems = 2
+	cfg.Locale = LocaleGerman
+
+	out := NewDumper(cfg).Sdump(Stats{Requests: 1234567, Latency: 2.5, Samples: make([]int, 1500)})
+	for _, want := range []string{"=> 1.234.567\n", "=> 2,500000\n", "|1.500| ["} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q, got:\n%s", want, out)
+		}
+	}
+}
@@ -0,0 +1,223 @@
+// Package govar provides a powerful and highly configurable pretty-printer for Go
+// data structures. This file implements structure matching: comparing a value
+// against a partial pattern and reporting every mismatch with both sides
+// rendered by the dumper.
+package govar
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Wildcard is a placeholder in Match patterns that accepts values by a property
+// instead of by equality.
+type Wildcard int
+
+const (
+	Any     Wildcard = iota + 1 // Matches any value, including nil.
+	NonZero                     // Matches any value that is not the zero value of its type.
+)
+
+// matchReportConfig renders the values shown in Match reports.
+var matchReportConfig = DumperConfig{
+	IndentWidth:     3,
+	MaxDepth:        5,
+	MaxItems:        20,
+	MaxStringLen:    200,
+	MaxInlineLength: 80,
+	ShowTypes:       true,
+}
+
+// Match reports whether value matches pattern, and otherwise returns a report
+// listing every mismatch by path. Patterns are partial:
+//
+//   - struct patterns only check their non-zero fields, matched by name, so a
+//     pattern may be the same type as value or an anonymous struct of a subset;
+//   - map patterns only check their keys, against map keys or struct fields;
+//   - slice and array patterns must match length and every element;
+//   - Any and NonZero match by property, nil matches nil values;
+//   - other values are compared for equality, converting between numeric types.
+//
+// Pointers and interfaces are followed on both sides.
+func Match(value, pattern any) (bool, string) {
+	m := &matcher{}
+	m.match("", reflect.ValueOf(value), reflect.ValueOf(pattern))
+	if len(m.mismatches) == 0 {
+		return true, ""
+	}
+	noun := "mismatches"
+	if len(m.mismatches) == 1 {
+		noun = "mismatch"
+	}
+	return false, fmt.Sprintf("%d %s:\n%s", len(m.mismatches), noun, strings.Join(m.mismatches, "\n"))
+}
+
+// matcher collects the mismatches found while walking a value and a pattern.
+type matcher struct {
+	mismatches []string
+}
+
+// match compares v against the pattern p at the given path.
+func (m *matcher) match(path string, v, p reflect.Value) {
+	p = derefAll(p)
+	if p.IsValid() && p.Type() == reflect.TypeOf(Any) {
+		if p.Interface() == NonZero && (!derefAll(v).IsValid() || derefAll(v).IsZero()) {
+			m.fail(path, "want a non-zero value", v)
+		}
+		return
+	}
+	v = derefAll(v)
+	if !p.IsValid() {
+		if v.IsValid() && !isNil(v) {
+			m.fail(path, "want nil", v)
+		}
+		return
+	}
+	if !v.IsValid() {
+		m.fail(path, "want "+renderForReport(p), v)
+		return
+	}
+
+	switch p.Kind() {
+	case reflect.Struct:
+		if v.Kind() != reflect.Struct {
+			m.fail(path, "want a struct", v)
+			return
+		}
+		for i := range p.NumField() {
+			pf := tryExport(p.Field(i))
+			if pf.IsZero() {
+				continue
+			}
+			name := p.Type().Field(i).Name
+			vf := v.FieldByName(name)
+			if !vf.IsValid() {
+				m.fail(joinPath(path, name), "missing field", reflect.Value{})
+				continue
+			}
+			m.match(joinPath(path, name), tryExport(vf), pf)
+		}
+	case reflect.Map:
+		keys := p.MapKeys()
+		sort.Slice(keys, func(i, j int) bool { return fmt.Sprint(keys[i]) < fmt.Sprint(keys[j]) })
+		for _, key := range keys {
+			name := fmt.Sprint(tryExport(key).Interface())
+			keyPath := joinPath(path, name)
+			var vf reflect.Value
+			switch v.Kind() {
+			case reflect.Map:
+				keyPath = path + "[" + strconv.Quote(name) + "]"
+				for _, vk := range v.MapKeys() {
+					if mapKeyMatches(vk, name) {
+						vf = v.MapIndex(vk)
+						break
+					}
+				}
+			case reflect.Struct:
+				vf = v.FieldByName(name)
+			default:
+				m.fail(path, "want a map or struct", v)
+				return
+			}
+			if !vf.IsValid() {
+				m.fail(keyPath, "missing", reflect.Value{})
+				continue
+			}
+			m.match(keyPath, tryExport(vf), p.MapIndex(key))
+		}
+	case reflect.Slice, reflect.Array:
+		if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+			m.fail(path, "want a list", v)
+			return
+		}
+		if v.Len() != p.Len() {
+			m.fail(path, fmt.Sprintf("want %d elements, got %d", p.Len(), v.Len()), reflect.Value{})
+			return
+		}
+		for i := range p.Len() {
+			m.match(path+"["+strconv.Itoa(i)+"]", tryExport(v.Index(i)), p.Index(i))
+		}
+	default:
+		if !scalarsEqual(v, p) {
+			m.fail(path, "want "+renderForReport(p), v)
+		}
+	}
+}
+
+// fail records a mismatch, rendering the actual value if there is one.
+func (m *matcher) fail(path, reason string, got reflect.Value) {
+	if path == "" {
+		path = "value"
+	}
+	line := "   " + path + ": " + reason
+	if got.IsValid() {
+		line += ", got " + renderForReport(got)
+	}
+	m.mismatches = append(m.mismatches, line)
+}
+
+// scalarsEqual compares two non-composite values, converting between numeric
+// types so untyped constants in patterns match sized integers and floats.
+func scalarsEqual(v, p reflect.Value) bool {
+	v, p = tryExport(v), tryExport(p)
+	if !v.CanInterface() || !p.CanInterface() {
+		return false
+	}
+	if isNumberKind(v.Kind()) && isNumberKind(p.Kind()) && p.CanConvert(v.Type()) {
+		converted := p.Convert(v.Type())
+		if converted.CanConvert(p.Type()) && converted.Convert(p.Type()).Interface() == p.Interface() {
+			return converted.Interface() == v.Interface()
+		}
+		return false
+	}
+	if v.Type() != p.Type() {
+		return false
+	}
+	return reflect.DeepEqual(v.Interface(), p.Interface())
+}
+
+// isNumberKind reports whether k is an integer or floating-point kind.
+func isNumberKind(k reflect.Kind) bool {
+	switch k {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
+
+// derefAll follows pointers and interfaces until a nil or a concrete value.
+func derefAll(v reflect.Value) reflect.Value {
+	for v.IsValid() && (v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface) {
+		if v.IsNil() {
+			return reflect.Value{}
+		}
+		v = v.Elem()
+	}
+	return v
+}
+
+// renderForReport dumps a value on a single logical line for a Match report,
+// indenting continuation lines of block renderings.
+func renderForReport(v reflect.Value) string {
+	v = tryExport(v)
+	if !v.CanInterface() {
+		return "<" + v.Type().String() + ">"
+	}
+	d := NewDumper(matchReportConfig)
+	sb := &strings.Builder{}
+	d.renderAllValues(sb, v.Interface())
+	return strings.ReplaceAll(strings.TrimRight(sb.String(), "\n"), "\n", "\n      ")
+}
+
+// joinPath appends a field name to a path.
+func joinPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
@@ -0,0 +1,58 @@
+package govar
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMatch(t *testing.T) {
+	type address struct{ City, Zip string }
+	type user struct {
+		ID      int64
+		Name    string
+		Address *address
+		Tags    []string
+		Meta    map[string]any
+	}
+	u := user{ID: 7, Name: "Ann", Address: &address{City: "Oslo", Zip: "0150"}, Tags: []string{"a", "b"}, Meta: map[string]any{"tier": 2}}
+
+	matching := []any{
+		user{Name: "Ann"},
+		struct{ ID int }{ID: 7},
+		map[string]any{"Address": map[string]any{"City": "Oslo"}, "Tags": []any{Any, "b"}},
+		map[string]any{"Meta": map[string]any{"tier": 2}, "Name": NonZero},
+		&user{Address: &address{Zip: "0150"}},
+	}
+	for i, pattern := range matching {
+		if ok, report := Match(u, pattern); !ok {
+			t.Errorf("pattern %d: expected a match, got:\n%s", i, report)
+		}
+	}
+
+	ok, report := Match(u, map[string]any{
+		"Name":    "Bob",
+		"Address": map[string]any{"City": "Bergen"},
+		"Tags":    []string{"a"},
+		"Meta":    map[string]any{"owner": Any},
+		"Email":   Any,
+	})
+	if ok {
+		t.Fatal("expected a mismatch")
+	}
+	for _, want := range []string{
+		"5 mismatches:",
+		`Name: want string => "Bob", got string => "Ann"`,
+		`Address.City: want string => "Bergen", got string => "Oslo"`,
+		"Tags: want 1 elements, got 2",
+		`Meta["owner"]: missing`,
+		"Email: missing",
+	} {
+		if !strings.Contains(report, want) {
+			t.Errorf("expected %q in report, got:\n%s", want, report)
+		}
+	}
+
+	if ok, report := Match(user{}, map[string]any{"Name": NonZero, "Address": nil}); ok || !strings.Contains(report, "Name: want a non-zero value") || strings.Contains(report, "Address") {
+		t.Errorf("unexpected result for NonZero and nil patterns:\n%s", report)
+	}
+}
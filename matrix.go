@@ -0,0 +1,87 @@
+// Package govar provides a powerful and highly configurable pretty-printer for Go
+// data structures. This file renders two-dimensional numeric arrays ([N][M]int,
+// [N][M]float64, …) as a right-aligned grid with a single column index header,
+// which reads far better than one nested block per row.
+package govar
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// isMatrix reports whether v is a non-empty [N][M] array of numbers rendered as
+// a grid. JSON-like output keeps its nested literals, and single-line output
+// (SingleLine or a compact field) its inline arrays.
+func (d *Dumper) isMatrix(v reflect.Value) bool {
+	if !d.config.MatrixGrid || d.config.JSONLikeLayout || d.config.SingleLine || d.forceInline > 0 || v.Kind() != reflect.Array {
+		return false
+	}
+	row := v.Type().Elem()
+	return row.Kind() == reflect.Array && isNumericKind(row.Elem().Kind()) && v.Len() > 0 && row.Len() > 0
+}
+
+// formatMatrix renders the [N][M] numeric array v as a grid: a header line with
+// the column indices, then one line per row starting with the row index. Rows
+// and columns beyond MaxItems are truncated.
+func (d *Dumper) formatMatrix(v reflect.Value, level int) string {
+	sb := &strings.Builder{}
+	rows, cols := min(v.Len(), d.config.MaxItems), min(v.Index(0).Len(), d.config.MaxItems)
+
+	if d.showMeta(MetaListLength) {
+		fmt.Fprint(sb, d.metaHint(d.localizeInt(v.Len())+"×"+d.localizeInt(v.Index(0).Len()), ""))
+	}
+
+	// Format every shown cell first, to know the width of each column.
+	cells := make([][]string, rows)
+	widths := make([]int, cols)
+	for c := range cols {
+		widths[c] = len(strconv.Itoa(c))
+	}
+	for r := range rows {
+		cells[r] = make([]string, cols)
+		for c := range cols {
+			cells[r][c] = d.numberText(v.Index(r).Index(c))
+			widths[c] = max(widths[c], utf8.RuneCountInString(cells[r][c]))
+		}
+	}
+	rowLabelWidth := len(strconv.Itoa(rows - 1))
+	colsTruncated := cols < v.Index(0).Len()
+
+	fmt.Fprintln(sb, d.delim("[", level))
+	header := strings.Repeat(" ", rowLabelWidth)
+	for c := range cols {
+		header += "  " + d.ApplyFormat(d.depthColor(level, ColorDarkTeal), padLeft(strconv.Itoa(c), widths[c]))
+	}
+	if colsTruncated {
+		header += "  " + d.ApplyFormat(ColorSlateGray, "…")
+	}
+	d.renderIndent(sb, level+1, header+"\n")
+
+	for r := range rows {
+		line := d.ApplyFormat(d.depthColor(level, ColorDarkTeal), padRight(strconv.Itoa(r), len(strconv.Itoa(r)), rowLabelWidth))
+		for c := range cols {
+			line += "  " + d.ApplyFormat(ColorSkyBlue, padLeft(cells[r][c], widths[c]))
+		}
+		if colsTruncated {
+			line += "  " + d.ApplyFormat(ColorSlateGray, "…")
+		}
+		d.renderIndent(sb, level+1, line+"\n")
+	}
+	if rows < v.Len() {
+		d.renderIndent(sb, level+1, d.ApplyFormat(ColorSlateGray, "… (truncated)\n"))
+	}
+	d.renderIndent(sb, level, "")
+	fmt.Fprint(sb, d.delim("]", level))
+	return sb.String()
+}
+
+// padLeft right-aligns s in a column of the given width.
+func padLeft(s string, width int) string {
+	if n := utf8.RuneCountInString(s); n < width {
+		return strings.Repeat(" ", width-n) + s
+	}
+	return s
+}
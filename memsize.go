@@ -0,0 +1,120 @@
+// Package govar provides a powerful and highly configurable pretty-printer for Go
+// data structures. This file implements memory attribution: estimating the deep
+// size of values (their own size plus everything they reference) so dumps can
+// annotate each subtree with its share of the total, like a memory profile of
+// an in-memory structure.
+package govar
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// mapEntryOverhead approximates the per-map bookkeeping of the runtime's hash
+// table (header and bucket metadata), which reflection cannot observe.
+const mapEntryOverhead = 48
+
+// deepSize estimates the memory used by v: its own size plus the memory it
+// references. Memory shared within v (e.g. by two pointers) is counted once.
+func deepSize(v reflect.Value) int64 {
+	if !v.IsValid() {
+		return 0
+	}
+	return int64(v.Type().Size()) + heapSize(v, map[canonicalKey]bool{})
+}
+
+// heapSize estimates the memory referenced by v, excluding v's own size. It
+// walks v with an explicit stack, so very deep structures cannot overflow the
+// goroutine stack.
+func heapSize(v reflect.Value, visited map[canonicalKey]bool) int64 {
+	var size int64
+	stack := []reflect.Value{v}
+	for len(stack) > 0 {
+		v := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		switch v.Kind() {
+		case reflect.Ptr:
+			if v.IsNil() || !markVisited(visited, v.Pointer(), v.Type()) {
+				continue
+			}
+			size += int64(v.Type().Elem().Size())
+			stack = append(stack, v.Elem())
+		case reflect.Interface:
+			if v.IsNil() {
+				continue
+			}
+			elem := v.Elem()
+			switch elem.Kind() {
+			case reflect.Ptr, reflect.Map, reflect.Chan, reflect.Func, reflect.UnsafePointer:
+				// Stored directly in the interface word.
+			default:
+				size += int64(elem.Type().Size())
+			}
+			stack = append(stack, elem)
+		case reflect.Struct:
+			for i := range v.NumField() {
+				stack = append(stack, v.Field(i))
+			}
+		case reflect.Array:
+			for i := range v.Len() {
+				stack = append(stack, v.Index(i))
+			}
+		case reflect.Slice:
+			if v.IsNil() || !markVisited(visited, v.Pointer(), v.Type()) {
+				continue
+			}
+			size += int64(v.Cap()) * int64(v.Type().Elem().Size())
+			for i := range v.Len() {
+				stack = append(stack, v.Index(i))
+			}
+		case reflect.String:
+			size += int64(v.Len())
+		case reflect.Map:
+			if v.IsNil() || !markVisited(visited, v.Pointer(), v.Type()) {
+				continue
+			}
+			size += int64(mapEntryOverhead) + int64(v.Len())*int64(v.Type().Key().Size()+v.Type().Elem().Size())
+			iter := v.MapRange()
+			for iter.Next() {
+				stack = append(stack, iter.Key(), iter.Value())
+			}
+		case reflect.Chan:
+			if v.IsNil() || !markVisited(visited, v.Pointer(), v.Type()) {
+				continue
+			}
+			size += int64(v.Cap()) * int64(v.Type().Elem().Size())
+		}
+	}
+	return size
+}
+
+// markVisited records a referenced memory block, reporting false if it was
+// already counted.
+func markVisited(visited map[canonicalKey]bool, addr uintptr, t reflect.Type) bool {
+	key := canonicalKey{addr, t}
+	if visited[key] {
+		return false
+	}
+	visited[key] = true
+	return true
+}
+
+// renderMemoryUsage writes the deep size of a composite value and its share of
+// the top-level value being dumped, e.g. "|312 KiB, 64%|".
+func (d *Dumper) renderMemoryUsage(sb *strings.Builder, v reflect.Value) {
+	if !d.config.ShowMemoryUsage || d.memoryTotal <= 0 {
+		return
+	}
+	switch v.Kind() {
+	case reflect.Struct, reflect.Slice, reflect.Array, reflect.Map:
+	default:
+		return
+	}
+	size := deepSize(v)
+	share := fmt.Sprintf("%d%%", (size*100+d.memoryTotal/2)/d.memoryTotal)
+	if size*100 < d.memoryTotal {
+		share = "<1%"
+	}
+	fmt.Fprint(sb, d.ApplyFormat(ColorGoldenrod, "|"+humanizeBytes(size)+", "+share+"| "))
+}
@@ -0,0 +1,56 @@
+package govar
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDeepSize(t *testing.T) {
+	shared := &struct{ A, B int64 }{}
+	tests := []struct {
+		name  string
+		input any
+		want  int64
+	}{
+		{"int", int64(1), 8},
+		{"string", "hello", 16 + 5},
+		{"byte slice", make([]byte, 10, 32), 24 + 32},
+		{"pointer", shared, 8 + 16},
+		{"shared pointer counted once", [2]*struct{ A, B int64 }{shared, shared}, 16 + 16},
+	}
+	for _, tt := range tests {
+		if got := deepSize(reflect.ValueOf(tt.input)); got != tt.want {
+			t.Errorf("%s: deepSize() = %d, want %d", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestShowMemoryUsage(t *testing.T) {
+	type blob struct {
+		Name string
+		Data []byte
+	}
+	input := struct {
+		Big   blob
+		Small blob
+	}{Big: blob{Data: make([]byte, 3000)}, Small: blob{Data: make([]byte, 10)}}
+
+	cfg := DefaultConfig
+	cfg.UseColors = false
+	cfg.ShowHexdump = false
+	cfg.MaxItems = 1
+	cfg.ShowMemoryUsage = true
+	out := NewDumper(cfg).Sdump(input)
+
+	for _, want := range []string{"|3 KiB, 100%| {", "|3 KiB, 98%| {", "|50 B, 2%| {", "|34 B, 1%| |10| ["} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in output, got:\n%s", want, out)
+		}
+	}
+
+	cfg.ShowMemoryUsage = false
+	if out := NewDumper(cfg).Sdump(input); strings.Contains(out, "%|") {
+		t.Errorf("expected no memory annotations by default, got:\n%s", out)
+	}
+}
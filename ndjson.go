@@ -0,0 +1,62 @@
+// Package govar provides a powerful and highly configurable pretty-printer for Go
+// data structures. This file implements the NDJSON dump log: every dump is
+// appended as one JSON-encoded Record per line, so dumps collected during long
+// test runs can be post-processed with tools like jq.
+package govar
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+)
+
+// NDJSONSink appends dumps to a writer as newline-delimited JSON records.
+// It is safe for concurrent use.
+type NDJSONSink struct {
+	dumper  *Dumper
+	content RecordContent
+	closer  io.Closer
+
+	mu  sync.Mutex
+	enc *json.Encoder
+}
+
+// NewNDJSONSink returns a sink writing records with the selected content
+// (RecordText, RecordTree, or both) to w. Dumps are rendered with cfg.
+func NewNDJSONSink(w io.Writer, cfg DumperConfig, content RecordContent) *NDJSONSink {
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	return &NDJSONSink{dumper: NewDumper(cfg), content: content, enc: enc}
+}
+
+// OpenNDJSONLog opens (or creates) the file at path for appending and returns a
+// sink writing to it. The file is closed by Close.
+func OpenNDJSONLog(path string, cfg DumperConfig, content RecordContent) (*NDJSONSink, error) {
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("govar: open dump log: %w", err)
+	}
+	s := NewNDJSONSink(f, cfg, content)
+	s.closer = f
+	return s, nil
+}
+
+// Dump renders the values and appends them to the log as a single record.
+func (s *NDJSONSink) Dump(vs ...any) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.enc.Encode(s.dumper.Record(s.content, vs...)); err != nil {
+		return fmt.Errorf("govar: write dump log: %w", err)
+	}
+	return nil
+}
+
+// Close closes the underlying file if the sink was created by OpenNDJSONLog.
+func (s *NDJSONSink) Close() error {
+	if s.closer == nil {
+		return nil
+	}
+	return s.closer.Close()
+}
@@ -0,0 +1,60 @@
+package govar
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestNDJSONLog(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "dumps.ndjson")
+	cfg := DefaultConfig
+	cfg.UseColors = false
+
+	for i := 0; i < 2; i++ {
+		sink, err := OpenNDJSONLog(path, cfg, RecordText|RecordTree)
+		if err != nil {
+			t.Fatalf("OpenNDJSONLog error: %v", err)
+		}
+		if err := sink.Dump(map[string]int{"run": i}); err != nil {
+			t.Fatalf("Dump error: %v", err)
+		}
+		if err := sink.Close(); err != nil {
+			t.Fatalf("Close error: %v", err)
+		}
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("open log: %v", err)
+	}
+	defer f.Close()
+
+	var records []Record
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var rec Record
+		if err := json.Unmarshal(scanner.Bytes(), &rec); err != nil {
+			t.Fatalf("line is not a JSON record: %v\n%s", err, scanner.Text())
+		}
+		records = append(records, rec)
+	}
+
+	if len(records) != 2 {
+		t.Fatalf("expected 2 appended records, got %d", len(records))
+	}
+	for i, rec := range records {
+		if rec.Time.IsZero() || rec.Goroutine == 0 {
+			t.Errorf("record %d missing time or goroutine: %+v", i, rec)
+		}
+		if !strings.Contains(rec.Text, `"run"  => `) || len(rec.Tree) != 1 {
+			t.Errorf("record %d missing text or tree: %+v", i, rec)
+		}
+	}
+	if records[1].Tree[0].Children[0].Value != "1" {
+		t.Errorf("second record should hold the second dump, got %+v", records[1].Tree[0].Children[0])
+	}
+}
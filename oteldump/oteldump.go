@@ -0,0 +1,92 @@
+// Package oteldump turns govar dumps into OpenTelemetry span event attributes,
+// so values captured during a traced request show up in the tracing UI next to
+// the span. It has no dependency on the OpenTelemetry SDK; the attributes are
+// plain key/value strings that map directly onto attribute.String:
+//
+//	attrs := oteldump.Attributes(govar.DefaultConfig, 0, req)
+//	kvs := make([]attribute.KeyValue, len(attrs))
+//	for i, a := range attrs {
+//		kvs[i] = attribute.String(a.Key, a.Value)
+//	}
+//	span.AddEvent(oteldump.EventName, trace.WithAttributes(kvs...))
+package oteldump
+
+import (
+	"fmt"
+	"strconv"
+	"unicode/utf8"
+
+	"github.com/janvaclavik/govar"
+)
+
+const (
+	// EventName is the suggested name of span events carrying a dump.
+	EventName = "govar.dump"
+	// DefaultLimit is the maximum size in bytes of the dump attribute when no limit is given.
+	DefaultLimit = 4096
+)
+
+// Attribute keys set by Attributes.
+const (
+	KeyDump      = "govar.dump"           // The rendered dump, without colors or header.
+	KeyTruncated = "govar.dump.truncated" // "true" if the dump was cut to the size limit.
+	KeyFile      = "code.filepath"        // Source file of the dump call site.
+	KeyLine      = "code.lineno"          // Line of the dump call site.
+)
+
+// truncMarker is appended to truncated dumps with the number of dropped bytes.
+const truncMarker = "…(%d more bytes)"
+
+// Attribute is a single string-valued span attribute.
+type Attribute struct {
+	Key   string
+	Value string
+}
+
+// Attributes renders the values with cfg (colors are always disabled) and
+// returns them as span attributes. The rendered dump is truncated to limit
+// bytes; a limit <= 0 selects DefaultLimit.
+func Attributes(cfg govar.DumperConfig, limit int, vs ...any) []Attribute {
+	if limit <= 0 {
+		limit = DefaultLimit
+	}
+	cfg.UseColors = false
+	rec := govar.NewDumper(cfg).Record(govar.RecordText, vs...)
+
+	text, truncated := Truncate(rec.Text, limit)
+	attrs := []Attribute{
+		{Key: KeyDump, Value: text},
+		{Key: KeyTruncated, Value: strconv.FormatBool(truncated)},
+	}
+	if rec.File != "" {
+		attrs = append(attrs,
+			Attribute{Key: KeyFile, Value: rec.File},
+			Attribute{Key: KeyLine, Value: strconv.Itoa(rec.Line)},
+		)
+	}
+	return attrs
+}
+
+// Truncate cuts s to at most limit bytes without splitting a UTF-8 sequence.
+// A cut string ends with a marker stating how many bytes were dropped, and the
+// marker is counted towards the limit whenever it fits. A negative limit is
+// treated as 0.
+func Truncate(s string, limit int) (string, bool) {
+	limit = max(limit, 0)
+	if len(s) <= limit {
+		return s, false
+	}
+	// Reserve room for the largest possible count; the real one never has more digits.
+	cut := limit - len(fmt.Sprintf(truncMarker, len(s)))
+	withMarker := cut >= 0
+	if !withMarker {
+		cut = limit
+	}
+	for cut > 0 && !utf8.RuneStart(s[cut]) {
+		cut--
+	}
+	if !withMarker {
+		return s[:cut], true
+	}
+	return s[:cut] + fmt.Sprintf(truncMarker, len(s)-cut), true
+}
@@ -0,0 +1,61 @@
+package oteldump
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/janvaclavik/govar"
+)
+
+func TestAttributes(t *testing.T) {
+	attrs := Attributes(govar.DefaultConfig, 0, map[string]int{"answer": 42})
+	got := make(map[string]string)
+	for _, a := range attrs {
+		got[a.Key] = a.Value
+	}
+
+	if !strings.Contains(got[KeyDump], `"answer"  => 42`) {
+		t.Errorf("dump attribute missing value, got %q", got[KeyDump])
+	}
+	if strings.Contains(got[KeyDump], "\033[") {
+		t.Errorf("dump attribute should not contain ANSI colors, got %q", got[KeyDump])
+	}
+	if got[KeyTruncated] != "false" {
+		t.Errorf("short dump should not be truncated, got %q", got[KeyTruncated])
+	}
+	if got[KeyLine] == "" {
+		t.Errorf("expected call site attributes, got %+v", attrs)
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		limit     int
+		want      string
+		truncated bool
+	}{
+		{"fits", "hello", 5, "hello", false},
+		{"with marker", strings.Repeat("a", 40), 20, "aa…(38 more bytes)", true},
+		{"limit below marker", "abcdef", 3, "abc", true},
+		{"utf8 boundary", "žžž", 3, "ž", true},
+		{"zero limit", "abc", 0, "", true},
+		{"negative limit", "abc", -1, "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, truncated := Truncate(tt.input, tt.limit)
+			if got != tt.want || truncated != tt.truncated {
+				t.Errorf("Truncate(%q, %d) = %q, %v; want %q, %v", tt.input, tt.limit, got, truncated, tt.want, tt.truncated)
+			}
+			if len(got) > max(tt.limit, 0) && tt.truncated {
+				t.Errorf("Truncate result exceeds limit: %d > %d", len(got), tt.limit)
+			}
+			if !utf8.ValidString(got) {
+				t.Errorf("Truncate produced invalid UTF-8: %q", got)
+			}
+		})
+	}
+}
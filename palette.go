@@ -0,0 +1,92 @@
+// Package govar provides a powerful and highly configurable pretty-printer for Go
+// data structures. This file selects the terminal color palette: the default
+// colors are tuned for dark backgrounds, so terminals reporting a light
+// background switch to LightPalette, where pale colors stay readable.
+package govar
+
+import (
+	"os"
+	"strconv"
+	"strings"
+)
+
+// PaletteMode selects the ANSI colors used by DumperConfig.Palette.
+type PaletteMode int
+
+const (
+	PaletteAuto  PaletteMode = iota // LightPalette on terminals reporting a light background, the default colors otherwise.
+	PaletteDark                     // The default colors, tuned for dark backgrounds.
+	PaletteLight                    // LightPalette, tuned for light backgrounds.
+)
+
+// LightPalette maps the default color codes to darker ones that are readable on
+// light backgrounds.
+var LightPalette = map[string]string{
+	ColorPaleGray:  "\033[38;5;242m",
+	ColorSlateGray: "\033[38;5;243m",
+
+	ColorLime:         "\033[38;5;28m",
+	ColorSkyBlue:      "\033[38;5;31m",
+	ColorMutedBlue:    "\033[38;5;25m",
+	ColorLightTeal:    "\033[38;5;30m",
+	ColorGoBlue:       "\033[38;5;32m",
+	ColorDarkTeal:     "\033[38;5;23m",
+	ColorDarkGoBlue:   "\033[38;5;19m",
+	ColorSeafoamGreen: "\033[38;5;29m",
+	ColorGreen:        "\033[38;5;28m",
+	ColorGoldenrod:    "\033[38;5;136m",
+	ColorCoralRed:     "\033[38;5;160m",
+
+	ColorPink: "\033[38;5;163m",
+}
+
+// BackgroundEnvVar is the environment variable that declares the terminal
+// background ("light" or "dark") for terminals DetectPalette cannot query.
+const BackgroundEnvVar = "GOVAR_BACKGROUND"
+
+// DetectPalette returns LightPalette if the terminal reports a light background
+// and nil (the default colors) otherwise. It honors GOVAR_BACKGROUND, then
+// COLORFGBG ("15;0" is light text on a dark background), as set by rxvt,
+// Konsole and others, and iTerm2 profile names containing "light". WezTerm
+// exposes no background hint, so its users set GOVAR_BACKGROUND instead, e.g.
+// with set_environment_variables in their color scheme configuration.
+func DetectPalette() map[string]string {
+	if isLightBackground() {
+		return LightPalette
+	}
+	return nil
+}
+
+// isLightBackground implements the detection of DetectPalette.
+func isLightBackground() bool {
+	switch strings.ToLower(os.Getenv(BackgroundEnvVar)) {
+	case "light":
+		return true
+	case "dark":
+		return false
+	}
+	if fgbg := os.Getenv("COLORFGBG"); fgbg != "" {
+		// The background is the last field; colors 7 and 9-15 are light.
+		fields := strings.Split(fgbg, ";")
+		if bg, err := strconv.Atoi(fields[len(fields)-1]); err == nil {
+			return bg == 7 || (bg >= 9 && bg <= 15)
+		}
+	}
+	return strings.Contains(strings.ToLower(os.Getenv("ITERM_PROFILE")), "light")
+}
+
+// textFormatter returns the Formatter for text output: ANSI colors in the
+// configured palette if useColors is set, plain text otherwise.
+func (d *Dumper) textFormatter(useColors bool) Formatter {
+	if !useColors {
+		return &PlainFormatter{}
+	}
+	switch d.config.Palette {
+	case PaletteLight:
+		return &ANSIcolorFormatter{Palette: LightPalette}
+	case PaletteDark:
+		return &ANSIcolorFormatter{}
+	default:
+		return &ANSIcolorFormatter{Palette: DetectPalette()}
+	}
+}
@@ -0,0 +1,51 @@
+package govar
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDetectPalette(t *testing.T) {
+	tests := []struct {
+		name          string
+		colorFgBg     string
+		itermProfile  string
+		background    string
+		wantLightBack bool
+	}{
+		{"unknown", "", "", "", false},
+		{"dark background", "15;0", "", "", false},
+		{"light background", "0;15", "", "", true},
+		{"light background with default", "0;default;7", "", "", true},
+		{"iTerm light profile", "", "Solarized Light", "", true},
+		{"iTerm dark profile", "", "Default", "", false},
+		{"declared light background", "", "", "light", true},
+		{"declared dark background", "0;15", "", "Dark", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("COLORFGBG", tt.colorFgBg)
+			t.Setenv("ITERM_PROFILE", tt.itermProfile)
+			t.Setenv(BackgroundEnvVar, tt.background)
+			if got := DetectPalette() != nil; got != tt.wantLightBack {
+				t.Errorf("DetectPalette() light = %v, want %v", got, tt.wantLightBack)
+			}
+		})
+	}
+}
+
+func TestDumpPalette(t *testing.T) {
+	t.Setenv("COLORFGBG", "0;15")
+	t.Setenv("ITERM_PROFILE", "")
+	t.Setenv(BackgroundEnvVar, "")
+	cfg := DefaultConfig
+	light := LightPalette[ColorSkyBlue]
+
+	if out := NewDumper(cfg).Sdump(42); !strings.Contains(out, light+"42") {
+		t.Errorf("PaletteAuto should pick the light palette, got:\n%q", out)
+	}
+	cfg.Palette = PaletteDark
+	if out := NewDumper(cfg).Sdump(42); !strings.Contains(out, ColorSkyBlue+"42") {
+		t.Errorf("PaletteDark should keep the default colors, got:\n%q", out)
+	}
+}
@@ -0,0 +1,175 @@
+// Package govar provides a powerful and highly configurable pretty-printer for Go
+// data structures. This file implements path queries, which navigate arbitrary
+// values via reflection, e.g. `Users[2].Address.City` or `Config["timeout"]`.
+package govar
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// ErrPathNotFound is wrapped by the errors Get returns when a path does not
+// resolve to a value.
+var ErrPathNotFound = errors.New("govar: path not found")
+
+// pathStep is a single element of a parsed path: a field name or a bracketed
+// index or map key.
+type pathStep struct {
+	name      string
+	isBracket bool
+}
+
+// Get returns the value found at path within v. A path is a sequence of field
+// names separated by dots and of bracketed slice/array indexes or map keys,
+// e.g. `Users[2].Address.City`, `Tags[0]`, or `Labels["app"]` (quotes are
+// optional for simple keys). Map keys may also follow a dot. Pointers and
+// interfaces are followed transparently, and unexported fields are reachable.
+// An empty path returns v itself.
+func Get(v any, path string) (any, error) {
+	steps, err := parsePath(path)
+	if err != nil {
+		return nil, err
+	}
+	cur := reflect.ValueOf(v)
+	walked := ""
+	for _, step := range steps {
+		for cur.Kind() == reflect.Ptr || cur.Kind() == reflect.Interface {
+			if cur.IsNil() {
+				return nil, fmt.Errorf("%w: %s is nil", ErrPathNotFound, describePath(walked))
+			}
+			cur = cur.Elem()
+		}
+		next, err := followStep(cur, step)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s: %v", ErrPathNotFound, describePath(walked), err)
+		}
+		cur = next
+		if step.isBracket {
+			walked += "[" + step.name + "]"
+		} else {
+			walked += "." + step.name
+		}
+	}
+	if !cur.IsValid() {
+		return nil, nil
+	}
+	cur = tryExport(cur)
+	if !cur.CanInterface() {
+		return nil, fmt.Errorf("govar: value at %s cannot be accessed", describePath(walked))
+	}
+	return cur.Interface(), nil
+}
+
+// followStep resolves a single path step against a dereferenced value.
+func followStep(v reflect.Value, step pathStep) (reflect.Value, error) {
+	switch v.Kind() {
+	case reflect.Struct:
+		if step.isBracket {
+			return reflect.Value{}, fmt.Errorf("cannot index struct %s", v.Type())
+		}
+		f := v.FieldByName(step.name)
+		if !f.IsValid() {
+			return reflect.Value{}, fmt.Errorf("%s has no field %s", v.Type(), step.name)
+		}
+		return f, nil
+	case reflect.Slice, reflect.Array, reflect.String:
+		i, err := strconv.Atoi(step.name)
+		if err != nil || !step.isBracket {
+			return reflect.Value{}, fmt.Errorf("%s needs an index, not %q", v.Type(), step.name)
+		}
+		if i < 0 || i >= v.Len() {
+			return reflect.Value{}, fmt.Errorf("index %d out of range (length %d)", i, v.Len())
+		}
+		return v.Index(i), nil
+	case reflect.Map:
+		for _, key := range v.MapKeys() {
+			if mapKeyMatches(key, step.name) {
+				return v.MapIndex(key), nil
+			}
+		}
+		return reflect.Value{}, fmt.Errorf("map has no key %q", step.name)
+	default:
+		return reflect.Value{}, fmt.Errorf("cannot select %q from %s", step.name, v.Type())
+	}
+}
+
+// mapKeyMatches reports whether a map key is written as s in a path.
+func mapKeyMatches(key reflect.Value, s string) bool {
+	key = tryExport(key)
+	if key.Kind() == reflect.String {
+		return key.String() == s
+	}
+	return key.CanInterface() && fmt.Sprint(key.Interface()) == s
+}
+
+// parsePath splits a path into steps.
+func parsePath(path string) ([]pathStep, error) {
+	var steps []pathStep
+	rest := strings.TrimPrefix(path, ".")
+	for rest != "" {
+		if rest[0] == '[' {
+			end := closingBracket(rest)
+			if end < 0 {
+				return nil, fmt.Errorf("govar: invalid path %q: unclosed [", path)
+			}
+			key := rest[1:end]
+			if strings.HasPrefix(key, `"`) {
+				unquoted, err := strconv.Unquote(key)
+				if err != nil {
+					return nil, fmt.Errorf("govar: invalid path %q: bad quoted key %s", path, key)
+				}
+				key = unquoted
+			}
+			steps = append(steps, pathStep{name: key, isBracket: true})
+			rest = rest[end+1:]
+		} else {
+			end := strings.IndexAny(rest, ".[")
+			if end < 0 {
+				end = len(rest)
+			}
+			if end == 0 {
+				return nil, fmt.Errorf("govar: invalid path %q: empty field name", path)
+			}
+			steps = append(steps, pathStep{name: rest[:end]})
+			rest = rest[end:]
+		}
+		if strings.HasPrefix(rest, ".") {
+			rest = rest[1:]
+			if rest == "" || rest[0] == '.' || rest[0] == '[' {
+				return nil, fmt.Errorf("govar: invalid path %q: empty field name", path)
+			}
+		}
+	}
+	return steps, nil
+}
+
+// closingBracket returns the index of the bracket closing the one s starts
+// with, skipping over a quoted key, or -1 if there is none.
+func closingBracket(s string) int {
+	if strings.HasPrefix(s, `["`) {
+		for i := 2; i < len(s); i++ {
+			switch s[i] {
+			case '\\':
+				i++
+			case '"':
+				if i+1 < len(s) && s[i+1] == ']' {
+					return i + 1
+				}
+				return -1
+			}
+		}
+		return -1
+	}
+	return strings.IndexByte(s, ']')
+}
+
+// describePath renders the walked part of a path for error messages.
+func describePath(walked string) string {
+	if walked == "" {
+		return "root"
+	}
+	return strings.TrimPrefix(walked, ".")
+}
@@ -0,0 +1,52 @@
+package govar
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestGet(t *testing.T) {
+	type address struct{ City string }
+	type user struct {
+		Name    string
+		Address *address
+		Labels  map[string]int
+		secret  string
+	}
+	data := struct {
+		Users []user
+		ByID  map[int]*user
+	}{
+		Users: []user{
+			{Name: "Ann", Address: &address{City: "Oslo"}, Labels: map[string]int{"app.tier": 1}, secret: "s3"},
+			{Name: "Bob"},
+		},
+	}
+	data.ByID = map[int]*user{7: &data.Users[0]}
+
+	tests := []struct {
+		path string
+		want any
+	}{
+		{"Users[0].Address.City", "Oslo"},
+		{".Users[1].Name", "Bob"},
+		{`Users[0].Labels["app.tier"]`, 1},
+		{"ByID[7].Name", "Ann"},
+		{"Users[0].secret", "s3"},
+		{"Users[0].Name[0]", uint8('A')},
+	}
+	for _, tt := range tests {
+		got, err := Get(data, tt.path)
+		if err != nil || got != tt.want {
+			t.Errorf("Get(%q) = %v, %v; want %v", tt.path, got, err, tt.want)
+		}
+	}
+
+	for _, path := range []string{"Users[5]", "Users[1].Address.City", "Nope", "Users[0].Labels[missing]", "Users[0]."} {
+		if _, err := Get(data, path); err == nil {
+			t.Errorf("Get(%q): expected an error", path)
+		} else if path != "Users[0]." && !errors.Is(err, ErrPathNotFound) {
+			t.Errorf("Get(%q): expected ErrPathNotFound, got %v", path, err)
+		}
+	}
+}
@@ -0,0 +1,55 @@
+//go:build !(js && wasm)
+
+// Package govar provides a powerful and highly configurable pretty-printer for Go
+// data structures. This file contains the operating-system dependent pieces of
+// the dumper (standard streams and working directory handling) for regular targets.
+// See platform_js.go for the browser (js/wasm) counterpart.
+package govar
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+)
+
+// stdoutWriter returns the destination used by Dump.
+func stdoutWriter() io.Writer {
+	return os.Stdout
+}
+
+// stderrWriter returns the destination used by Edump.
+func stderrWriter() io.Writer {
+	return os.Stderr
+}
+
+// isTerminal reports whether w is a file attached to a terminal.
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	return ok && ioctlTerminalWidth(f.Fd()) > 0
+}
+
+// relativeSourcePath makes a source file path relative to the current working
+// directory when possible, keeping the dump header short.
+func relativeSourcePath(file string) string {
+	wd, err := os.Getwd()
+	if err != nil {
+		return file
+	}
+	if rel, err := filepath.Rel(wd, file); err == nil {
+		return rel
+	}
+	return file
+}
+
+// terminalWidth returns the width of the terminal attached to stdout (or
+// stderr), falling back to the COLUMNS environment variable, or 0 if unknown.
+func terminalWidth() int {
+	for _, f := range []*os.File{os.Stdout, os.Stderr} {
+		if width := ioctlTerminalWidth(f.Fd()); width > 0 {
+			return width
+		}
+	}
+	width, _ := strconv.Atoi(os.Getenv("COLUMNS"))
+	return max(width, 0)
+}
@@ -0,0 +1,50 @@
+//go:build js && wasm
+
+// Package govar provides a powerful and highly configurable pretty-printer for Go
+// data structures. This file contains the browser (js/wasm) implementations of the
+// operating-system dependent pieces of the dumper. Standard output and error are routed
+// to the browser console and source paths are shown without a working directory.
+package govar
+
+import (
+	"io"
+	"path"
+	"syscall/js"
+)
+
+// consoleWriter writes each chunk of output to the browser console using the
+// named console method ("log" or "error").
+type consoleWriter string
+
+func (c consoleWriter) Write(p []byte) (int, error) {
+	js.Global().Get("console").Call(string(c), string(p))
+	return len(p), nil
+}
+
+// stdoutWriter returns the destination used by Dump. In the browser os.Stdout is
+// emulated by a line-buffered shim that mangles multi-line dumps, so the output
+// goes straight to console.log instead.
+func stdoutWriter() io.Writer {
+	return consoleWriter("log")
+}
+
+// stderrWriter returns the destination used by Edump: console.error.
+func stderrWriter() io.Writer {
+	return consoleWriter("error")
+}
+
+// isTerminal always reports false: the browser console is not a terminal.
+func isTerminal(w io.Writer) bool {
+	return false
+}
+
+// relativeSourcePath shortens a source file path for the dump header. There is
+// no meaningful working directory in the browser, so only the file name is kept.
+func relativeSourcePath(file string) string {
+	return path.Base(file)
+}
+
+// terminalWidth returns 0, as the browser console has no fixed width.
+func terminalWidth() int {
+	return 0
+}
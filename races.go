@@ -0,0 +1,79 @@
+// Package govar provides a powerful and highly configurable pretty-printer for Go
+// data structures. This file implements the RecoverRaces option, which keeps a
+// dump of values modified by other goroutines from crashing the program: the
+// runtime panics that such modifications cause while a collection is read (an
+// index or slice bounds out of range after a slice shrank, a nil dereference)
+// are recovered, and the collection is rendered as a marker instead. Other
+// panics, e.g. of String methods, are re-raised.
+//
+// Go's runtime aborts with a fatal error, which cannot be recovered, when it
+// detects a map being read and written at the same time, so maps shared with
+// writers must still be locked around the dump.
+package govar
+
+import (
+	"reflect"
+	"runtime"
+	"strings"
+)
+
+// concurrentlyModified is the marker rendered for collections whose reading
+// panicked under RecoverRaces.
+const concurrentlyModified = "<concurrently modified>"
+
+// formatCollection formats the slice, array, or map v. With RecoverRaces, a
+// race symptom while formatting it yields the concurrently modified marker instead;
+// nested collections are guarded on their own, so the marker replaces only the
+// innermost one affected.
+func (d *Dumper) formatCollection(v reflect.Value, level int) (formatted string) {
+	format := d.formatArrayOrSlice
+	if v.Kind() == reflect.Map {
+		format = d.formatMap
+	}
+	if !d.config.RecoverRaces {
+		return format(v, level)
+	}
+	path := d.valuePath
+	defer func() {
+		if p := recover(); p != nil {
+			if !isRaceSymptom(p) {
+				panic(p)
+			}
+			d.valuePath = path
+			formatted = d.ApplyFormat(ColorCoralRed, concurrentlyModified)
+		}
+	}()
+	return format(v, level)
+}
+
+// guardAnalysis runs the reference analysis. With RecoverRaces, a race symptom
+// during it discards the partial results, so the values are rendered without
+// reference IDs instead of not at all.
+func (d *Dumper) guardAnalysis(analyze func()) {
+	if !d.config.RecoverRaces {
+		analyze()
+		return
+	}
+	defer func() {
+		if p := recover(); p != nil {
+			if !isRaceSymptom(p) {
+				panic(p)
+			}
+			d.resetState()
+		}
+	}()
+	analyze()
+}
+
+// isRaceSymptom reports whether the recovered panic value p is a runtime error
+// that reading a collection modified by another goroutine can cause.
+func isRaceSymptom(p any) bool {
+	err, ok := p.(runtime.Error)
+	if !ok {
+		return false
+	}
+	msg := err.Error()
+	return strings.Contains(msg, "index out of range") ||
+		strings.Contains(msg, "slice bounds out of range") ||
+		strings.Contains(msg, "nil pointer dereference")
+}
@@ -0,0 +1,49 @@
+// Package govar provides a powerful and highly configurable pretty-printer for Go
+// data structures. This file defines Record, a self-describing snapshot of a
+// single dump (call site, time, rendered text, and structured render tree) that
+// sinks can serialize and ship elsewhere.
+package govar
+
+import (
+	"strings"
+	"time"
+)
+
+// RecordContent selects which representations of the dumped values a Record carries.
+type RecordContent uint
+
+const (
+	RecordText RecordContent = 1 << iota // The rendered text, as Sdump would produce it (without header).
+	RecordTree                           // The structured render tree, as returned by Tree.
+)
+
+// Record is a serializable snapshot of a single dump.
+type Record struct {
+	Time      time.Time `json:"time"`
+	File      string    `json:"file,omitempty"`      // Source file of the dump call site.
+	Line      int       `json:"line,omitempty"`      // Line of the dump call site.
+	Func      string    `json:"func,omitempty"`      // The govar function that was called (e.g. "govar.Dump").
+	Goroutine uint64    `json:"goroutine,omitempty"` // ID of the dumping goroutine (0 if unknown).
+	Text      string    `json:"text,omitempty"`
+	Tree      []*Node   `json:"tree,omitempty"`
+}
+
+// Record renders the given values into a Record carrying the requested content.
+// The call site is resolved the same way as for the dump header.
+func (d *Dumper) Record(content RecordContent, vs ...any) Record {
+	file, line, funcName := findCallerInStack()
+	rec := Record{Time: time.Now(), Line: line, Func: funcName, Goroutine: goroutineID()}
+	if file != "" {
+		rec.File = relativeSourcePath(file)
+	}
+	if content&RecordText != 0 {
+		d.Formatter = d.textFormatter(d.config.UseColors)
+		sb := &strings.Builder{}
+		d.renderAllValues(sb, vs...)
+		rec.Text = sb.String()
+	}
+	if content&RecordTree != 0 {
+		rec.Tree = d.Tree(vs...)
+	}
+	return rec
+}
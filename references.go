@@ -25,12 +25,14 @@ type definitionPoint struct {
 	isPointerRef     bool
 	indirectionLevel int
 	level            int
+	order            int // Position in the pre-scan, used to break ties between equal candidates.
 	valueType        reflect.Type
 }
 
 // RefStats collects statistics about references to a value during the analysis pass.
 type RefStats struct {
 	pointerReferencesCount, definitionLevel, minPointerRefLevel, totalReferencesCount int
+	scanOrder                                                                         int // Order of first encounter during the pre-scan.
 	valueKind                                                                         reflect.Kind
 	isPrimitive                                                                       bool
 	value                                                                             interface{}
@@ -38,27 +40,32 @@ type RefStats struct {
 
 // queueItem is used for the Breadth-First Search (BFS) traversal of the value graph.
 type queueItem struct {
-	v     reflect.Value
-	level int
+	v       reflect.Value
+	level   int
+	counted bool // Whether the value contributes to the reference statistics.
 }
 
 // addChildrenToQueue adds all child elements of a composite type (struct, slice, map) to the BFS queue.
-func (d *Dumper) addChildrenToQueue(queue []queueItem, v reflect.Value, level int) []queueItem {
+func (d *Dumper) addChildrenToQueue(queue []queueItem, v reflect.Value, level int, counted bool) []queueItem {
 	v = deref(v)
+	if d.heavyTypeSummary(v) != "" {
+		return queue // Summarized values are not traversed.
+	}
 	switch v.Kind() {
 	case reflect.Struct:
-		for i := 0; i < v.NumField(); i++ {
-			queue = append(queue, queueItem{v.Field(i), level + 1})
+		fields, _ := d.shownFields(v.Type()) // Hidden fields must not define references.
+		for _, i := range fields {
+			queue = append(queue, queueItem{v.Field(i), level + 1, counted})
 		}
 	case reflect.Slice, reflect.Array:
 		for i := 0; i < v.Len(); i++ {
-			queue = append(queue, queueItem{v.Index(i), level + 1})
+			queue = append(queue, queueItem{v.Index(i), level + 1, counted})
 		}
 	case reflect.Map:
-		keys := sortMapKeys(v) // Use govar's stable map key sorting
+		keys := d.sortedMapKeys(v) // Scan in the same order the map is rendered
 		for _, key := range keys {
-			queue = append(queue, queueItem{key, level + 1})
-			queue = append(queue, queueItem{v.MapIndex(key), level + 1})
+			queue = append(queue, queueItem{key, level + 1, counted})
+			queue = append(queue, queueItem{v.MapIndex(key), level + 1, counted})
 		}
 	}
 	return queue
@@ -70,16 +77,14 @@ func (d *Dumper) assignReferenceIDs() {
 	mergedStats := d.getMergedStats()
 	idCounter := 1
 
-	// Sort roots to ensure deterministic ID assignment across runs.
+	// Sort roots by the order they were first encountered during the pre-scan, so
+	// ID assignment is deterministic and does not depend on memory layout.
 	sortedRoots := make([]canonicalKey, 0, len(mergedStats))
 	for key := range mergedStats {
 		sortedRoots = append(sortedRoots, key)
 	}
 	sort.Slice(sortedRoots, func(i, j int) bool {
-		if sortedRoots[i].addr != sortedRoots[j].addr {
-			return sortedRoots[i].addr < sortedRoots[j].addr
-		}
-		return sortedRoots[i].typ.String() < sortedRoots[j].typ.String()
+		return mergedStats[sortedRoots[i]].scanOrder < mergedStats[sortedRoots[j]].scanOrder
 	})
 
 	for _, rootKey := range sortedRoots {
@@ -98,100 +103,82 @@ func (d *Dumper) assignReferenceIDs() {
 	}
 }
 
-// determineDefinitionPoints is the final analysis pass. It traverses the graph again
-// to find the "best" place to print the ID for each value that has one.
-// The "best" place is determined by a set of priority rules.
-func (d *Dumper) determineDefinitionPoints(val reflect.Value) {
-	queue := []queueItem{{val, 0}}
-	traversed := make(map[canonicalKey]bool)
-	for len(queue) > 0 {
-		item := queue[0]
-		queue = queue[1:]
-		v := item.v
-		if !v.IsValid() {
-			continue
-		}
-
-		rawKey, keyOK := d.getRawKey(v)
-		if !keyOK {
-			continue
-		}
+// chooseDefinitionPoints is the final analysis pass. For every value that was
+// assigned an ID, it picks the "best" place to print the ID among the candidates
+// collected by preScanBFS for all members of the value's unified set.
+func (d *Dumper) chooseDefinitionPoints() {
+	for rawKey, candidate := range d.candidatePoints {
 		rootKey := d.findRoot(rawKey)
 		// Only consider values that were assigned an ID.
-		if _, hasID := d.referenceIDs[rootKey]; hasID {
-			incumbent, exists := d.definitionPoints[rootKey]
-			isBetter := false
-
-			currentIsPointerRef := isPointerRef(v)
-
-			if !exists {
-				isBetter = true
-			} else {
-				// Priority Rules for choosing the definition point:
-				// 1. A non-pointer value beats a pointer value.
-				if incumbent.isPointerRef && !currentIsPointerRef {
-					isBetter = true
-				} else if !incumbent.isPointerRef && currentIsPointerRef {
-					isBetter = false
-				} else {
-					// Kinds are the same (both pointers or both non-pointers).
-					if currentIsPointerRef { // Both are pointers
-						// 2. Lower pointer indirection level wins (e.g., *T beats **T).
-						currentIndirection := getIndirectionLevel(v)
-						if currentIndirection < incumbent.indirectionLevel {
-							isBetter = true
-						} else if currentIndirection == incumbent.indirectionLevel {
-							// 3. Lower nesting depth wins.
-							if item.level < incumbent.level {
-								isBetter = true
-							}
-						}
-					} else { // Both are non-pointers
-						// 3. Lower nesting depth wins.
-						if item.level < incumbent.level {
-							isBetter = true
-						}
-					}
-				}
-			}
-
-			// If the current location is better, update the definition point.
-			if isBetter {
-				if instKey, instKeyOK := d.getInstanceKey(v); instKeyOK {
-					d.definitionPoints[rootKey] = definitionPoint{
-						instanceKey:      instKey,
-						isPointerRef:     currentIsPointerRef,
-						indirectionLevel: getIndirectionLevel(v),
-						level:            item.level,
-						valueType:        deref(v).Type(),
-					}
-				}
-			}
-		}
-
-		// Continue traversal.
-		traversalTarget := deref(v)
-		if !traversalTarget.IsValid() || !isCompositeOrInterface(traversalTarget.Kind()) {
+		if _, hasID := d.referenceIDs[rootKey]; !hasID {
 			continue
 		}
-		traversalKey, ok := d.getRawKey(traversalTarget)
-		if !ok || traversed[traversalKey] {
-			continue
+		if incumbent, exists := d.definitionPoints[rootKey]; !exists || candidate.betterThan(incumbent) {
+			d.definitionPoints[rootKey] = candidate
 		}
-		traversed[traversalKey] = true
-		queue = d.addChildrenToQueue(queue, v, item.level)
+	}
+}
+
+// betterThan reports whether p is a better place than other to print an ID.
+// Candidates that rank equally are ordered by when they were encountered.
+func (p definitionPoint) betterThan(other definitionPoint) bool {
+	// Priority Rules for choosing the definition point:
+	// 1. A non-pointer value beats a pointer value.
+	if p.isPointerRef != other.isPointerRef {
+		return !p.isPointerRef
+	}
+	// 2. Lower pointer indirection level wins (e.g., *T beats **T).
+	if p.isPointerRef && p.indirectionLevel != other.indirectionLevel {
+		return p.indirectionLevel < other.indirectionLevel
+	}
+	// 3. Lower nesting depth wins.
+	if p.level != other.level {
+		return p.level < other.level
+	}
+	// 4. The first location encountered wins.
+	return p.order < other.order
+}
+
+// recordCandidate remembers the location of v as a possible definition point for
+// the value it refers to, keeping only the best location seen for each raw key.
+func (d *Dumper) recordCandidate(v reflect.Value, rawKey canonicalKey, level, order int) {
+	instKey, ok := d.getInstanceKey(v)
+	if !ok {
+		return
+	}
+	candidate := definitionPoint{
+		instanceKey:      instKey,
+		isPointerRef:     isPointerRef(v),
+		indirectionLevel: getIndirectionLevel(v),
+		level:            level,
+		order:            order,
+		valueType:        deref(v).Type(),
+	}
+	if incumbent, exists := d.candidatePoints[rawKey]; !exists || candidate.betterThan(incumbent) {
+		d.candidatePoints[rawKey] = candidate
 	}
 }
 
 // findRoot is part of the union-find algorithm. It finds the root representative
 // for a given key, applying path compression for efficiency.
 func (d *Dumper) findRoot(k canonicalKey) canonicalKey {
-	if parent, ok := d.canonicalRoots[k]; !ok || parent == k {
-		d.canonicalRoots[k] = k
-		return k
+	root := k
+	for {
+		parent, ok := d.canonicalRoots[root]
+		if !ok {
+			d.canonicalRoots[root] = root
+		}
+		if !ok || parent == root {
+			break
+		}
+		root = parent
+	}
+	// Path compression: point every key on the way directly at the root.
+	for k != root {
+		next := d.canonicalRoots[k]
+		d.canonicalRoots[k] = root
+		k = next
 	}
-	root := d.findRoot(d.canonicalRoots[k])
-	d.canonicalRoots[k] = root
 	return root
 }
 
@@ -246,7 +233,7 @@ func (d *Dumper) getMergedStats() map[canonicalKey]*RefStats {
 	mergedStats := make(map[canonicalKey]*RefStats)
 	for root, members := range rootToMembers {
 		// Start with the stats of the root itself.
-		newStats := &RefStats{}
+		newStats := &RefStats{scanOrder: -1}
 		if rootStat, ok := d.referenceStats[root]; ok {
 			newStats.value = rootStat.value
 			newStats.valueKind = rootStat.valueKind
@@ -258,6 +245,9 @@ func (d *Dumper) getMergedStats() map[canonicalKey]*RefStats {
 			if memberStats, ok := d.referenceStats[memberKey]; ok {
 				newStats.totalReferencesCount += memberStats.totalReferencesCount
 				newStats.pointerReferencesCount += memberStats.pointerReferencesCount
+				if newStats.scanOrder == -1 || memberStats.scanOrder < newStats.scanOrder {
+					newStats.scanOrder = memberStats.scanOrder
+				}
 			}
 		}
 		mergedStats[root] = newStats
@@ -270,7 +260,7 @@ func (d *Dumper) getOrCreateStats(key canonicalKey, v reflect.Value, level int)
 	if stats, ok := d.referenceStats[key]; ok {
 		return stats
 	}
-	stats := &RefStats{definitionLevel: level, minPointerRefLevel: -1, valueKind: v.Kind(), isPrimitive: isPrimitiveKind(v.Kind())}
+	stats := &RefStats{definitionLevel: level, minPointerRefLevel: -1, scanOrder: len(d.referenceStats), valueKind: v.Kind(), isPrimitive: isPrimitiveKind(v.Kind())}
 	if v.IsValid() {
 		exportedV := tryExport(v)
 		if exportedV.CanInterface() {
@@ -359,37 +349,61 @@ func (d *Dumper) processValue(v reflect.Value, level int) {
 	}
 }
 
-// preScanBFS is the first analysis pass. It traverses the entire object graph using BFS
-// to collect statistics about every value, such as reference counts and depth.
-func (d *Dumper) preScanBFS(val reflect.Value) {
-	queue := []queueItem{{val, 0}}
-
-	for len(queue) > 0 {
-		item := queue[0]
-		queue = queue[1:]
-		if !item.v.IsValid() {
-			continue
-		}
-		// Collect stats for the current value.
-		d.processValue(item.v, item.level)
+// preScanBFS is the first analysis pass. It traverses the object graph of each value
+// using BFS, collecting statistics about every value, such as reference counts and
+// depth, together with the candidate definition points of every value.
+//
+// Each value is traversed on its own, so that nesting levels and candidates are
+// relative to that value, but a composite shared with an earlier value only
+// contributes to the statistics once.
+func (d *Dumper) preScanBFS(vals ...reflect.Value) {
+	order := 0
+	for _, val := range vals {
+		queue := []queueItem{{val, 0, true}}
+		traversed := make(map[canonicalKey]bool)
+		for len(queue) > 0 {
+			item := queue[0]
+			queue = queue[1:]
+			if !item.v.IsValid() {
+				continue
+			}
+			// Collect stats for the current value.
+			if item.counted {
+				d.processValue(item.v, item.level)
+			}
+			if rawKey, ok := d.getRawKey(item.v); ok {
+				d.recordCandidate(item.v, rawKey, item.level, order)
+				d.recordSliceSpan(item.v, rawKey, order)
+				order++
+			}
 
-		// Continue traversal into composite types.
-		targetVal := deref(item.v)
-		if !targetVal.IsValid() || !isCompositeOrInterface(targetVal.Kind()) {
-			continue
-		}
+			// Continue traversal into composite types.
+			targetVal := deref(item.v)
+			if !targetVal.IsValid() || !isCompositeOrInterface(targetVal.Kind()) {
+				continue
+			}
 
-		// Avoid re-traversing the same composite value.
-		key, ok := d.getRawKey(targetVal)
-		if !ok || d.visitedForScan[key] {
-			continue
+			// Avoid re-traversing the same composite value.
+			key, ok := d.getRawKey(targetVal)
+			if !ok || traversed[key] {
+				continue
+			}
+			traversed[key] = true
+			counted := item.counted && !d.visitedForScan[key]
+			d.visitedForScan[key] = true
+			queue = d.addChildrenToQueue(queue, item.v, item.level, counted)
 		}
-		d.visitedForScan[key] = true
-		queue = d.addChildrenToQueue(queue, item.v, item.level)
 	}
 }
 
-// resetState clears all maps and slices used for reference tracking.
+// Reset clears all reference tracking and cycle detection state. Every top-level
+// dump already starts with a reset, so calling it is only needed to release the
+// memory held by a long-lived Dumper after dumping a large object graph.
+func (d *Dumper) Reset() {
+	d.resetState()
+}
+
+// resetState clears all maps used for reference tracking and cycle detection.
 // It is called at the beginning of each top-level dump operation to ensure a clean slate.
 func (d *Dumper) resetState() {
 	d.referenceStats = make(map[canonicalKey]*RefStats)
@@ -397,9 +411,52 @@ func (d *Dumper) resetState() {
 	d.canonicalRoots = make(map[canonicalKey]canonicalKey)
 	d.primitiveInstances = make(map[canonicalKey]any)
 	d.definitionPoints = make(map[canonicalKey]definitionPoint)
+	d.candidatePoints = make(map[canonicalKey]definitionPoint)
 	d.renderedIDs = make(map[canonicalKey]bool)
 	d.fakeAddrs = make(map[any]uintptr)
 	d.visitedForScan = make(map[canonicalKey]bool)
+	d.sliceSpans = nil
+	d.sharedArrays = make(map[canonicalKey]sharedArray)
+	d.visitedPointers = make(map[canonicalKey]bool)
+	d.addressLabels = make(map[uintptr]string)
+	d.inlineLengths = make(map[canonicalKey]int)
+	d.mapSamples = make(map[uintptr][]reflect.Value)
+	d.renderedNodes = 0
+	if d.config.AdaptiveInline {
+		d.termWidth = terminalWidth()
+	}
+}
+
+// resolveReference decides how a value participates in the ID/back-reference
+// system while rendering. It returns the value's ID ("" if it has none) and whether
+// it must be printed as a back-reference instead of being rendered in full.
+// Returning an ID with isBackref == false marks the ID as rendered.
+func (d *Dumper) resolveReference(v reflect.Value) (id string, isBackref bool) {
+	rawKey, keyOK := d.getRawKey(v)
+	if !keyOK {
+		return "", false
+	}
+	rootKey := d.findRoot(rawKey)
+	id, hasID := d.referenceIDs[rootKey]
+	if !hasID {
+		return "", false
+	}
+	def, defExists := d.definitionPoints[rootKey]
+	instKey, instKeyOK := d.getInstanceKey(v)
+	// Check if the current value is the chosen "definition point".
+	isTheChosenDefinition := defExists && instKeyOK && def.instanceKey == instKey
+
+	if !isTheChosenDefinition {
+		// This is not the definition point, so it must be a back-reference.
+		return id, true
+	}
+	// This is the definition point. If we've already rendered it (e.g., a cycle),
+	// print a back-reference. Otherwise, print the ID and render the value.
+	if d.renderedIDs[rootKey] {
+		return id, true
+	}
+	d.renderedIDs[rootKey] = true
+	return id, false
 }
 
 // unifyAllCopies is the second analysis pass. It identifies values that are identical
@@ -539,3 +539,118 @@ govar.ZST => &1 {}
 		})
 	}
 }
+
+func TestDumperReuseWithoutTracking(t *testing.T) {
+	type Node struct {
+		Name string
+		Next *Node
+	}
+	type Pair struct {
+		Left, Right *Node
+	}
+
+	cfg := DefaultConfig
+	cfg.UseColors = false
+	cfg.TrackReferences = false
+	cfg.EmbedTypeMethods = false
+	d := NewDumper(cfg)
+
+	shared := &Node{Name: "shared"}
+	value := Pair{Left: shared, Right: shared}
+	for i := 0; i < 2; i++ {
+		out := d.Sdump(value)
+		if strings.Contains(out, "<cycle>") {
+			t.Fatalf("dump #%d: shared values and first fields must not be reported as cycles, got:\n%s", i+1, out)
+		}
+		if strings.Count(out, `"shared"`) != 2 {
+			t.Errorf("dump #%d: expected the shared node to be rendered twice, got:\n%s", i+1, out)
+		}
+	}
+
+	loop := &Node{Name: "loop"}
+	loop.Next = loop
+	if out := d.Sdump(loop); !strings.Contains(out, "<cycle>") {
+		t.Errorf("a real cycle should still be detected, got:\n%s", out)
+	}
+
+	d.Reset()
+	if len(d.visitedPointers) != 0 || len(d.referenceIDs) != 0 {
+		t.Error("Reset should clear the reference and cycle detection state")
+	}
+}
+
+func TestDefinitionPointPriority(t *testing.T) {
+	value := definitionPoint{level: 3, order: 5}
+	pointer := definitionPoint{isPointerRef: true, indirectionLevel: 1, level: 0, order: 0}
+	doublePointer := definitionPoint{isPointerRef: true, indirectionLevel: 2, level: 0, order: 1}
+	shallowValue := definitionPoint{level: 1, order: 9}
+	laterValue := definitionPoint{level: 3, order: 6}
+
+	tests := []struct {
+		name        string
+		a, b        definitionPoint
+		aBetterThan bool
+	}{
+		{"value beats pointer", value, pointer, true},
+		{"pointer loses to value", pointer, value, false},
+		{"lower indirection wins", pointer, doublePointer, true},
+		{"lower level wins", shallowValue, value, true},
+		{"first encountered wins a tie", value, laterValue, true},
+		{"later loses a tie", laterValue, value, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.a.betterThan(tt.b); got != tt.aBetterThan {
+				t.Errorf("betterThan() = %v, want %v", got, tt.aBetterThan)
+			}
+		})
+	}
+}
+
+func TestAnalysisSharedAcrossValues(t *testing.T) {
+	type Node struct {
+		Name string
+		Next *Node
+	}
+	cfg := DefaultConfig
+	cfg.UseColors = false
+	cfg.EmbedTypeMethods = false
+	d := NewDumper(cfg)
+
+	shared := &Node{Name: "shared"}
+	outer := &Node{Name: "outer", Next: shared}
+	out := d.Sdump(outer, shared)
+	if strings.Count(out, `"shared"`) != 1 {
+		t.Errorf("the shared node should be rendered once, got:\n%s", out)
+	}
+	if !strings.Contains(out, "↩︎ &1") {
+		t.Errorf("the second value should be a back-reference, got:\n%s", out)
+	}
+}
+
+func TestDumpSharedBackingArrays(t *testing.T) {
+	type Parts struct {
+		All, Head, Tail, Other []int
+	}
+	buf := []int{1, 2, 3, 4, 5, 6, 7, 8}
+	cfg := DefaultConfig
+	cfg.UseColors = false
+	cfg.EmbedTypeMethods = false
+
+	out := NewDumper(cfg).Sdump(Parts{All: buf, Head: buf[:3], Tail: buf[5:], Other: []int{9}})
+	for _, want := range []string{
+		"All    []int => &1 |8| [",
+		"Head   []int => shares array with &1 [offset 0] |L:3 C:8| [",
+		"Tail   []int => shares array with &1 [offset 5] |3| [",
+		"Other  []int => |1| [",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q, got:\n%s", want, out)
+		}
+	}
+
+	cfg.TrackReferences = false
+	if out := NewDumper(cfg).Sdump(buf[:3], buf[5:]); strings.Contains(out, "shares array") {
+		t.Errorf("shared arrays are only detected with TrackReferences, got:\n%s", out)
+	}
+}
@@ -7,7 +7,7 @@ package govar
 import (
 	"fmt"
 	"reflect"
-	"runtime"
+	"regexp"
 	"sort"
 	"strings"
 	"unsafe"
@@ -18,20 +18,16 @@ import (
 // and an empty string for the value. If the value was nil and resolved type is
 // also nil, retuned type is "unknown"
 func checkNilInterface(v any) (string, string) {
-	rt := reflect.TypeOf(v)
-
-	resolvedType := ""
-	resolvedValue := ""
-	if v == nil {
-		resolvedValue = "<nil>"
-		if rt != nil {
-			resolvedType = rt.String()
-		} else {
-			resolvedType = "unknown"
-		}
-		return resolvedType, resolvedValue
+	return checkNilValue(reflect.ValueOf(v))
+}
+
+// checkNilValue is checkNilInterface for a reflect.Value; the zero (invalid)
+// reflect.Value stands for a nil interface.
+func checkNilValue(v reflect.Value) (string, string) {
+	if !v.IsValid() {
+		return "unknown", "<nil>"
 	}
-	return rt.String(), resolvedValue
+	return v.Type().String(), ""
 }
 
 // deref repeatedly dereferences a pointer or interface until it reaches a non-pointer,
@@ -46,31 +42,11 @@ func deref(v reflect.Value) reflect.Value {
 	return v
 }
 
-// findCallerInStack inspects the call stack to locate the first caller
-// not within the govar package. It returns the file, line number, and the name
-// of the govar function that was called (e.g., "govar.Dump").
-func findCallerInStack() (string, int, string) {
-	govarFuncName := ""
-	for i := 1; i < 15; i++ {
-		pc, file, line, ok := runtime.Caller(i)
-		if !ok {
-			break
-		}
-
-		fn := runtime.FuncForPC(pc)
-		if fn == nil || !strings.Contains(fn.Name(), "/"+PackageName) {
-			return file, line, govarFuncName
-		}
-		tmpNameSliced := strings.Split(fn.Name(), "/")
-		govarFuncName = tmpNameSliced[len(tmpNameSliced)-1]
-	}
-	return "", 0, ""
-}
-
-// findTypeMethods returns all exported methods associated with the given
+// collectTypeMethods returns all exported methods associated with the given
 // named reflect.Type, considering both value and pointer receivers.
-// It avoids duplicates if a method exists on both.
-func findTypeMethods(typ reflect.Type) []reflect.Method {
+// It avoids duplicates if a method exists on both. Use the cached
+// findTypeMethods instead.
+func collectTypeMethods(typ reflect.Type) []reflect.Method {
 	seen := make(map[string]bool)
 	methods := []reflect.Method{}
 
@@ -103,9 +79,75 @@ func findTypeMethods(typ reflect.Type) []reflect.Method {
 	return methods
 }
 
-// getFunctionName returns the full package-qualified function name from a reflect.Value.
-func getFunctionName(v reflect.Value) string {
-	return runtime.FuncForPC(v.Pointer()).Name()
+// formatTypeName renders a reflect.Type like reflect.Type.String does, but lets
+// the caller decide how named types are spelled via the qualify callback, which
+// receives every named type declared in a package. Composite types are rebuilt
+// recursively so the callback applies to every named type they mention.
+func formatTypeName(t reflect.Type, qualify func(t reflect.Type) string) string {
+	if t.Name() != "" {
+		if t.PkgPath() == "" {
+			return t.Name() // predeclared types (int, string, error...)
+		}
+		return qualify(t)
+	}
+
+	switch t.Kind() {
+	case reflect.Ptr:
+		return "*" + formatTypeName(t.Elem(), qualify)
+	case reflect.Slice:
+		return "[]" + formatTypeName(t.Elem(), qualify)
+	case reflect.Array:
+		return fmt.Sprintf("[%d]%s", t.Len(), formatTypeName(t.Elem(), qualify))
+	case reflect.Map:
+		return "map[" + formatTypeName(t.Key(), qualify) + "]" + formatTypeName(t.Elem(), qualify)
+	case reflect.Chan:
+		switch t.ChanDir() {
+		case reflect.RecvDir:
+			return "<-chan " + formatTypeName(t.Elem(), qualify)
+		case reflect.SendDir:
+			return "chan<- " + formatTypeName(t.Elem(), qualify)
+		}
+		if t.Elem().Kind() == reflect.Chan && t.Elem().ChanDir() == reflect.RecvDir {
+			return "chan (" + formatTypeName(t.Elem(), qualify) + ")"
+		}
+		return "chan " + formatTypeName(t.Elem(), qualify)
+	case reflect.Func:
+		in := make([]string, t.NumIn())
+		for i := range t.NumIn() {
+			if t.IsVariadic() && i == t.NumIn()-1 {
+				in[i] = "..." + formatTypeName(t.In(i).Elem(), qualify)
+			} else {
+				in[i] = formatTypeName(t.In(i), qualify)
+			}
+		}
+		out := make([]string, t.NumOut())
+		for i := range t.NumOut() {
+			out[i] = formatTypeName(t.Out(i), qualify)
+		}
+		sig := "func(" + strings.Join(in, ", ") + ")"
+		switch len(out) {
+		case 0:
+		case 1:
+			sig += " " + out[0]
+		default:
+			sig += " (" + strings.Join(out, ", ") + ")"
+		}
+		return sig
+	default:
+		// Unnamed structs and interfaces are spelled out by the reflect package.
+		return t.String()
+	}
+}
+
+// importPathPattern matches the slash-separated directory part of an import path,
+// as the reflect package prints it inside generic type arguments.
+var importPathPattern = regexp.MustCompile(`(?:[\w\-.~]+/)+`)
+
+// shortenImportPaths collapses import paths embedded in a type name down to the
+// last path element, e.g. "List[github.com/acme/billing.Invoice]" becomes
+// "List[billing.Invoice]".
+func shortenImportPaths(name string) string {
+	return importPathPattern.ReplaceAllString(name, "")
 }
 
 // getIndirectionLevel calculates the level of pointer indirection for a value.
@@ -247,9 +289,19 @@ func isSimpleValue(v reflect.Value) bool {
 	}
 }
 
+// reflectValues returns the reflect.Value of each of vs.
+func reflectValues(vs []any) []reflect.Value {
+	vals := make([]reflect.Value, len(vs))
+	for i, v := range vs {
+		vals[i] = reflect.ValueOf(v)
+	}
+	return vals
+}
+
 // makeAddressable returns an addressable value:
 // if v is an unaddressable value, it wraps it in a newly allocated pointer.
-// Otherwise, returns v unchanged.
+// Otherwise, returns v unchanged. Read-only values (reached through unexported
+// fields) cannot be copied and are returned unchanged as well.
 func makeAddressable(v reflect.Value) reflect.Value {
 	// A guard clause to handle zero/invalid reflect.Values.
 	if !v.IsValid() {
@@ -259,7 +311,7 @@ func makeAddressable(v reflect.Value) reflect.Value {
 	if v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
 		return v
 	}
-	if !v.CanAddr() {
+	if !v.CanAddr() && v.CanInterface() {
 		temp := reflect.New(v.Type()).Elem()
 		if v.IsValid() {
 			temp.Set(v)
@@ -309,6 +361,81 @@ func sortMapKeys(m reflect.Value) []reflect.Value {
 	return keys
 }
 
+// hasOnlyUnexportedFields reports whether v (or the value it points to) is a
+// struct with at least one field, none of which is exported.
+func hasOnlyUnexportedFields(v reflect.Value) bool {
+	if v.Kind() == reflect.Ptr && !v.IsNil() {
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct || v.NumField() == 0 {
+		return false
+	}
+	for i := range v.NumField() {
+		if v.Type().Field(i).IsExported() {
+			return false
+		}
+	}
+	return true
+}
+
+// matchesTypeList reports whether t, or its element type if t is a pointer, is
+// named in the list, either as printed by reflect ("time.Duration") or with its
+// full import path ("github.com/acme/shop.Order").
+func matchesTypeList(t reflect.Type, names []string) bool {
+	if len(names) == 0 {
+		return false
+	}
+	candidates := []reflect.Type{t}
+	if t.Kind() == reflect.Ptr {
+		candidates = append(candidates, t.Elem())
+	}
+	for _, c := range candidates {
+		for _, name := range names {
+			if name == c.String() || (c.PkgPath() != "" && name == c.PkgPath()+"."+c.Name()) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// naturalLess reports whether a sorts before b when runs of digits are compared
+// by their numeric value, so "item2" sorts before "item10". Numerically equal
+// runs with more leading zeros sort last; other characters compare bytewise.
+func naturalLess(a, b string) bool {
+	for a != "" && b != "" {
+		da, db := leadingDigits(a), leadingDigits(b)
+		if da == 0 || db == 0 {
+			if a[0] != b[0] {
+				return a[0] < b[0]
+			}
+			a, b = a[1:], b[1:]
+			continue
+		}
+		na, nb := strings.TrimLeft(a[:da], "0"), strings.TrimLeft(b[:db], "0")
+		if len(na) != len(nb) {
+			return len(na) < len(nb)
+		}
+		if na != nb {
+			return na < nb
+		}
+		if da != db {
+			return da < db
+		}
+		a, b = a[da:], b[db:]
+	}
+	return len(a) < len(b)
+}
+
+// leadingDigits returns the number of ASCII digits at the start of s.
+func leadingDigits(s string) int {
+	n := 0
+	for n < len(s) && s[n] >= '0' && s[n] <= '9' {
+		n++
+	}
+	return n
+}
+
 // toAddressableByteSlice returns a copy of a byte-like array/slice,
 // ensuring the returned slice is addressable.
 func toAddressableByteSlice(v reflect.Value) []byte {
@@ -319,17 +446,3 @@ func toAddressableByteSlice(v reflect.Value) []byte {
 	}
 	return out
 }
-
-// tryExport returns an interfaceable version of v if possible.
-// If v represents an unexported field but is addressable, it uses unsafe
-// to create an accessible copy. This is crucial for inspecting private fields.
-func tryExport(v reflect.Value) reflect.Value {
-	if v.CanInterface() {
-		return v
-	}
-	if v.CanAddr() {
-		return reflect.NewAt(v.Type(), unsafe.Pointer(v.UnsafeAddr())).Elem()
-	}
-	// Final fallback: return original value, even if unexported
-	return v
-}
@@ -4,6 +4,7 @@ import (
 	"reflect"
 	"strings"
 	"testing"
+	"time"
 )
 
 func TestCheckNilInterface(t *testing.T) {
@@ -81,6 +82,38 @@ func TestForceExported(t *testing.T) {
 	}
 }
 
+func TestFormatTypeName(t *testing.T) {
+	full := func(t reflect.Type) string { return t.PkgPath() + "." + t.Name() }
+	tests := []struct {
+		name string
+		typ  reflect.Type
+		want string
+	}{
+		{"builtin", reflect.TypeOf(0), "int"},
+		{"named", reflect.TypeOf(exampleStruct{}), "github.com/janvaclavik/govar.exampleStruct"},
+		{"pointer", reflect.TypeOf(&exampleStruct{}), "*github.com/janvaclavik/govar.exampleStruct"},
+		{"map", reflect.TypeOf(map[string][]*exampleStruct{}), "map[string][]*github.com/janvaclavik/govar.exampleStruct"},
+		{"array", reflect.TypeOf([2]strings.Builder{}), "[2]strings.Builder"},
+		{"recv chan", reflect.TypeOf(make(<-chan exampleStruct)), "<-chan github.com/janvaclavik/govar.exampleStruct"},
+		{"variadic func", reflect.TypeOf(func(int, ...exampleStruct) (bool, error) { return false, nil }), "func(int, ...github.com/janvaclavik/govar.exampleStruct) (bool, error)"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := formatTypeName(tt.typ, full); got != tt.want {
+				t.Errorf("formatTypeName() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestShortenImportPaths(t *testing.T) {
+	got := shortenImportPaths("List[github.com/acme/billing.Invoice,map[string]*gopkg.in/yaml.v3.Node]")
+	want := "List[billing.Invoice,map[string]*yaml.v3.Node]"
+	if got != want {
+		t.Errorf("shortenImportPaths() = %q, want %q", got, want)
+	}
+}
+
 func TestGetFunctionName(t *testing.T) {
 	f := func() {}
 	name := getFunctionName(reflect.ValueOf(f))
@@ -117,6 +150,28 @@ func TestSortMapKeys(t *testing.T) {
 	}
 }
 
+func TestNaturalLess(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want bool
+	}{
+		{"item2", "item10", true},
+		{"item10", "item2", false},
+		{"a", "b", true},
+		{"file1.txt", "file1.txt", false},
+		{"v1.2", "v1.10", true},
+		{"x01", "x1", false},
+		{"x1", "x01", true},
+		{"abc", "abc1", true},
+		{"10", "9a", false},
+	}
+	for _, tt := range tests {
+		if got := naturalLess(tt.a, tt.b); got != tt.want {
+			t.Errorf("naturalLess(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
 func TestToAddressableByteSlice(t *testing.T) {
 	arr := [3]uint8{1, 2, 3}
 	rv := reflect.ValueOf(arr)
@@ -365,3 +420,47 @@ func TestTryExport(t *testing.T) {
 		t.Errorf("tryExport could not access unexported field")
 	}
 }
+
+func TestMatchesTypeList(t *testing.T) {
+	tests := []struct {
+		name  string
+		typ   reflect.Type
+		names []string
+		want  bool
+	}{
+		{"empty list", reflect.TypeOf(time.Second), nil, false},
+		{"short name", reflect.TypeOf(time.Second), []string{"time.Duration"}, true},
+		{"pointer matches element", reflect.TypeOf(&testData{}), []string{"govar.testData"}, true},
+		{"full import path", reflect.TypeOf(testData{}), []string{"github.com/janvaclavik/govar.testData"}, true},
+		{"other type", reflect.TypeOf(testData{}), []string{"time.Duration"}, false},
+	}
+	for _, tt := range tests {
+		if got := matchesTypeList(tt.typ, tt.names); got != tt.want {
+			t.Errorf("%s: matchesTypeList(%v, %v) = %v, want %v", tt.name, tt.typ, tt.names, got, tt.want)
+		}
+	}
+}
+
+func TestTypeCaches(t *testing.T) {
+	type simple struct {
+		A int
+		B string
+	}
+	type nested struct {
+		A int
+		S simple
+	}
+
+	first := findTypeMethods(reflect.TypeOf(time.Time{}))
+	second := findTypeMethods(reflect.TypeOf(time.Time{}))
+	if len(first) == 0 || &first[0] != &second[0] {
+		t.Errorf("expected the method list of time.Time to be computed once and shared")
+	}
+
+	if !hasOnlySimpleFields(reflect.TypeOf(simple{})) {
+		t.Error("expected simple to have only simple fields")
+	}
+	if hasOnlySimpleFields(reflect.TypeOf(nested{})) || hasOnlySimpleFields(reflect.TypeOf(struct{ E error }{})) {
+		t.Error("expected structs with struct or interface fields not to be simple")
+	}
+}
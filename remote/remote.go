@@ -0,0 +1,132 @@
+// Package remote ships govar dumps over the network. A Sink serializes each
+// dump as a JSON-encoded govar.Record (one per line) and sends it to a
+// collector over TCP or a Unix socket; Listen runs such a collector and
+// re-renders the received dumps locally. This is useful for debugging
+// processes that have no attached terminal, such as containers and daemons.
+package remote
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+
+	"github.com/janvaclavik/govar"
+)
+
+// Sink sends dumps to a remote collector. It is safe for concurrent use.
+type Sink struct {
+	conn    net.Conn
+	dumper  *govar.Dumper
+	content govar.RecordContent
+
+	mu  sync.Mutex
+	enc *json.Encoder
+}
+
+// Dial connects to a collector listening on the given network ("tcp", "unix", ...)
+// and address. Dumps are rendered with cfg and carry the selected content
+// (govar.RecordText, govar.RecordTree, or both).
+func Dial(network, address string, cfg govar.DumperConfig, content govar.RecordContent) (*Sink, error) {
+	conn, err := net.Dial(network, address)
+	if err != nil {
+		return nil, fmt.Errorf("remote: dial %s %s: %w", network, address, err)
+	}
+	return &Sink{
+		conn:    conn,
+		dumper:  govar.NewDumper(cfg),
+		content: content,
+		enc:     json.NewEncoder(conn),
+	}, nil
+}
+
+// Dump renders the values and sends them to the collector.
+func (s *Sink) Dump(values ...any) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	rec := s.dumper.Record(s.content, values...)
+	if err := s.enc.Encode(rec); err != nil {
+		return fmt.Errorf("remote: send dump: %w", err)
+	}
+	return nil
+}
+
+// Close closes the connection to the collector.
+func (s *Sink) Close() error {
+	return s.conn.Close()
+}
+
+// Serve accepts connections on l and calls handle for every record received.
+// Records from different connections may be handled concurrently; handle must
+// be safe for concurrent use. Serve returns when l is closed.
+func Serve(l net.Listener, handle func(govar.Record)) error {
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			return err
+		}
+		go func() {
+			defer conn.Close()
+			dec := json.NewDecoder(bufio.NewReader(conn))
+			for {
+				var rec govar.Record
+				if err := dec.Decode(&rec); err != nil {
+					return
+				}
+				handle(rec)
+			}
+		}()
+	}
+}
+
+// Listen runs a collector on the given network and address and re-renders every
+// received dump to w, preceded by a header naming the remote call site. If
+// writing to w fails, Listen stops accepting dumps and returns that error.
+func Listen(network, address string, w io.Writer) error {
+	l, err := net.Listen(network, address)
+	if err != nil {
+		return fmt.Errorf("remote: listen %s %s: %w", network, address, err)
+	}
+	defer l.Close()
+
+	var (
+		mu       sync.Mutex
+		writeErr error
+	)
+	err = Serve(l, func(rec govar.Record) {
+		mu.Lock()
+		defer mu.Unlock()
+		if writeErr != nil {
+			return
+		}
+		if err := WriteRecord(w, rec); err != nil {
+			writeErr = fmt.Errorf("remote: write dump: %w", err)
+			l.Close()
+		}
+	})
+	mu.Lock()
+	defer mu.Unlock()
+	if writeErr != nil {
+		return writeErr
+	}
+	return err
+}
+
+// WriteRecord re-renders a received record to w. The rendered text is preferred;
+// records carrying only a structured tree are rendered from the tree.
+func WriteRecord(w io.Writer, rec govar.Record) error {
+	if _, err := fmt.Fprintf(w, "[>] %s  ⟵  %s:%d  (%s)\n", rec.Func, rec.File, rec.Line, rec.Time.Format("15:04:05.000")); err != nil {
+		return err
+	}
+	if rec.Text != "" {
+		_, err := fmt.Fprintln(w, rec.Text)
+		return err
+	}
+	if err := govar.WriteTree(w, rec.Tree, 3); err != nil {
+		return err
+	}
+	_, err := fmt.Fprintln(w)
+	return err
+}
@@ -0,0 +1,110 @@
+package remote
+
+import (
+	"bytes"
+	"errors"
+	"net"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/janvaclavik/govar"
+)
+
+func TestSinkAndServe(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen failed: %v", err)
+	}
+	defer l.Close()
+
+	received := make(chan govar.Record, 1)
+	go Serve(l, func(rec govar.Record) { received <- rec })
+
+	cfg := govar.DefaultConfig
+	cfg.UseColors = false
+	sink, err := Dial("tcp", l.Addr().String(), cfg, govar.RecordText|govar.RecordTree)
+	if err != nil {
+		t.Fatalf("Dial error: %v", err)
+	}
+	defer sink.Close()
+
+	if err := sink.Dump(map[string]int{"answer": 42}); err != nil {
+		t.Fatalf("Dump error: %v", err)
+	}
+
+	var rec govar.Record
+	select {
+	case rec = <-received:
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for the dump")
+	}
+
+	if rec.Time.IsZero() || rec.Line == 0 {
+		t.Errorf("record should carry the time and call site, got %+v", rec)
+	}
+	if !strings.Contains(rec.Text, `"answer"  => 42`) {
+		t.Errorf("record text missing dumped value, got:\n%s", rec.Text)
+	}
+	if len(rec.Tree) != 1 || len(rec.Tree[0].Children) != 1 || rec.Tree[0].Children[0].Value != "42" {
+		t.Errorf("record tree missing dumped value, got %+v", rec.Tree)
+	}
+}
+
+func TestWriteRecordFromTree(t *testing.T) {
+	rec := govar.Record{
+		Func: "remote.(*Sink).Dump",
+		File: "main.go",
+		Line: 7,
+		Tree: []*govar.Node{{Type: "[]int", Kind: "slice", Children: []*govar.Node{
+			{Key: "0", Type: "int", Kind: "int", Value: "1"},
+		}}},
+	}
+
+	var buf bytes.Buffer
+	if err := WriteRecord(&buf, rec); err != nil {
+		t.Fatalf("WriteRecord error: %v", err)
+	}
+	out := buf.String()
+	for _, want := range []string{"main.go:7", "[]int => |1|", "   0 int => 1"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("WriteRecord output missing %q, got:\n%s", want, out)
+		}
+	}
+}
+
+// failingWriter is an io.Writer whose writes always fail.
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) { return 0, errors.New("disk full") }
+
+func TestListenReturnsWriteErrors(t *testing.T) {
+	address := filepath.Join(t.TempDir(), "govar.sock")
+	done := make(chan error, 1)
+	go func() { done <- Listen("unix", address, failingWriter{}) }()
+
+	var sink *Sink
+	for deadline := time.Now().Add(5 * time.Second); sink == nil; {
+		var err error
+		if sink, err = Dial("unix", address, govar.DefaultConfig, govar.RecordText); err != nil {
+			if time.Now().After(deadline) {
+				t.Fatalf("Dial error: %v", err)
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	}
+	defer sink.Close()
+	if err := sink.Dump(42); err != nil {
+		t.Fatalf("Dump error: %v", err)
+	}
+
+	select {
+	case err := <-done:
+		if err == nil || !strings.Contains(err.Error(), "remote: write dump: disk full") {
+			t.Errorf("expected the write error, got %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for Listen to return")
+	}
+}
@@ -0,0 +1,41 @@
+// Package govar provides a powerful and highly configurable pretty-printer for Go
+// data structures. This file implements the ShowRenderStats option: a footer
+// reporting how many nodes a dump visited and how long its analysis and
+// rendering took, e.g. |rendered 12,401 nodes in 48ms|, which makes dumps of
+// pathologically large values easy to spot.
+package govar
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// renderStatsFooter writes the render statistics footer for a dump that started
+// at start, if ShowRenderStats is enabled.
+func (d *Dumper) renderStatsFooter(sb *strings.Builder, start time.Time) {
+	if !d.config.ShowRenderStats {
+		return
+	}
+	locale := d.config.Locale
+	if locale.Grouping == "" {
+		locale = LocaleEnglish
+	}
+	nodes := locale.format(strconv.Itoa(d.renderedNodes))
+	sb.WriteString(d.ApplyFormat(ColorDimGray, "|rendered "+nodes+" nodes in "+roundDuration(time.Since(start)).String()+"|"))
+	sb.WriteString("\n")
+}
+
+// roundDuration rounds d to a precision that is meaningful for humans: whole
+// milliseconds from 10ms, tenths of milliseconds below, and microseconds below
+// 1ms.
+func roundDuration(d time.Duration) time.Duration {
+	switch {
+	case d >= 10*time.Millisecond:
+		return d.Round(time.Millisecond)
+	case d >= time.Millisecond:
+		return d.Round(100 * time.Microsecond)
+	default:
+		return d.Round(time.Microsecond)
+	}
+}
@@ -0,0 +1,94 @@
+//go:build !tinygo
+
+// Package govar provides a powerful and highly configurable pretty-printer for Go
+// data structures. This file contains the helpers that depend on runtime symbol
+// tables and on unsafe memory access. They are replaced by reduced versions in
+// runtime_helpers_tinygo.go when building with TinyGo.
+package govar
+
+import (
+	"bytes"
+	"reflect"
+	"runtime"
+	"strconv"
+	"strings"
+	"unsafe"
+)
+
+// findCallerInStack inspects the call stack to locate the first caller
+// not within the govar package. It returns the file, line number, and the name
+// of the govar function that was called (e.g., "govar.Dump").
+func findCallerInStack() (string, int, string) {
+	govarFuncName := ""
+	for i := 1; i < 15; i++ {
+		pc, file, line, ok := runtime.Caller(i)
+		if !ok {
+			break
+		}
+
+		fn := runtime.FuncForPC(pc)
+		if fn == nil || !strings.Contains(fn.Name(), "/"+PackageName) {
+			return file, line, govarFuncName
+		}
+		tmpNameSliced := strings.Split(fn.Name(), "/")
+		govarFuncName = tmpNameSliced[len(tmpNameSliced)-1]
+	}
+	return "", 0, ""
+}
+
+// goroutineID returns the ID of the calling goroutine, parsed from the
+// "goroutine N [running]:" line of its stack trace, or 0 if it cannot be parsed.
+func goroutineID() uint64 {
+	buf := make([]byte, 64)
+	buf = buf[:runtime.Stack(buf, false)]
+	buf = bytes.TrimPrefix(buf, []byte("goroutine "))
+	if i := bytes.IndexByte(buf, ' '); i > 0 {
+		buf = buf[:i]
+	}
+	id, err := strconv.ParseUint(string(buf), 10, 64)
+	if err != nil {
+		return 0
+	}
+	return id
+}
+
+// callers records the program counters of the calling goroutine's stack, like
+// runtime.Callers.
+func callers(skip int, pcs []uintptr) int {
+	return runtime.Callers(skip+1, pcs)
+}
+
+// framesFromPCs resolves program counters, as returned by runtime.Callers, to
+// stack frames.
+func framesFromPCs(pcs []uintptr) Stack {
+	frames := Stack{}
+	iter := runtime.CallersFrames(pcs)
+	for {
+		frame, more := iter.Next()
+		if frame.Function != "" {
+			frames = append(frames, StackFrame{Function: frame.Function, File: frame.File, Line: frame.Line})
+		}
+		if !more {
+			return frames
+		}
+	}
+}
+
+// getFunctionName returns the full package-qualified function name from a reflect.Value.
+func getFunctionName(v reflect.Value) string {
+	return runtime.FuncForPC(v.Pointer()).Name()
+}
+
+// tryExport returns an interfaceable version of v if possible.
+// If v represents an unexported field but is addressable, it uses unsafe
+// to create an accessible copy. This is crucial for inspecting private fields.
+func tryExport(v reflect.Value) reflect.Value {
+	if v.CanInterface() {
+		return v
+	}
+	if v.CanAddr() {
+		return reflect.NewAt(v.Type(), unsafe.Pointer(v.UnsafeAddr())).Elem()
+	}
+	// Final fallback: return original value, even if unexported
+	return v
+}
@@ -0,0 +1,40 @@
+//go:build tinygo
+
+// Package govar provides a powerful and highly configurable pretty-printer for Go
+// data structures. This file contains reduced versions of the runtime and unsafe
+// dependent helpers for TinyGo, which lacks reliable runtime.FuncForPC name
+// resolution and reflect.NewAt. Unexported fields are still listed, but values
+// that cannot be interfaced are rendered from their kind-specific accessors only.
+package govar
+
+import "reflect"
+
+// findCallerInStack is not supported on TinyGo; dumps are rendered without a header.
+func findCallerInStack() (string, int, string) {
+	return "", 0, ""
+}
+
+// goroutineID is not supported on TinyGo and always returns 0.
+func goroutineID() uint64 {
+	return 0
+}
+
+// callers is not supported on TinyGo and records no program counters.
+func callers(skip int, pcs []uintptr) int {
+	return 0
+}
+
+// framesFromPCs cannot resolve symbols on TinyGo and returns no frames.
+func framesFromPCs(pcs []uintptr) Stack {
+	return Stack{}
+}
+
+// getFunctionName cannot resolve symbol names on TinyGo and returns a placeholder.
+func getFunctionName(v reflect.Value) string {
+	return "func"
+}
+
+// tryExport returns v unchanged, as TinyGo builds never force-export unexported values.
+func tryExport(v reflect.Value) reflect.Value {
+	return v
+}
@@ -0,0 +1,91 @@
+// Package govar provides a powerful and highly configurable pretty-printer for Go
+// data structures. This file implements the SparseArrays mode, which renders
+// large, mostly-zero numeric arrays and slices (bitsets, histograms, sparse
+// matrices) as their non-zero elements plus a count of the zeros left out.
+package govar
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// minSparseLength is the length from which numeric arrays and slices are
+// considered for sparse rendering; shorter ones read fine in full.
+const minSparseLength = 16
+
+// isSparse reports whether v is a numeric array or slice rendered sparsely:
+// SparseArrays is enabled, the output is not single-line (SingleLine or a
+// compact field), v has at least minSparseLength elements and at least half
+// of them are zero.
+func (d *Dumper) isSparse(v reflect.Value) bool {
+	if !d.config.SparseArrays || d.config.SingleLine || d.forceInline > 0 || v.Len() < minSparseLength || !isNumericKind(v.Type().Elem().Kind()) {
+		return false
+	}
+	return countZeros(v)*2 >= v.Len()
+}
+
+// isNumericKind returns true for integer, floating-point and complex kinds.
+func isNumericKind(k reflect.Kind) bool {
+	switch k {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64, reflect.Complex64, reflect.Complex128:
+		return true
+	default:
+		return false
+	}
+}
+
+// countZeros returns the number of zero elements of the array or slice v.
+func countZeros(v reflect.Value) int {
+	zeros := 0
+	for i := range v.Len() {
+		if v.Index(i).IsZero() {
+			zeros++
+		}
+	}
+	return zeros
+}
+
+// renderSparse renders the non-zero elements of a sparse array or slice as a
+// block of `index => value` lines (up to MaxItems of them), followed by the
+// number of zero elements that were left out.
+func (d *Dumper) renderSparse(sb *strings.Builder, v reflect.Value, level int) {
+	var indices []int
+	for i := range v.Len() {
+		if !v.Index(i).IsZero() {
+			indices = append(indices, i)
+		}
+	}
+	shown := indices[:min(len(indices), d.config.MaxItems)]
+
+	maxIndexLen := 0
+	if len(shown) > 0 {
+		maxIndexLen = len(strconv.Itoa(shown[len(shown)-1]))
+	}
+	formattedType := d.formatType(v.Index(0), true)
+
+	fmt.Fprintln(sb)
+	for _, i := range shown {
+		indexStr := strconv.Itoa(i)
+		indexSymbol := d.ApplyFormat(d.depthColor(level, ColorDarkTeal), padRight(indexStr, utf8.RuneCountInString(indexStr), maxIndexLen))
+		renderIndex := indexSymbol + " => "
+		if formattedType != "" {
+			renderIndex = indexSymbol + " " + formattedType + " => "
+		}
+		d.renderIndent(sb, level+1, renderIndex)
+		leave := d.enterPath(indexPathStep(i))
+		d.renderValue(sb, v.Index(i), level+1, false)
+		leave()
+		d.renderBlockLineEnd(sb)
+	}
+	if len(shown) < len(indices) {
+		d.renderIndent(sb, level+1, d.ApplyFormat(ColorSlateGray, "… (truncated)\n"))
+	}
+	zeros := fmt.Sprintf("… %d zeros\n", v.Len()-len(indices))
+	d.renderIndent(sb, level+1, d.ApplyFormat(ColorSlateGray, zeros))
+	d.renderIndent(sb, level, "")
+}
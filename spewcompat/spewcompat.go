@@ -0,0 +1,102 @@
+// Package spewcompat exposes the commonly used API of
+// github.com/davecgh/go-spew/spew (Dump, Fdump, Sdump and ConfigState) backed
+// by govar, so code using spew can migrate by changing a single import:
+//
+//	import spew "github.com/janvaclavik/govar/spewcompat"
+//
+// Output follows govar's format rather than spew's: like spew it shows types,
+// lengths and capacities and has no colors, but it detects shared references
+// and cycles instead of printing raw pointer addresses.
+package spewcompat
+
+import (
+	"io"
+	"math"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/janvaclavik/govar"
+)
+
+// tabWidth is the number of columns a tab in ConfigState.Indent stands for.
+const tabWidth = 8
+
+// ConfigState mirrors spew's ConfigState. The fields spew code commonly sets
+// are all present; those without a govar counterpart are accepted and ignored.
+type ConfigState struct {
+	// Indent is the indentation of each nesting level. govar indents with
+	// spaces, so only its width is used, counting tabs as tabWidth columns.
+	Indent string
+	// MaxDepth is the maximum nesting depth rendered; 0 means no limit.
+	MaxDepth int
+	// DisableMethods renders values implementing error and fmt.Stringer by
+	// their contents instead of by their method's result.
+	DisableMethods bool
+	// DisableCapacities omits the capacities of slices; lengths are still shown.
+	DisableCapacities bool
+
+	// Accepted for compatibility and ignored: govar never calls methods on
+	// pointer receivers it would have to create, replaces pointer addresses by
+	// reference IDs, always sorts map keys, and formats map keys itself.
+	DisablePointerMethods   bool
+	DisablePointerAddresses bool
+	ContinueOnMethod        bool
+	SortKeys                bool
+	SpewKeys                bool
+}
+
+// Config is the configuration used by the package-level functions, matching
+// spew's default of a single space indentation.
+var Config = ConfigState{Indent: " "}
+
+// NewDefaultConfig returns a ConfigState with spew's default options.
+func NewDefaultConfig() *ConfigState {
+	return &ConfigState{Indent: " "}
+}
+
+// Dump prints the values to stdout using Config.
+func Dump(a ...any) {
+	Config.Dump(a...)
+}
+
+// Fdump writes the values to w using Config.
+func Fdump(w io.Writer, a ...any) {
+	Config.Fdump(w, a...)
+}
+
+// Sdump returns the values formatted using Config.
+func Sdump(a ...any) string {
+	return Config.Sdump(a...)
+}
+
+// Dump prints the values to stdout.
+func (c *ConfigState) Dump(a ...any) {
+	c.dumper().Dump(a...)
+}
+
+// Fdump writes the values to w.
+func (c *ConfigState) Fdump(w io.Writer, a ...any) {
+	c.dumper().Fdump(w, a...)
+}
+
+// Sdump returns the formatted values.
+func (c *ConfigState) Sdump(a ...any) string {
+	return c.dumper().Sdump(a...)
+}
+
+// dumper returns a govar Dumper configured like c.
+func (c *ConfigState) dumper() *govar.Dumper {
+	cfg := govar.DefaultConfig
+	cfg.UseColors = false
+	cfg.EmbedTypeMethods = false
+	cfg.IndentWidth = utf8.RuneCountInString(c.Indent) + strings.Count(c.Indent, "\t")*(tabWidth-1)
+	cfg.MaxDepth = c.MaxDepth
+	if c.MaxDepth == 0 {
+		cfg.MaxDepth = math.MaxInt
+	}
+	cfg.IgnoreStringer = c.DisableMethods
+	if c.DisableCapacities {
+		cfg.HiddenMeta |= govar.MetaListCapacity
+	}
+	return govar.NewDumper(cfg)
+}
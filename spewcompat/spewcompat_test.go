@@ -0,0 +1,50 @@
+package spewcompat
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSdump(t *testing.T) {
+	type Node struct {
+		Name  string
+		Items []int
+		Next  *Node
+	}
+	v := Node{Name: "root", Items: make([]int, 1, 4), Next: &Node{Name: "leaf"}}
+
+	out := Sdump(v)
+	if strings.Contains(out, "\033[") {
+		t.Errorf("output should have no colors, got:\n%q", out)
+	}
+	for _, want := range []string{"spewcompat.Node => {\n", "\n ⯀ Name ", "|L:1 C:4|"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q, got:\n%s", want, out)
+		}
+	}
+
+	cfg := ConfigState{Indent: "\t", MaxDepth: 1, DisableCapacities: true}
+	out = cfg.Sdump(v)
+	if strings.Contains(out, "|L:1 C:4|") || !strings.Contains(out, "|1|") {
+		t.Errorf("DisableCapacities should hide capacities but keep lengths, got:\n%s", out)
+	}
+	if !strings.Contains(out, "\n        ⯀ Name ") || !strings.Contains(out, "max depth reached") {
+		t.Errorf("Indent and MaxDepth should be applied, got:\n%s", out)
+	}
+}
+
+func TestConfigStateDisableMethods(t *testing.T) {
+	cfg := NewDefaultConfig()
+	buf := &bytes.Buffer{}
+	cfg.Fdump(buf, time.Second)
+	if !strings.Contains(buf.String(), "1s") {
+		t.Errorf("Stringer should be used by default, got:\n%s", buf)
+	}
+
+	cfg.DisableMethods = true
+	if out := cfg.Sdump(time.Second); strings.Contains(out, "1s") {
+		t.Errorf("DisableMethods should bypass Stringer, got:\n%s", out)
+	}
+}
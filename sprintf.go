@@ -0,0 +1,151 @@
+// Package govar provides a powerful and highly configurable pretty-printer for Go
+// data structures. This file implements Printf-style formatting: Sprintf,
+// Printf, and Fprintf render their %v operands with govar's compact inline
+// formatting, so dumps can be woven into ordinary log messages.
+package govar
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// inlineOperand is a Printf operand rendered by govar for the %v verb.
+type inlineOperand struct {
+	cfg   DumperConfig
+	value any
+}
+
+// Format implements fmt.Formatter. %v renders the operand on a single line
+// using the configuration, %+v additionally shows types, and other verbs
+// (including %#v) format the operand like package fmt. Under Anonymize, every
+// verb renders like %v, so the value is blurred.
+func (o inlineOperand) Format(f fmt.State, verb rune) {
+	if (verb != 'v' || f.Flag('#')) && o.cfg.Anonymize == AnonymizeOff {
+		fmt.Fprintf(f, fmt.FormatString(f, verb), o.value)
+		return
+	}
+	cfg := o.cfg
+	cfg.ShowTypes = cfg.ShowTypes || f.Flag('+')
+	cfg.SingleLine, cfg.ShowExpressions = true, false
+	cfg.ShowRenderStats, cfg.ShowLegend = false, false
+	d := NewDumper(cfg)
+	d.Formatter = d.textFormatter(cfg.UseColors)
+	sb := &strings.Builder{}
+	d.renderAllValues(sb, o.value)
+	io.WriteString(f, strings.TrimSuffix(sb.String(), "\n"))
+}
+
+// inlineOperands wraps the args formatted only by v verbs in format (under
+// Anonymize, by any verb formatting a value), so that Printf-style functions
+// render them with cfg. Other args are left alone, as fmt handles %T, %p and
+// * widths before it consults a fmt.Formatter.
+func inlineOperands(cfg DumperConfig, format string, args []any) []any {
+	byV, byValue, byRaw := operandVerbs(format, len(args))
+	anonymize := cfg.Anonymize != AnonymizeOff
+	wrapped := make([]any, len(args))
+	for i, arg := range args {
+		if (byV[i] || byValue[i]) && !byRaw[i] && (anonymize || !byValue[i]) {
+			wrapped[i] = inlineOperand{cfg: cfg, value: arg}
+		} else {
+			wrapped[i] = arg
+		}
+	}
+	return wrapped
+}
+
+// operandVerbs scans format the way fmt does, reporting for each of n operands
+// whether a %v verb formats it, whether another verb formatting its value (%d,
+// %s, …) does, and whether %T, %p, or a * width or precision consumes it.
+// Explicit argument indexes ("%[2]v") are honored.
+func operandVerbs(format string, n int) (byV, byValue, byRaw []bool) {
+	byV, byValue, byRaw = make([]bool, n), make([]bool, n), make([]bool, n)
+	argNum := 0
+	consume := func(verb rune) {
+		if argNum >= 0 && argNum < n {
+			switch verb {
+			case 'v':
+				byV[argNum] = true
+			case 'T', 'p', '*':
+				byRaw[argNum] = true
+			default:
+				byValue[argNum] = true
+			}
+		}
+		argNum++
+	}
+	// argIndex applies an explicit "[k]" argument index at format[i:], if any,
+	// and returns the position after it.
+	argIndex := func(i int) int {
+		if i >= len(format) || format[i] != '[' {
+			return i
+		}
+		end := strings.IndexByte(format[i:], ']')
+		if end < 0 {
+			return i
+		}
+		if k, err := strconv.Atoi(format[i+1 : i+end]); err == nil {
+			argNum = k - 1
+		}
+		return i + end + 1
+	}
+	// widthOrPrecision skips a width or precision at format[i:], consuming an
+	// operand for "*", and returns the position after it.
+	widthOrPrecision := func(i int) int {
+		i = argIndex(i)
+		if i < len(format) && format[i] == '*' {
+			consume('*')
+			return i + 1
+		}
+		for i < len(format) && format[i] >= '0' && format[i] <= '9' {
+			i++
+		}
+		return i
+	}
+
+	for i := 0; i < len(format); i++ {
+		if format[i] != '%' {
+			continue
+		}
+		i++
+		for i < len(format) && strings.IndexByte("#0+- ", format[i]) >= 0 {
+			i++
+		}
+		i = widthOrPrecision(i)
+		if i < len(format) && format[i] == '.' {
+			i = widthOrPrecision(i + 1)
+		}
+		i = argIndex(i)
+		if i >= len(format) {
+			break
+		}
+		verb, size := utf8.DecodeRuneInString(format[i:])
+		i += size - 1
+		if verb != '%' {
+			consume(verb)
+		}
+	}
+	return byV, byValue, byRaw
+}
+
+// Sprintf formats according to a format specifier like fmt.Sprintf, except
+// that %v operands are rendered inline by govar with the Dumper's
+// configuration and %+v operands additionally show types, e.g.
+// `user={⯀ Name => "Ann", ⯀ Age => 31}`. Other verbs behave as in package fmt,
+// except under Anonymize, where operands of value verbs (%d, %s, …) are
+// rendered like %v. No call site header is written.
+func (d *Dumper) Sprintf(format string, args ...any) string {
+	return fmt.Sprintf(format, inlineOperands(d.config, format, args)...)
+}
+
+// Printf is like Sprintf, printing to stdout.
+func (d *Dumper) Printf(format string, args ...any) {
+	fmt.Fprint(stdoutWriter(), d.Sprintf(format, args...))
+}
+
+// Fprintf is like Sprintf, writing to w.
+func (d *Dumper) Fprintf(w io.Writer, format string, args ...any) (int, error) {
+	return fmt.Fprint(w, d.Sprintf(format, args...))
+}
@@ -0,0 +1,60 @@
+package govar
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestSprintf(t *testing.T) {
+	type User struct {
+		Name string
+		Age  int
+	}
+	user := User{Name: "Ann", Age: 31}
+
+	got := Sprintf("user=%v items=%+v n=%03d q=%q", user, []int{1, 2}, 7, "x")
+	want := `user={⯀ Name => "Ann", ⯀ Age => 31} items=[]int => [0 => 1, 1 => 2] n=007 q="x"`
+	if got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+
+	if got := Sprintf("%#v", user); got != `govar.User{Name:"Ann", Age:31}` {
+		t.Errorf("expected %%#v to format like package fmt, got %q", got)
+	}
+
+	if got := Sprintf("%T %v", []int{1}, []int{1}); got != "[]int [0 => 1]" {
+		t.Errorf("expected %%T to print the operand's type, got %q", got)
+	}
+	ptr := &user
+	if got, want := Sprintf("%p|%[1]v", ptr), fmt.Sprintf("%p|%[1]v", ptr); got != want {
+		t.Errorf("expected an operand also used by %%p to format like package fmt, got %q, want %q", got, want)
+	}
+	if got := Sprintf("%*d|%v", 3, 7, user.Name); got != `  7|"Ann"` {
+		t.Errorf("expected * widths to consume their operand, got %q", got)
+	}
+
+	if got := Sprintf("%v", [2][2]int{{1, 2}, {3, 4}}); strings.Contains(got, "\n") {
+		t.Errorf("expected matrices on a single line, got %q", got)
+	}
+
+	cfg := DefaultConfig
+	cfg.UseColors = false
+	cfg.Anonymize = AnonymizeMask
+	got = NewDumper(cfg).Sprintf("pin=%d name=%s user=%v type=%T", 4711, "Ann", user, user)
+	if strings.Contains(got, "4711") || strings.Contains(got, "Ann") || !strings.Contains(got, "type=govar.User") {
+		t.Errorf("expected all values anonymized and types kept, got %q", got)
+	}
+
+	buf := &bytes.Buffer{}
+	if n, err := Fprintf(buf, "[%v]", nil); err != nil || n != 7 || buf.String() != "[<nil>]" {
+		t.Errorf("expected 7 bytes \"[<nil>]\", got %d bytes %q (err=%v)", n, buf.String(), err)
+	}
+
+	SetEnabled(false)
+	defer SetEnabled(true)
+	if got := Sprintf("user=%v", user); got != "user={Ann 31}" {
+		t.Errorf("expected fmt formatting while disabled, got %q", got)
+	}
+}
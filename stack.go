@@ -0,0 +1,193 @@
+// Package govar provides a powerful and highly configurable pretty-printer for Go
+// data structures. This file implements stack traces: the Stack type with its
+// constructors for runtime.Callers and debug.Stack output, the extraction of
+// stacks from error values (a pkg/errors style StackTrace() method or %+v
+// output), and their rendering as colored call lists.
+package govar
+
+import (
+	"fmt"
+	"path"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// StackFrame is a single resolved call site of a stack trace.
+type StackFrame struct {
+	Function string // Package-qualified function name.
+	File     string // Absolute path of the source file.
+	Line     int    // Line number within File.
+}
+
+// Stack is a captured call stack, innermost call first. Dumping a Stack renders
+// it as a call list with one "function file:line" entry per frame instead of a
+// struct or an escaped string.
+type Stack []StackFrame
+
+// CallerStack captures the stack of the calling goroutine. The argument skip is
+// the number of frames to skip before recording, with 0 identifying the caller
+// of CallerStack.
+func CallerStack(skip int) Stack {
+	pcs := make([]uintptr, 64)
+	return StackFromPCs(pcs[:callers(skip+2, pcs)])
+}
+
+// StackFromPCs resolves program counters, as returned by runtime.Callers, to a
+// Stack. It returns an empty Stack on platforms without symbol tables (TinyGo).
+func StackFromPCs(pcs []uintptr) Stack {
+	return framesFromPCs(pcs)
+}
+
+// ParseStack parses a goroutine stack trace as formatted by debug.Stack,
+// runtime.Stack, or a panic, dropping call arguments and PC offsets. Traces of
+// several goroutines are concatenated.
+func ParseStack(trace []byte) Stack {
+	return parseStackText(string(trace))
+}
+
+// asStack returns the Stack held by v, if v is of type Stack.
+func asStack(v reflect.Value) (Stack, bool) {
+	v = tryExport(v)
+	if v.Type() != reflect.TypeOf(Stack(nil)) || !v.CanInterface() {
+		return nil, false
+	}
+	return v.Interface().(Stack), true
+}
+
+// errorStack returns the stack trace carried by an error value. It supports a
+// StackTrace() method returning a slice of program counters (as pkg/errors and
+// compatible packages do) and fmt.Formatter implementations that print
+// "function\n\tfile:line" pairs for %+v. It returns nil if neither applies.
+func errorStack(v reflect.Value) Stack {
+	if !v.CanInterface() || (v.Kind() == reflect.Ptr && v.IsNil()) {
+		return nil
+	}
+	if m := v.MethodByName("StackTrace"); m.IsValid() {
+		mt := m.Type()
+		if mt.NumIn() == 0 && mt.NumOut() == 1 && mt.Out(0).Kind() == reflect.Slice && mt.Out(0).Elem().Kind() == reflect.Uintptr {
+			trace := m.Call(nil)[0]
+			pcs := make([]uintptr, trace.Len())
+			for i := range pcs {
+				pcs[i] = uintptr(trace.Index(i).Uint())
+			}
+			if frames := framesFromPCs(pcs); len(frames) > 0 {
+				return frames
+			}
+		}
+	}
+	if f, ok := v.Interface().(fmt.Formatter); ok {
+		return parseStackText(fmt.Sprintf("%+v", f))
+	}
+	return nil
+}
+
+// parseStackText extracts "function\n\tfile:line" pairs from a formatted stack
+// trace, ignoring message and goroutine header lines around them. Call
+// arguments ("main.f(0x1, ...)"), "created by" prefixes, and PC offsets
+// ("+0x1d") are removed.
+func parseStackText(text string) Stack {
+	var frames Stack
+	lines := strings.Split(text, "\n")
+	for i := 0; i+1 < len(lines); i++ {
+		location, ok := strings.CutPrefix(lines[i+1], "\t")
+		if !ok || lines[i] == "" || strings.HasPrefix(lines[i], "\t") {
+			continue
+		}
+		location, _, _ = strings.Cut(location, " +0x")
+		sep := strings.LastIndexByte(location, ':')
+		if sep < 0 {
+			continue
+		}
+		line, err := strconv.Atoi(location[sep+1:])
+		if err != nil {
+			continue
+		}
+		frames = append(frames, StackFrame{Function: stackFunctionName(lines[i]), File: location[:sep], Line: line})
+		i++
+	}
+	return frames
+}
+
+// stackFunctionName strips the decorations of a function line in a goroutine
+// stack trace, e.g. "created by main.run in goroutine 1" or "main.f(0x1, ...)".
+func stackFunctionName(line string) string {
+	line = strings.TrimPrefix(line, "created by ")
+	if i := strings.Index(line, " in goroutine "); i >= 0 {
+		line = line[:i]
+	}
+	if strings.HasSuffix(line, ")") {
+		if i := strings.LastIndexByte(line, '('); i > 0 {
+			line = line[:i]
+		}
+	}
+	return line
+}
+
+// renderErrorStack writes up to ErrorStackFrames frames of an error's stack
+// trace under its message, one indented line per frame, or in brackets after
+// it in single-line output. Frames of the Go runtime are skipped, and the
+// number of frames left out is summarized.
+func (d *Dumper) renderErrorStack(sb *strings.Builder, v reflect.Value, level int) {
+	if d.config.ErrorStackFrames <= 0 {
+		return
+	}
+	var frames Stack
+	for _, frame := range errorStack(v) {
+		if !strings.HasPrefix(frame.Function, "runtime.") {
+			frames = append(frames, frame)
+		}
+	}
+	shown := min(len(frames), d.config.ErrorStackFrames)
+	lines := make([]string, 0, shown+1)
+	for _, frame := range frames[:shown] {
+		lines = append(lines, d.formatStackFrame(frame))
+	}
+	if hidden := len(frames) - shown; hidden > 0 {
+		lines = append(lines, d.ApplyFormat(ColorDimGray, fmt.Sprintf("… %d more frames", hidden)))
+	}
+	if len(lines) > 0 && (d.config.SingleLine || d.forceInline > 0) {
+		sb.WriteString(" [" + strings.Join(lines, ", ") + "]")
+		return
+	}
+	for _, line := range lines {
+		fmt.Fprintln(sb)
+		d.renderIndent(sb, level+1, line)
+	}
+}
+
+// renderStack writes a Stack as an indented call list, limited by MaxItems.
+// Single-line output lists the frames separated by commas instead.
+func (d *Dumper) renderStack(sb *strings.Builder, stack Stack, level int) {
+	if d.showMeta(MetaListLength) {
+		fmt.Fprint(sb, d.metaHint(fmt.Sprintf("%d frames", len(stack)), ""))
+	}
+	inline := d.config.SingleLine || d.forceInline > 0
+	fmt.Fprint(sb, "[")
+	for i, frame := range stack {
+		if !inline {
+			fmt.Fprintln(sb)
+			d.renderIndent(sb, level+1, "")
+		} else if i > 0 {
+			sb.WriteString(", ")
+		}
+		if i >= d.config.MaxItems {
+			sb.WriteString(d.ApplyFormat(ColorDimGray, fmt.Sprintf("… %d more frames", len(stack)-i)))
+			break
+		}
+		sb.WriteString(d.ApplyFormat(ColorDimGray, strconv.Itoa(i)+" ") + d.formatStackFrame(frame))
+	}
+	if len(stack) > 0 && !inline {
+		fmt.Fprintln(sb)
+		d.renderIndent(sb, level, "")
+	}
+	fmt.Fprint(sb, "]")
+}
+
+// formatStackFrame renders a frame as "at pkg.Func file:line", with the file
+// relative to the working directory so terminals and editors can link it.
+func (d *Dumper) formatStackFrame(frame StackFrame) string {
+	return d.ApplyFormat(ColorDimGray, "at ") +
+		d.ApplyFormat(ColorLightTeal, path.Base(frame.Function)) + " " +
+		d.ApplyFormat(ColorSlateGray, fmt.Sprintf("%s:%d", relativeSourcePath(frame.File), frame.Line))
+}
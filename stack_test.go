@@ -0,0 +1,119 @@
+package govar
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+type traceFrame uintptr
+
+type tracedError struct {
+	msg string
+	pcs []traceFrame
+}
+
+func newTracedError(msg string) *tracedError {
+	pcs := make([]uintptr, 32)
+	n := runtime.Callers(2, pcs)
+	err := &tracedError{msg: msg}
+	for _, pc := range pcs[:n] {
+		err.pcs = append(err.pcs, traceFrame(pc))
+	}
+	return err
+}
+
+func (e *tracedError) Error() string            { return e.msg }
+func (e *tracedError) StackTrace() []traceFrame { return e.pcs }
+
+type formattedError struct{}
+
+func (formattedError) Error() string { return "formatted" }
+
+func (formattedError) Format(s fmt.State, verb rune) {
+	fmt.Fprint(s, "formatted\nmain.handler\n\t/app/main.go:42\nmain.main\n\t/app/main.go:10\nruntime.main\n\t/go/src/runtime/proc.go:250")
+}
+
+func TestErrorStackFrames(t *testing.T) {
+	cfg := DefaultConfig
+	cfg.UseColors = false
+	cfg.EmbedTypeMethods = false
+	cfg.ErrorStackFrames = 1
+
+	out := NewDumper(cfg).Sdump(newTracedError("traced"))
+	if !strings.Contains(out, `"traced"`) || !strings.Contains(out, "at govar.TestErrorStackFrames stack_test.go:") {
+		t.Errorf("expected the StackTrace() frame under the message, got:\n%s", out)
+	}
+	if !strings.Contains(out, "more frames") {
+		t.Errorf("expected trimmed frames to be summarized, got:\n%s", out)
+	}
+
+	cfg.ErrorStackFrames = 5
+	out = NewDumper(cfg).Sdump(formattedError{})
+	for _, want := range []string{"at main.handler ", "app/main.go:42", "at main.main "} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in output, got:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "runtime.main") || strings.Contains(out, "more frames") {
+		t.Errorf("runtime frames should be skipped, got:\n%s", out)
+	}
+
+	cfg.SingleLine = true
+	if out := NewDumper(cfg).Sdump(formattedError{}); strings.Count(out, "\n") != 2 || !strings.Contains(out, " [at main.handler ") {
+		t.Errorf("expected single-line output to keep the frames on the message line, got:\n%s", out)
+	}
+	cfg.SingleLine = false
+
+	cfg.ErrorStackFrames = 0
+	if out := NewDumper(cfg).Sdump(formattedError{}); strings.Contains(out, "at main.handler") {
+		t.Errorf("stacks should be off by default, got:\n%s", out)
+	}
+}
+
+func TestParseStack(t *testing.T) {
+	trace := "goroutine 1 [running]:\n" +
+		"runtime/debug.Stack()\n\t/go/src/runtime/debug/stack.go:26 +0x5e\n" +
+		"main.handle(0xc000012345, {0x10, 0x2})\n\t/app/main.go:42 +0x1d\n" +
+		"created by main.serve in goroutine 1\n\t/app/server.go:7 +0x45\n"
+	want := Stack{
+		{Function: "runtime/debug.Stack", File: "/go/src/runtime/debug/stack.go", Line: 26},
+		{Function: "main.handle", File: "/app/main.go", Line: 42},
+		{Function: "main.serve", File: "/app/server.go", Line: 7},
+	}
+	got := ParseStack([]byte(trace))
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("ParseStack() = %v, want %v", got, want)
+	}
+}
+
+func TestDumpStack(t *testing.T) {
+	cfg := DefaultConfig
+	cfg.UseColors = false
+	cfg.EmbedTypeMethods = false
+
+	stack := CallerStack(0)
+	out := NewDumper(cfg).Sdump(stack)
+	if !strings.Contains(out, "frames| [") || !strings.Contains(out, "0 at govar.TestDumpStack stack_test.go:") {
+		t.Errorf("expected a call list starting at the caller, got:\n%s", out)
+	}
+
+	cfg.MaxItems = 1
+	out = NewDumper(cfg).Sdump(struct{ Trace Stack }{stack})
+	if !strings.Contains(out, "more frames") {
+		t.Errorf("expected long stacks to be truncated by MaxItems, got:\n%s", out)
+	}
+
+	cfg.SingleLine = true
+	out = NewDumper(cfg).Sdump(struct{ Trace Stack }{stack})
+	if !strings.Contains(out, "[0 at govar.TestDumpStack stack_test.go:") || !strings.Contains(out, ", … ") || strings.Count(out, "\n") != 2 {
+		t.Errorf("expected single-line output to list the frames on one line, got:\n%s", out)
+	}
+	cfg.SingleLine = false
+
+	node := NewDumper(cfg).Tree(stack)[0]
+	if len(node.Children) != 1 || !node.Truncated || !strings.Contains(node.Children[0].Value, "govar.TestDumpStack") {
+		t.Errorf("unexpected tree node: %+v", node)
+	}
+}
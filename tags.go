@@ -0,0 +1,257 @@
+// Package govar provides a powerful and highly configurable pretty-printer for Go
+// data structures. This file parses the `govar:"..."` struct tag, whose
+// comma-separated directives control how individual fields are rendered, and
+// implements the masking of sensitive fields.
+package govar
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"path"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// MaskStrategy selects how the value of a masked field is hidden.
+type MaskStrategy int
+
+const (
+	MaskNone    MaskStrategy = iota // The field is not masked.
+	MaskFull                        // The value is replaced by "***".
+	MaskPartial                     // Only the start and the end of the value are revealed ("sk_live_…9f2c").
+	MaskHash                        // The value is replaced by a stable hash ("sha256:1f2e3d4c5b6a").
+)
+
+// maskStrategyNames maps the names used in `govar:"mask=..."` tags to strategies.
+var maskStrategyNames = map[string]MaskStrategy{
+	"full":    MaskFull,
+	"partial": MaskPartial,
+	"hash":    MaskHash,
+}
+
+// fieldOptions holds the rendering directives parsed from a `govar:"..."` tag.
+type fieldOptions struct {
+	mask MaskStrategy // Explicit mask strategy; MaskNone if the tag does not mask.
+	// maskDefault is set by a bare `mask` directive, which uses the configured strategy.
+	maskDefault bool
+	bytes       bool          // Integer value is a size in bytes (`govar:"bytes"`).
+	unit        time.Duration // Integer value is a duration or Unix timestamp in this unit (`govar:"unit=ms"`).
+	format      string        // Rendering directive: "hex", "bin", "quote", or "compact" (`govar:"format=hex"`).
+}
+
+// timeUnits maps the names used in `govar:"unit=..."` tags to durations.
+var timeUnits = map[string]time.Duration{
+	"ns": time.Nanosecond,
+	"us": time.Microsecond,
+	"µs": time.Microsecond,
+	"ms": time.Millisecond,
+	"s":  time.Second,
+}
+
+// parseFieldOptions parses the `govar` tag of a struct field. Unknown directives
+// are ignored so tags stay forward compatible.
+func parseFieldOptions(tag reflect.StructTag) fieldOptions {
+	var opts fieldOptions
+	for _, directive := range strings.Split(tag.Get(PackageName), ",") {
+		name, value, _ := strings.Cut(strings.TrimSpace(directive), "=")
+		switch name {
+		case "mask":
+			if strategy, ok := maskStrategyNames[value]; ok {
+				opts.mask = strategy
+			} else {
+				opts.maskDefault = true
+			}
+		case "bytes":
+			opts.bytes = true
+		case "unit":
+			opts.unit = timeUnits[value]
+		case "format":
+			opts.format = value
+		}
+	}
+	return opts
+}
+
+// fieldMask returns the mask strategy that applies to a struct field, from its
+// tag or from the configured MaskFieldPatterns.
+func (d *Dumper) fieldMask(field reflect.StructField) MaskStrategy {
+	opts := parseFieldOptions(field.Tag)
+	if opts.mask != MaskNone {
+		return opts.mask
+	}
+	if opts.maskDefault || d.matchesMaskPattern(field.Name) {
+		if d.config.MaskStrategy == MaskNone {
+			return MaskFull
+		}
+		return d.config.MaskStrategy
+	}
+	return MaskNone
+}
+
+// matchesMaskPattern reports whether a field name matches one of the
+// MaskFieldPatterns (shell-style globs, compared case-insensitively).
+func (d *Dumper) matchesMaskPattern(name string) bool {
+	name = strings.ToLower(name)
+	for _, pattern := range d.config.MaskFieldPatterns {
+		if ok, _ := path.Match(strings.ToLower(pattern), name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// maskValue returns the masked representation of v, without quotes or colors.
+func maskValue(v reflect.Value, strategy MaskStrategy) string {
+	var text string
+	switch {
+	case v.Kind() == reflect.String:
+		text = v.String()
+	case v.Kind() == reflect.Slice && v.Type().Elem().Kind() == reflect.Uint8 && !v.IsNil():
+		text = string(v.Bytes())
+	case tryExport(v).CanInterface():
+		text = fmt.Sprint(tryExport(v).Interface())
+	}
+
+	switch strategy {
+	case MaskPartial:
+		return revealPartially(text)
+	case MaskHash:
+		sum := sha256.Sum256([]byte(text))
+		return "sha256:" + hex.EncodeToString(sum[:])[:12]
+	default:
+		return "***"
+	}
+}
+
+// revealPartially keeps up to the first 8 and the last 4 runes of s, but never
+// more than a quarter of it on each side; short values are fully hidden.
+func revealPartially(s string) string {
+	runes := []rune(s)
+	head, tail := min(len(runes)/4, 8), min(len(runes)/4, 4)
+	if head == 0 {
+		return "***"
+	}
+	return string(runes[:head]) + "…" + string(runes[len(runes)-tail:])
+}
+
+// renderMaskedValue writes the masked representation of a field value. No meta
+// information is added, as even the length of a secret may be sensitive.
+func (d *Dumper) renderMaskedValue(sb *strings.Builder, v reflect.Value, strategy MaskStrategy) {
+	sb.WriteString(d.ApplyFormat(ColorSlateGray, strconv.Quote(maskValue(v, strategy))))
+}
+
+// fieldHint returns the human-readable hint requested by the tag of a struct
+// field for its value (e.g. "1.5 KiB" for `govar:"bytes"`), or "" if none
+// applies. Hints are omitted under Anonymize, as they would reveal the value.
+func (d *Dumper) fieldHint(field reflect.StructField, v reflect.Value) string {
+	if d.config.Anonymize != AnonymizeOff {
+		return ""
+	}
+	opts := parseFieldOptions(field.Tag)
+	if v.Kind() == reflect.Ptr && !v.IsNil() {
+		v = v.Elem()
+	}
+	var n int64
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n = v.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		n = int64(min(v.Uint(), math.MaxInt64))
+	default:
+		return ""
+	}
+	switch {
+	case opts.bytes:
+		return humanizeBytes(n)
+	case opts.unit != 0:
+		return humanizeTimeUnit(n, opts.unit)
+	}
+	return ""
+}
+
+// humanizeBytes formats a byte count with binary (IEC) units, e.g. "1.5 KiB".
+func humanizeBytes(n int64) string {
+	const units = "KMGTPE"
+	sign, size := "", float64(n)
+	if n < 0 {
+		sign, size = "-", -size
+	}
+	if size < 1024 {
+		return fmt.Sprintf("%s%d B", sign, int64(size))
+	}
+	exp := 0
+	for size >= 1024 && exp < len(units) {
+		size /= 1024
+		exp++
+	}
+	num := strings.TrimSuffix(fmt.Sprintf("%.1f", size), ".0")
+	return fmt.Sprintf("%s%s %ciB", sign, num, units[exp-1])
+}
+
+// humanizeTimeUnit interprets n as a count of unit. Values that fall between
+// the years 1990 and 2100 when read as a Unix timestamp are shown in RFC 3339,
+// anything else as a duration (e.g. "1.5s").
+func humanizeTimeUnit(n int64, unit time.Duration) string {
+	perSecond := int64(time.Second / unit)
+	t := time.Unix(n/perSecond, (n%perSecond)*int64(unit))
+	if t.Year() >= 1990 && t.Year() < 2100 {
+		return t.UTC().Format(time.RFC3339Nano)
+	}
+	if n > math.MaxInt64/int64(unit) || n < math.MinInt64/int64(unit) {
+		return ""
+	}
+	return (time.Duration(n) * unit).String()
+}
+
+// formatFieldValue renders v according to a `format=hex|bin|quote` directive.
+// It reports false when the directive does not apply to the value's kind, in
+// which case the value is rendered as usual. The "compact" directive is handled
+// by renderFieldValue, as it changes the layout rather than a single value.
+// Under Anonymize the directives are ignored, so the value is anonymized.
+func (d *Dumper) formatFieldValue(v reflect.Value, format string) (string, bool) {
+	if d.config.Anonymize != AnonymizeOff {
+		return "", false
+	}
+	if v.Kind() == reflect.Ptr && !v.IsNil() {
+		v = v.Elem()
+	}
+	isBytes := v.Kind() == reflect.Slice && v.Type().Elem().Kind() == reflect.Uint8
+
+	switch format {
+	case "hex", "bin":
+		base, prefix := 16, "0x"
+		if format == "bin" {
+			base, prefix = 2, "0b"
+		}
+		switch v.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			n := v.Int()
+			if n < 0 {
+				return d.ApplyFormat(ColorSkyBlue, "-"+prefix+strconv.FormatUint(uint64(-n), base)), true
+			}
+			return d.ApplyFormat(ColorSkyBlue, prefix+strconv.FormatInt(n, base)), true
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+			return d.ApplyFormat(ColorSkyBlue, prefix+strconv.FormatUint(v.Uint(), base)), true
+		}
+		if format == "hex" && (v.Kind() == reflect.String || isBytes) {
+			return d.ApplyFormat(ColorSkyBlue, "0x"+hex.EncodeToString([]byte(stringOrBytes(v)))), true
+		}
+	case "quote":
+		if v.Kind() == reflect.String || isBytes {
+			return d.ApplyFormat(ColorLime, strconv.Quote(stringOrBytes(v))), true
+		}
+	}
+	return "", false
+}
+
+// stringOrBytes returns the contents of a string or byte slice value.
+func stringOrBytes(v reflect.Value) string {
+	if v.Kind() == reflect.String {
+		return v.String()
+	}
+	return string(toAddressableByteSlice(v))
+}
@@ -0,0 +1,233 @@
+package govar
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type credentials struct {
+	User     string
+	Password string
+	APIKey   string `govar:"mask=partial"`
+	Session  string `govar:"mask=hash"`
+	PIN      int    `govar:"mask"`
+}
+
+func TestDumpMaskedFields(t *testing.T) {
+	input := credentials{
+		User:     "alice",
+		Password: "hunter2",
+		APIKey:   "sk_live_0123456789abcdef9f2c",
+		Session:  "s3ss10n",
+		PIN:      1234,
+	}
+
+	cfg := DefaultConfig
+	cfg.UseColors = false
+	cfg.EmbedTypeMethods = false
+	cfg.MaskFieldPatterns = []string{"*password*"}
+	out := NewDumper(cfg).Sdump(input)
+
+	wantContains := []string{
+		`=> |R:5| "alice"`,
+		`Password  string => "***"`,
+		`APIKey    string => "sk_live…9f2c"`,
+		`Session   string => "sha256:`,
+		`PIN       int    => "***"`,
+	}
+	for _, want := range wantContains {
+		if !strings.Contains(out, want) {
+			t.Errorf("masked fields: missing expected fragment:\nwant contains:\n%s\ngot:\n%s", want, out)
+		}
+	}
+	for _, secret := range []string{"hunter2", "0123456789", "s3ss10n", "1234"} {
+		if strings.Contains(out, secret) {
+			t.Errorf("masked fields: output leaks %q:\n%s", secret, out)
+		}
+	}
+
+	cfg.MaskStrategy = MaskHash
+	hashed := NewDumper(cfg).Sdump(input)
+	if !strings.Contains(hashed, `Password  string => "sha256:`) || !strings.Contains(hashed, `PIN       int    => "sha256:`) {
+		t.Errorf("MaskStrategy should apply to patterns and bare mask tags, got:\n%s", hashed)
+	}
+
+	nodes := NewDumper(cfg).Tree(input)
+	if got := nodes[0].Children[1].Value; !strings.HasPrefix(got, `"sha256:`) {
+		t.Errorf("Tree should mask fields too, got %q", got)
+	}
+}
+
+func TestParseFieldOptions(t *testing.T) {
+	tests := []struct {
+		tag  reflect.StructTag
+		want fieldOptions
+	}{
+		{``, fieldOptions{}},
+		{`json:"x"`, fieldOptions{}},
+		{`govar:"mask"`, fieldOptions{maskDefault: true}},
+		{`govar:"mask=partial"`, fieldOptions{mask: MaskPartial}},
+		{`govar:"unknown, mask=hash"`, fieldOptions{mask: MaskHash}},
+	}
+	for _, tt := range tests {
+		if got := parseFieldOptions(tt.tag); got != tt.want {
+			t.Errorf("parseFieldOptions(%q) = %+v, want %+v", tt.tag, got, tt.want)
+		}
+	}
+}
+
+func TestRevealPartially(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"abc", "***"},
+		{"abcdefgh", "ab…gh"},
+		{"sk_live_0123456789abcdef9f2c", "sk_live…9f2c"},
+		{strings.Repeat("x", 40) + "tail", "xxxxxxxx…tail"},
+		{"žluťoučký kůň", "žlu…kůň"},
+	}
+	for _, tt := range tests {
+		if got := revealPartially(tt.input); got != tt.want {
+			t.Errorf("revealPartially(%q) = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestDumpBytesTag(t *testing.T) {
+	type Quota struct {
+		Used   int64  `govar:"bytes"`
+		Limit  uint64 `govar:"bytes"`
+		Buffer *int   `govar:"bytes"`
+		Files  int
+	}
+	buffer := 512
+	input := Quota{Used: 1536, Limit: 5 << 30, Buffer: &buffer, Files: 2048}
+
+	cfg := DefaultConfig
+	cfg.UseColors = false
+	cfg.EmbedTypeMethods = false
+	out := NewDumper(cfg).Sdump(input)
+
+	wantContains := []string{
+		`=> 1536 |1.5 KiB|`,
+		`=> 5368709120 |5 GiB|`,
+		`=> 512 |512 B|`,
+		`=> 2048` + "\n",
+	}
+	for _, want := range wantContains {
+		if !strings.Contains(out, want) {
+			t.Errorf("bytes tag: missing expected fragment:\nwant contains:\n%q\ngot:\n%s", want, out)
+		}
+	}
+
+	cfg.Anonymize = AnonymizeMask
+	if out := NewDumper(cfg).Sdump(input); strings.Contains(out, "KiB") || strings.Contains(out, "GiB") {
+		t.Errorf("bytes tag: hints should be omitted under Anonymize, got:\n%s", out)
+	}
+}
+
+func TestHumanizeBytes(t *testing.T) {
+	tests := []struct {
+		input int64
+		want  string
+	}{
+		{0, "0 B"},
+		{1023, "1023 B"},
+		{1024, "1 KiB"},
+		{1536, "1.5 KiB"},
+		{-2048, "-2 KiB"},
+		{3 << 20, "3 MiB"},
+		{1 << 62, "4 EiB"},
+	}
+	for _, tt := range tests {
+		if got := humanizeBytes(tt.input); got != tt.want {
+			t.Errorf("humanizeBytes(%d) = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestDumpUnitTag(t *testing.T) {
+	type Request struct {
+		StartedAt int64  `govar:"unit=s"`
+		CreatedAt int64  `govar:"unit=ms"`
+		LatencyMs int64  `govar:"unit=ms"`
+		Timeout   uint32 `govar:"unit=us"`
+		Unknown   int64  `govar:"unit=fortnight"`
+	}
+	input := Request{StartedAt: 1700000000, CreatedAt: 1700000000123, LatencyMs: 1500, Timeout: 250, Unknown: 3}
+
+	cfg := DefaultConfig
+	cfg.UseColors = false
+	cfg.EmbedTypeMethods = false
+	out := NewDumper(cfg).Sdump(input)
+
+	wantContains := []string{
+		`=> 1700000000 |2023-11-14T22:13:20Z|`,
+		`=> 1700000000123 |2023-11-14T22:13:20.123Z|`,
+		`=> 1500 |1.5s|`,
+		`=> 250 |250µs|`,
+		`=> 3` + "\n",
+	}
+	for _, want := range wantContains {
+		if !strings.Contains(out, want) {
+			t.Errorf("unit tag: missing expected fragment:\nwant contains:\n%q\ngot:\n%s", want, out)
+		}
+	}
+
+	cfg.Anonymize = AnonymizeHash
+	if out := NewDumper(cfg).Sdump(input); strings.Contains(out, "2023-") || strings.Contains(out, "1.5s") {
+		t.Errorf("unit tag: hints should be omitted under Anonymize, got:\n%s", out)
+	}
+}
+
+func TestDumpFormatTag(t *testing.T) {
+	type Point struct{ X, Y int }
+	type Packet struct {
+		Flags    uint8   `govar:"format=bin"`
+		Checksum int32   `govar:"format=hex"`
+		Offset   int     `govar:"format=hex"`
+		Payload  []byte  `govar:"format=quote"`
+		Raw      string  `govar:"format=hex"`
+		Route    []Point `govar:"format=compact"`
+		Label    string  `govar:"format=bin"`
+	}
+	input := Packet{
+		Flags:    5,
+		Checksum: 0x1f2e,
+		Offset:   -255,
+		Payload:  []byte("hi\n"),
+		Raw:      "AB",
+		Route:    []Point{{1, 2}, {3, 4}},
+		Label:    "plain",
+	}
+
+	cfg := DefaultConfig
+	cfg.UseColors = false
+	cfg.EmbedTypeMethods = false
+	out := NewDumper(cfg).Sdump(input)
+
+	wantContains := []string{
+		`=> 0b101`,
+		`=> 0x1f2e`,
+		`=> -0xff`,
+		`=> "hi\n"`,
+		`=> 0x4142`,
+		`govar.Point => {⯀ X int => 1, ⯀ Y int => 2}, 1`,
+		`=> |R:5| "plain"`,
+	}
+	for _, want := range wantContains {
+		if !strings.Contains(out, want) {
+			t.Errorf("format tag: missing expected fragment:\nwant contains:\n%s\ngot:\n%s", want, out)
+		}
+	}
+
+	cfg.Anonymize = AnonymizeMask
+	out = NewDumper(cfg).Sdump(input)
+	for _, secret := range []string{"0b101", "0x1f2e", "0xff", `"hi\n"`, "0x4142"} {
+		if strings.Contains(out, secret) {
+			t.Errorf("format tag: output leaks %q under Anonymize:\n%s", secret, out)
+		}
+	}
+}
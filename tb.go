@@ -0,0 +1,46 @@
+// Package govar provides a powerful and highly configurable pretty-printer for Go
+// data structures. This file routes dumps through a test's log (t.Log), so they
+// are attributed to the test, captured by `go test` output buffering, and only
+// shown when the test fails or -v is given.
+package govar
+
+import "strings"
+
+// TB is the subset of testing.TB used to log dumps. *testing.T, *testing.B and
+// *testing.F all satisfy it; the package itself does not import testing.
+type TB interface {
+	Helper()
+	Log(args ...any)
+}
+
+// TBDumper writes dumps to a test's log. Create one with Dumper.WithTB.
+type TBDumper struct {
+	dumper *Dumper
+	tb     TB
+}
+
+// WithTB returns a TBDumper that renders values with d's configuration and
+// writes them through tb.Log. The header is omitted, as t.Log already reports
+// the file and line of the call.
+func (d *Dumper) WithTB(tb TB) *TBDumper {
+	return &TBDumper{dumper: d, tb: tb}
+}
+
+// Dump renders the values and logs them as a single t.Log entry.
+func (t *TBDumper) Dump(vs ...any) {
+	t.tb.Helper()
+	d := t.dumper
+	d.Formatter = d.textFormatter(d.config.UseColors)
+	sb := &strings.Builder{}
+	d.renderAllValues(sb, vs...)
+	t.tb.Log("\n" + strings.TrimRight(sb.String(), "\n"))
+}
+
+// TDump logs the given values through tb.Log using the DefaultConfig with colors
+// disabled, since test output is often read from CI logs.
+func TDump(tb TB, values ...any) {
+	tb.Helper()
+	cfg := defaultConfig()
+	cfg.UseColors = false
+	NewDumper(cfg).WithTB(tb).Dump(values...)
+}
@@ -0,0 +1,44 @@
+package govar
+
+import (
+	"strings"
+	"testing"
+)
+
+// recordingTB captures what is logged and how often Helper is called.
+type recordingTB struct {
+	helpers int
+	logs    []string
+}
+
+func (r *recordingTB) Helper() { r.helpers++ }
+
+func (r *recordingTB) Log(args ...any) {
+	for _, a := range args {
+		r.logs = append(r.logs, a.(string))
+	}
+}
+
+func TestTDump(t *testing.T) {
+	tb := &recordingTB{}
+	TDump(tb, map[string]int{"answer": 42})
+
+	if len(tb.logs) != 1 {
+		t.Fatalf("expected a single log entry, got %d", len(tb.logs))
+	}
+	out := tb.logs[0]
+	if !strings.HasPrefix(out, "\n") || !strings.Contains(out, `"answer"  => 42`) {
+		t.Errorf("unexpected log output:\n%s", out)
+	}
+	if strings.Contains(out, "\033[") || strings.Contains(out, "[>]") {
+		t.Errorf("log output should have no colors and no header:\n%s", out)
+	}
+	if tb.helpers < 2 {
+		t.Errorf("TDump and TBDumper.Dump should both mark themselves as helpers, got %d calls", tb.helpers)
+	}
+}
+
+func TestWithTBRealTest(t *testing.T) {
+	// Smoke test against a real *testing.T; the output is shown with -v.
+	NewDumper(SimpleConfig).WithTB(t).Dump([]int{1, 2, 3})
+}
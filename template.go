@@ -0,0 +1,36 @@
+// Package govar provides a powerful and highly configurable pretty-printer for Go
+// data structures. This file integrates govar with html/template, so dumps can
+// be embedded into server-rendered debug pages and error templates.
+package govar
+
+import (
+	"html/template"
+	"strings"
+)
+
+// TemplateFuncs returns a FuncMap for html/template with two functions:
+//
+//   - govar renders its arguments as plain text (escaped by the template, so
+//     place it inside a <pre> element), e.g. <pre>{{govar .Order}}</pre>.
+//   - govarHTML renders its arguments as a colored HTML block, like SdumpHTML,
+//     e.g. {{govarHTML .Order .User}}.
+//
+// Both use DefaultConfig and omit the call site header, which would only point
+// into the template engine. Every call uses its own Dumper, so templates may be
+// executed concurrently.
+func TemplateFuncs() template.FuncMap {
+	return template.FuncMap{
+		"govar": func(values ...any) string {
+			cfg := defaultConfig()
+			cfg.UseColors = false
+			d := NewDumper(cfg)
+			sb := &strings.Builder{}
+			d.renderAllValues(sb, values...)
+			return strings.TrimSuffix(sb.String(), "\n")
+		},
+		"govarHTML": func(values ...any) template.HTML {
+			// SdumpHTML output is escaped by the HTMLformatter.
+			return template.HTML(NewDumper(defaultConfig()).sdumpHTML(false, values...))
+		},
+	}
+}
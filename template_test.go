@@ -0,0 +1,31 @@
+package govar
+
+import (
+	"html/template"
+	"strings"
+	"testing"
+)
+
+func TestTemplateFuncs(t *testing.T) {
+	tmpl := template.Must(template.New("debug").Funcs(TemplateFuncs()).Parse(
+		`<pre>{{govar .}}</pre>{{govarHTML .}}`))
+	data := testData{Name: "<script>alert(1)</script>", Value: 7}
+
+	sb := &strings.Builder{}
+	if err := tmpl.Execute(sb, data); err != nil {
+		t.Fatalf("Execute() error: %v", err)
+	}
+	out := sb.String()
+	if strings.Contains(out, "<script>") {
+		t.Errorf("dumped strings must be escaped, got:\n%s", out)
+	}
+	if !strings.Contains(out, "<pre>govar.testData =&gt; {") {
+		t.Errorf("expected a plain text dump, got:\n%s", out)
+	}
+	if !strings.Contains(out, `<pre class="govar"`) || !strings.Contains(out, "&lt;script&gt;") {
+		t.Errorf("expected an HTML dump, got:\n%s", out)
+	}
+	if strings.Contains(out, "[&gt;]") || strings.Contains(out, "[>]") {
+		t.Errorf("dumps in templates should not have a call site header, got:\n%s", out)
+	}
+}
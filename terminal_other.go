@@ -0,0 +1,11 @@
+//go:build !((linux || darwin || freebsd || netbsd || openbsd || dragonfly) && !tinygo)
+
+// Package govar provides a powerful and highly configurable pretty-printer for Go
+// data structures. This file is the fallback for targets where the terminal size
+// cannot be queried; AdaptiveInline then relies on the COLUMNS variable.
+package govar
+
+// ioctlTerminalWidth is not supported on this target and always returns 0.
+func ioctlTerminalWidth(fd uintptr) int {
+	return 0
+}
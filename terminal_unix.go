@@ -0,0 +1,22 @@
+//go:build (linux || darwin || freebsd || netbsd || openbsd || dragonfly) && !tinygo
+
+// Package govar provides a powerful and highly configurable pretty-printer for Go
+// data structures. This file queries the terminal size on Unix-like systems for
+// the AdaptiveInline option. See terminal_other.go for other targets.
+package govar
+
+import (
+	"syscall"
+	"unsafe"
+)
+
+// ioctlTerminalWidth returns the number of columns of the terminal behind the
+// file descriptor, or 0 if it is not a terminal.
+func ioctlTerminalWidth(fd uintptr) int {
+	var ws struct{ Row, Col, Xpixel, Ypixel uint16 }
+	_, _, errno := syscall.Syscall(syscall.SYS_IOCTL, fd, uintptr(syscall.TIOCGWINSZ), uintptr(unsafe.Pointer(&ws)))
+	if errno != 0 {
+		return 0
+	}
+	return int(ws.Col)
+}
@@ -0,0 +1,71 @@
+// Package govar provides a powerful and highly configurable pretty-printer for Go
+// data structures. This file implements stopwatch dumps: Trace prints a label
+// with the time elapsed since the previous Trace of the same goroutine before
+// the values, turning scattered dumps into a lightweight timing trace.
+package govar
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+)
+
+// maxTraceClocks bounds the number of goroutines with a running stopwatch.
+// Goroutines that exited never trace again, so without a bound their entries
+// would pile up in long-running programs.
+const maxTraceClocks = 4096
+
+var (
+	traceClockMu sync.Mutex
+	traceClock   = make(map[uint64]time.Time) // Time of the last Trace call, by goroutine ID.
+)
+
+// Trace prints a label with the time elapsed since the previous Trace call on
+// the same goroutine (e.g. "⏱ loaded +12.4ms"), followed by the values, to
+// stdout. The first Trace of a goroutine starts its stopwatch.
+func (d *Dumper) Trace(label string, vs ...any) {
+	d.Ftrace(stdoutWriter(), label, vs...)
+}
+
+// Ftrace is Trace writing to the given io.Writer.
+func (d *Dumper) Ftrace(w io.Writer, label string, vs ...any) {
+	now := time.Now()
+	d.Formatter = d.textFormatter(d.config.UseColors)
+	sb := &strings.Builder{}
+	d.renderHeader(sb)
+	sb.WriteString(d.traceLine(label, now))
+	d.renderAllValues(sb, vs...)
+	fmt.Fprintln(w, sb.String())
+}
+
+// traceLine returns the label line of a Trace call made at now, and restarts
+// the stopwatch of the calling goroutine.
+func (d *Dumper) traceLine(label string, now time.Time) string {
+	elapsed := "(start)"
+	if prev, ok := restartTraceClock(goroutineID(), now); ok {
+		elapsed = "+" + roundDuration(now.Sub(prev)).String()
+	}
+	return d.ApplyFormat(ColorGoBlue, "⏱ "+label) + " " + d.ApplyFormat(ColorGoldenrod, elapsed) + "\n"
+}
+
+// restartTraceClock sets the stopwatch of goroutine id to now and returns its
+// previous time, if any. Beyond maxTraceClocks stopwatches, the one idle the
+// longest is dropped.
+func restartTraceClock(id uint64, now time.Time) (prev time.Time, ok bool) {
+	traceClockMu.Lock()
+	defer traceClockMu.Unlock()
+	prev, ok = traceClock[id]
+	traceClock[id] = now
+	if !ok && len(traceClock) > maxTraceClocks {
+		oldest := id
+		for other, last := range traceClock {
+			if last.Before(traceClock[oldest]) {
+				oldest = other
+			}
+		}
+		delete(traceClock, oldest)
+	}
+	return prev, ok
+}
@@ -0,0 +1,47 @@
+package govar
+
+import (
+	"bytes"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTrace(t *testing.T) {
+	cfg := DefaultConfig
+	cfg.UseColors = false
+	cfg.EmbedTypeMethods = false
+	d := NewDumper(cfg)
+
+	done := make(chan string)
+	go func() {
+		buf := &bytes.Buffer{}
+		d.Ftrace(buf, "start", 1)
+		d.Ftrace(buf, "loaded")
+		done <- buf.String()
+	}()
+	out := <-done
+
+	if !strings.Contains(out, "⏱ start (start)\nint => 1\n") {
+		t.Errorf("expected the first trace to start the stopwatch, got:\n%s", out)
+	}
+	if !regexp.MustCompile(`⏱ loaded \+[0-9.]+[µnm]?s\n`).MatchString(out) {
+		t.Errorf("expected the elapsed time since the first trace, got:\n%s", out)
+	}
+}
+
+func TestTraceClockBound(t *testing.T) {
+	start := time.Now()
+	for id := range uint64(maxTraceClocks + 10) {
+		restartTraceClock(1<<62+id, start.Add(time.Duration(id)))
+	}
+	traceClockMu.Lock()
+	defer traceClockMu.Unlock()
+	if len(traceClock) > maxTraceClocks {
+		t.Errorf("expected at most %d stopwatches, got %d", maxTraceClocks, len(traceClock))
+	}
+	if _, ok := traceClock[1<<62]; ok {
+		t.Error("expected the stopwatch idle the longest to be dropped")
+	}
+}
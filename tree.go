@@ -0,0 +1,293 @@
+// Package govar provides a powerful and highly configurable pretty-printer for Go
+// data structures. This file builds the structured render tree, a
+// serializable representation of a dump used by the interactive HTML output
+// and by tools that want to post-process dumps instead of parsing text.
+package govar
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Node is a single element of the structured render tree. Composite values
+// (structs, slices, arrays, maps) carry their elements in Children, while all
+// other values carry their plain-text rendering in Value. Stringer and error
+// values rendered with StringerWithFields carry both.
+type Node struct {
+	Key       string  `json:"key,omitempty"`       // Field name, index, or formatted map key.
+	Type      string  `json:"type,omitempty"`      // Display name of the value's type.
+	Kind      string  `json:"kind"`                // The reflect.Kind of the value (after dereferencing).
+	Value     string  `json:"value,omitempty"`     // Plain-text rendering of non-composite values.
+	ID        string  `json:"id,omitempty"`        // Reference ID (e.g. "&1") defined at this node.
+	Ref       string  `json:"ref,omitempty"`       // Reference ID this node points back to.
+	Truncated bool    `json:"truncated,omitempty"` // True if children were cut by MaxItems, MaxFields or MaxDepth.
+	Children  []*Node `json:"children,omitempty"`  // Elements of composite values.
+}
+
+// Tree analyzes the given values exactly like Sdump does and returns one
+// structured render tree per value. Rendered scalar values never contain colors.
+func (d *Dumper) Tree(vs ...any) []*Node {
+	d.Formatter = &PlainFormatter{}
+	addressableVars := d.analyzeValues(vs...)
+
+	nodes := make([]*Node, len(addressableVars))
+	for i, v := range addressableVars {
+		if vs[i] == nil {
+			nodes[i] = &Node{Type: "unknown", Kind: "invalid", Value: "<nil>"}
+			continue
+		}
+		nodes[i] = d.buildNode(v, "", 0, false)
+	}
+	return nodes
+}
+
+// buildNode is the tree counterpart of renderValue. It follows the same rules
+// for depth limits, nil values, references, and Stringer/error formatting.
+func (d *Dumper) buildNode(v reflect.Value, key string, level int, skipRefCheck bool) *Node {
+	node := &Node{Key: key}
+	if !v.IsValid() {
+		node.Kind, node.Value = "invalid", "<invalid>"
+		return node
+	}
+	node.Type = strings.ReplaceAll(d.typeName(v.Type()), "interface {}", "any")
+	node.Kind = v.Kind().String()
+
+	if level > d.config.MaxDepth {
+		node.Value, node.Truncated = "… (max depth reached)", true
+		return node
+	}
+	if d.needsFreshStack(level) {
+		d.onFreshStack(level, func() { node = d.buildNode(v, key, level, skipRefCheck) })
+		return node
+	}
+	if isNil(v) {
+		node.Value = d.nilLabel(v)
+		return node
+	}
+	if v.Kind() == reflect.Interface && isNil(v.Elem()) {
+		node.Value = d.typedNilLabel(v)
+		return node
+	}
+
+	if d.config.TrackReferences && !skipRefCheck {
+		if id, isBackref := d.resolveReference(v); id != "" {
+			if isBackref {
+				node.Ref = id
+				return node
+			}
+			node.ID = id
+		}
+	}
+
+	if stack, ok := asStack(v); ok {
+		for i, frame := range stack {
+			if i >= d.config.MaxItems {
+				node.Truncated = true
+				break
+			}
+			node.Children = append(node.Children, &Node{
+				Key:   strconv.Itoa(i),
+				Type:  d.typeName(reflect.TypeOf(frame)),
+				Kind:  "struct",
+				Value: d.formatStackFrame(frame),
+			})
+		}
+		return node
+	}
+	if target, ok := handleTarget(v); ok {
+		if !target.IsValid() {
+			node.Value = "<collected>"
+			return node
+		}
+		inner := d.buildNode(target, key, level, false)
+		inner.Type, inner.Kind = node.Type, node.Kind
+		return inner
+	}
+	if summary := d.heavyTypeSummary(v); summary != "" {
+		node.Value = summary
+		return node
+	}
+
+	exportedV := tryExport(v)
+	if exportedV.Kind() != reflect.Interface && d.useStringer(exportedV.Type()) {
+		if str := d.asStringerInterface(exportedV); str != "" {
+			node.Value = str
+			if s, ok := d.stringerFields(exportedV); ok {
+				d.buildStructChildren(node, s, level)
+			}
+			return node
+		}
+		if errs := d.joinedErrors(exportedV); errs != nil {
+			for i, err := range errs {
+				node.Children = append(node.Children, d.buildNode(reflect.ValueOf(&err).Elem(), strconv.Itoa(i), level+1, false))
+			}
+			return node
+		}
+		if str := d.asErrorInterface(exportedV); str != "" {
+			node.Value = str
+			if s, ok := d.stringerFields(exportedV); ok {
+				d.buildStructChildren(node, s, level)
+			} else if s, exported := d.errorFields(exportedV); len(exported) > 0 {
+				d.buildStructChildren(node, s, level)
+				node.Children = exportedChildren(node.Children, s)
+			}
+			return node
+		}
+		if str := d.asFormatterInterface(exportedV); str != "" {
+			node.Value = str
+			return node
+		}
+		if str := d.asTextMarshalerInterface(exportedV); str != "" {
+			node.Value = str
+			return node
+		}
+		if str := d.asJSONMarshalerInterface(exportedV, 0); str != "" {
+			node.Value = str
+			return node
+		}
+	}
+
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		// Pointers and interfaces are transparent: the node describes the target
+		// value but keeps the declared type and any ID defined at this level.
+		target := d.buildNode(v.Elem(), key, level, true)
+		target.Type = node.Type
+		if v.Kind() == reflect.Interface {
+			target.Type = d.interfaceTypeName(v, node.Type)
+		}
+		if node.ID != "" {
+			target.ID = node.ID
+		}
+		return target
+	case reflect.Struct:
+		d.buildStructChildren(node, v, level)
+	case reflect.Slice, reflect.Array:
+		for i := range v.Len() {
+			if i >= d.config.MaxItems {
+				node.Truncated = true
+				break
+			}
+			node.Children = append(node.Children, d.buildNode(v.Index(i), strconv.Itoa(i), level+1, false))
+		}
+	case reflect.Map:
+		for i, mapKey := range d.sortedMapKeys(v) {
+			if i >= d.config.MaxItems {
+				node.Truncated = true
+				break
+			}
+			node.Children = append(node.Children, d.buildNode(v.MapIndex(mapKey), d.formatMapKeyAsIndex(mapKey), level+1, false))
+		}
+		if d.isSampledMap(v) {
+			node.Truncated = true
+		}
+	case reflect.Func:
+		node.Value = d.formatFunc(v)
+	case reflect.Chan:
+		node.Value = d.formatChan(v)
+	case reflect.UnsafePointer:
+		sb := &strings.Builder{}
+		d.renderKind(sb, v, level)
+		node.Value = sb.String()
+	default:
+		node.Value = d.renderPrimitive(v)
+	}
+	return node
+}
+
+// buildStructChildren appends one child node per field of the struct v.
+func (d *Dumper) buildStructChildren(node *Node, v reflect.Value, level int) {
+	t := v.Type()
+	fields, hidden := d.shownFields(t)
+	if hidden > 0 {
+		node.Truncated = true
+	}
+	for _, i := range fields {
+		fieldVal := v.Field(i)
+		if !t.Field(i).IsExported() {
+			fieldVal = tryExport(fieldVal)
+		}
+		if mask := d.fieldMask(t.Field(i)); mask != MaskNone {
+			node.Children = append(node.Children, &Node{
+				Key:   t.Field(i).Name,
+				Type:  d.typeName(fieldVal.Type()),
+				Kind:  fieldVal.Kind().String(),
+				Value: strconv.Quote(maskValue(fieldVal, mask)),
+			})
+			continue
+		}
+		child := d.buildNode(fieldVal, t.Field(i).Name, level+1, false)
+		if str, ok := d.formatFieldValue(fieldVal, parseFieldOptions(t.Field(i).Tag).format); ok {
+			child.Value = str
+		}
+		if hint := d.fieldHint(t.Field(i), fieldVal); hint != "" && child.Value != "" {
+			child.Value += " |" + hint + "|"
+		}
+		node.Children = append(node.Children, child)
+	}
+}
+
+// exportedChildren keeps the child nodes of the exported fields of struct v.
+func exportedChildren(children []*Node, v reflect.Value) []*Node {
+	var kept []*Node
+	for _, child := range children {
+		if field, ok := v.Type().FieldByName(child.Key); ok && field.IsExported() {
+			kept = append(kept, child)
+		}
+	}
+	return kept
+}
+
+// WriteTree renders structured render trees as indented plain text, one node per
+// line. It is used to re-render trees that were serialized by another process.
+func WriteTree(w io.Writer, nodes []*Node, indentWidth int) error {
+	for _, node := range nodes {
+		if err := writeTreeNode(w, node, 0, indentWidth); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeTreeNode writes a single node and, recursively, its children.
+func writeTreeNode(w io.Writer, node *Node, level, indentWidth int) error {
+	line := &strings.Builder{}
+	line.WriteString(strings.Repeat(" ", level*indentWidth))
+	if node.Key != "" {
+		line.WriteString(node.Key + " ")
+	}
+	if node.Type != "" {
+		line.WriteString(node.Type + " ")
+	}
+	line.WriteString("=> ")
+	if node.ID != "" {
+		line.WriteString(node.ID + " ")
+	}
+	switch {
+	case node.Ref != "":
+		line.WriteString("↩︎ " + node.Ref)
+	case node.Children != nil:
+		if node.Value != "" {
+			line.WriteString(node.Value + " ")
+		}
+		line.WriteString(fmt.Sprintf("|%d|", len(node.Children)))
+	default:
+		line.WriteString(node.Value)
+	}
+	if _, err := fmt.Fprintln(w, line.String()); err != nil {
+		return err
+	}
+	for _, child := range node.Children {
+		if err := writeTreeNode(w, child, level+1, indentWidth); err != nil {
+			return err
+		}
+	}
+	if node.Truncated && node.Children != nil {
+		_, err := fmt.Fprintln(w, strings.Repeat(" ", (level+1)*indentWidth)+"… (truncated)")
+		return err
+	}
+	return nil
+}
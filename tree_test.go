@@ -0,0 +1,44 @@
+package govar
+
+import (
+	"testing"
+)
+
+func TestTree(t *testing.T) {
+	type Node struct {
+		Name string
+		Next *Node
+		Tags map[string]int
+	}
+	a := &Node{Name: "a", Tags: map[string]int{"x": 1}}
+	b := &Node{Name: "b", Next: a}
+	a.Next = b
+
+	cfg := DefaultConfig
+	cfg.UseColors = true // the tree must stay colorless regardless
+	nodes := NewDumper(cfg).Tree(a, nil)
+
+	if len(nodes) != 2 {
+		t.Fatalf("Tree() returned %d nodes, want 2", len(nodes))
+	}
+	root := nodes[0]
+	if root.Type != "*govar.Node" || root.Kind != "struct" || root.ID == "" {
+		t.Errorf("unexpected root node: %+v", root)
+	}
+	if len(root.Children) != 3 || root.Children[0].Key != "Name" || root.Children[0].Value != `|R:1| "a"` {
+		t.Fatalf("unexpected root children: %+v", root.Children)
+	}
+	next := root.Children[1]
+	if next.Key != "Next" || len(next.Children) != 3 {
+		t.Fatalf("unexpected Next node: %+v", next)
+	}
+	if backref := next.Children[1]; backref.Ref != root.ID {
+		t.Errorf("cycle should end in a back-reference to %s, got %+v", root.ID, backref)
+	}
+	if tags := root.Children[2]; len(tags.Children) != 1 || tags.Children[0].Key != `"x"` || tags.Children[0].Value != "1" {
+		t.Errorf("unexpected map node: %+v", tags)
+	}
+	if nodes[1].Value != "<nil>" {
+		t.Errorf("nil top-level value should render as <nil>, got %+v", nodes[1])
+	}
+}
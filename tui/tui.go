@@ -0,0 +1,265 @@
+// Package tui provides a small terminal browser for govar dumps. It presents
+// the structured render tree of one or more values (or of dump records read
+// from a stream) as a collapsible, searchable tree driven by short commands,
+// so it works in any terminal without raw-mode input handling.
+package tui
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/janvaclavik/govar"
+)
+
+// helpText lists the commands understood by Viewer.Run.
+const helpText = `commands:
+  <n>        expand/collapse node number n
+  e / c      expand all / collapse all
+  /<text>    search keys, types and values (empty query clears)
+  ?          show this help
+  q          quit`
+
+// Viewer is an interactive browser over structured render trees.
+type Viewer struct {
+	roots     []*govar.Node
+	expanded  map[*govar.Node]bool
+	matches   map[*govar.Node]bool
+	formatter govar.Formatter
+	useColors bool
+}
+
+// line is one visible row of the tree.
+type line struct {
+	node  *govar.Node
+	depth int
+}
+
+// New creates a viewer over the given trees. The top two levels start expanded.
+func New(roots []*govar.Node, useColors bool) *Viewer {
+	v := &Viewer{
+		roots:     roots,
+		expanded:  make(map[*govar.Node]bool),
+		matches:   make(map[*govar.Node]bool),
+		formatter: &govar.PlainFormatter{},
+		useColors: useColors,
+	}
+	if useColors {
+		v.formatter = &govar.ANSIcolorFormatter{Palette: govar.DetectPalette()}
+	}
+	for _, root := range roots {
+		v.expanded[root] = true
+		for _, child := range root.Children {
+			v.expanded[child] = len(child.Children) > 0 && len(root.Children) <= 10
+		}
+	}
+	return v
+}
+
+// Browse dumps the values with the DefaultConfig and browses them on the terminal.
+func Browse(values ...any) error {
+	nodes := govar.NewDumper(govar.DefaultConfig).Tree(values...)
+	return New(nodes, true).Run(os.Stdin, os.Stdout)
+}
+
+// ReadRecords decodes a stream of JSON-encoded govar.Record values, as written by
+// the remote sink or the NDJSON log, and returns the trees they carry.
+func ReadRecords(r io.Reader) ([]*govar.Node, error) {
+	var roots []*govar.Node
+	dec := json.NewDecoder(r)
+	for {
+		var rec govar.Record
+		if err := dec.Decode(&rec); err == io.EOF {
+			return roots, nil
+		} else if err != nil {
+			return roots, fmt.Errorf("tui: decode record: %w", err)
+		}
+		if len(rec.Tree) == 0 {
+			continue
+		}
+		// Group the values of one record under a node naming its call site.
+		roots = append(roots, &govar.Node{
+			Key:      fmt.Sprintf("%s:%d", rec.File, rec.Line),
+			Type:     rec.Func,
+			Kind:     "record",
+			Children: rec.Tree,
+		})
+	}
+}
+
+// Run renders the tree to out and processes commands read from in until "q" or EOF.
+func (v *Viewer) Run(in io.Reader, out io.Writer) error {
+	scanner := bufio.NewScanner(in)
+	v.Render(out)
+	for {
+		fmt.Fprint(out, "govar> ")
+		if !scanner.Scan() {
+			fmt.Fprintln(out)
+			return scanner.Err()
+		}
+		cmd := strings.TrimSpace(scanner.Text())
+		switch {
+		case cmd == "q":
+			return nil
+		case cmd == "?":
+			fmt.Fprintln(out, helpText)
+			continue
+		case cmd == "e":
+			v.ExpandAll()
+		case cmd == "c":
+			v.CollapseAll()
+		case strings.HasPrefix(cmd, "/"):
+			found := v.Search(cmd[1:])
+			if cmd != "/" {
+				fmt.Fprintf(out, "%d match(es)\n", found)
+			}
+		default:
+			n, err := strconv.Atoi(cmd)
+			if err != nil || !v.Toggle(n) {
+				fmt.Fprintf(out, "unknown command %q (type ? for help)\n", cmd)
+				continue
+			}
+		}
+		v.Render(out)
+	}
+}
+
+// Render writes the currently visible part of the tree to out, numbering each row.
+func (v *Viewer) Render(out io.Writer) {
+	for i, l := range v.visibleLines() {
+		fmt.Fprintf(out, "%4d %s%s\n", i+1, strings.Repeat("   ", l.depth), v.label(l.node))
+	}
+}
+
+// Toggle expands or collapses the n-th visible row (1-based). It reports false if
+// the row does not exist or has no children.
+func (v *Viewer) Toggle(n int) bool {
+	lines := v.visibleLines()
+	if n < 1 || n > len(lines) || len(lines[n-1].node.Children) == 0 {
+		return false
+	}
+	node := lines[n-1].node
+	v.expanded[node] = !v.expanded[node]
+	return true
+}
+
+// ExpandAll expands every node of the tree.
+func (v *Viewer) ExpandAll() {
+	walk(v.roots, func(n *govar.Node, _ []*govar.Node) { v.expanded[n] = true })
+}
+
+// CollapseAll collapses every node below the roots.
+func (v *Viewer) CollapseAll() {
+	v.expanded = make(map[*govar.Node]bool)
+	for _, root := range v.roots {
+		v.expanded[root] = true
+	}
+}
+
+// Search highlights all nodes whose key, type or value contains query (case
+// insensitive) and expands their ancestors. It returns the number of matches.
+func (v *Viewer) Search(query string) int {
+	v.matches = make(map[*govar.Node]bool)
+	query = strings.ToLower(query)
+	if query == "" {
+		return 0
+	}
+	walk(v.roots, func(n *govar.Node, ancestors []*govar.Node) {
+		text := strings.ToLower(n.Key + " " + n.Type + " " + n.Value)
+		if strings.Contains(text, query) {
+			v.matches[n] = true
+			for _, a := range ancestors {
+				v.expanded[a] = true
+			}
+		}
+	})
+	return len(v.matches)
+}
+
+// visibleLines flattens the expanded part of the tree.
+func (v *Viewer) visibleLines() []line {
+	var lines []line
+	var visit func(n *govar.Node, depth int)
+	visit = func(n *govar.Node, depth int) {
+		lines = append(lines, line{n, depth})
+		if v.expanded[n] {
+			for _, child := range n.Children {
+				visit(child, depth+1)
+			}
+		}
+	}
+	for _, root := range v.roots {
+		visit(root, 0)
+	}
+	return lines
+}
+
+// label formats a single node using govar's color theme.
+func (v *Viewer) label(n *govar.Node) string {
+	sb := &strings.Builder{}
+	switch {
+	case len(n.Children) == 0:
+		sb.WriteString("  ")
+	case v.expanded[n]:
+		sb.WriteString(v.formatter.ApplyFormat(govar.ColorDarkTeal, "▾ "))
+	default:
+		sb.WriteString(v.formatter.ApplyFormat(govar.ColorDarkTeal, "▸ "))
+	}
+	if n.Key != "" {
+		key := v.formatter.ApplyFormat(govar.ColorLightTeal, n.Key)
+		if v.matches[n] {
+			key = v.highlight(n.Key)
+		}
+		sb.WriteString(key + " ")
+	}
+	if n.Type != "" {
+		sb.WriteString(v.formatter.ApplyFormat(govar.ColorDarkGray, n.Type) + " ")
+	}
+	sb.WriteString("=> ")
+	if n.ID != "" {
+		sb.WriteString(v.formatter.ApplyFormat(govar.ColorGoldenrod, n.ID) + " ")
+	}
+	switch {
+	case n.Ref != "":
+		sb.WriteString(v.formatter.ApplyFormat(govar.ColorPink, "↩︎ "+n.Ref))
+	case n.Children != nil:
+		sb.WriteString(v.formatter.ApplyFormat(govar.ColorDimGray, fmt.Sprintf("|%d|", len(n.Children))))
+	case v.matches[n]:
+		sb.WriteString(v.highlight(n.Value))
+	case n.Kind == "string":
+		sb.WriteString(v.formatter.ApplyFormat(govar.ColorLime, n.Value))
+	default:
+		sb.WriteString(v.formatter.ApplyFormat(govar.ColorSkyBlue, n.Value))
+	}
+	if v.matches[n] && !v.useColors {
+		sb.WriteString("  ◀")
+	}
+	return sb.String()
+}
+
+// highlight renders a search hit in inverse video (or unchanged without colors).
+func (v *Viewer) highlight(s string) string {
+	if !v.useColors {
+		return s
+	}
+	return "\033[7m" + s + govar.ColorReset
+}
+
+// walk visits all nodes depth-first, passing the chain of ancestors of each node.
+func walk(roots []*govar.Node, fn func(n *govar.Node, ancestors []*govar.Node)) {
+	var visit func(n *govar.Node, ancestors []*govar.Node)
+	visit = func(n *govar.Node, ancestors []*govar.Node) {
+		fn(n, ancestors)
+		ancestors = append(ancestors, n)
+		for _, child := range n.Children {
+			visit(child, ancestors)
+		}
+	}
+	for _, root := range roots {
+		visit(root, nil)
+	}
+}
@@ -0,0 +1,57 @@
+package tui
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/janvaclavik/govar"
+)
+
+type address struct {
+	City string
+	Zip  string
+}
+
+type person struct {
+	Name    string
+	Address address
+	Friends []string
+}
+
+func TestViewerRun(t *testing.T) {
+	cfg := govar.DefaultConfig
+	cfg.UseColors = false
+	nodes := govar.NewDumper(cfg).Tree(person{Name: "Ada", Address: address{City: "London"}, Friends: []string{"Charles"}})
+
+	var out bytes.Buffer
+	v := New(nodes, false)
+	if err := v.Run(strings.NewReader("c\n/london\nq\n"), &out); err != nil {
+		t.Fatalf("Run error: %v", err)
+	}
+
+	got := out.String()
+	for _, want := range []string{
+		"1 ▾ tui.person => |3|",
+		"▸ Address tui.address => |2|",
+		"1 match(es)",
+		`City string => |R:6| "London"  ◀`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("Run output missing %q, got:\n%s", want, got)
+		}
+	}
+}
+
+func TestReadRecords(t *testing.T) {
+	input := `{"time":"2026-01-02T03:04:05Z","file":"main.go","line":12,"func":"govar.Dump","tree":[{"type":"int","kind":"int","value":"1"}]}
+{"time":"2026-01-02T03:04:06Z","file":"main.go","line":13,"func":"govar.Dump","text":"no tree"}
+`
+	roots, err := ReadRecords(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("ReadRecords error: %v", err)
+	}
+	if len(roots) != 1 || roots[0].Key != "main.go:12" || len(roots[0].Children) != 1 {
+		t.Errorf("unexpected roots: %+v", roots)
+	}
+}
@@ -0,0 +1,44 @@
+// Package govar provides a powerful and highly configurable pretty-printer for Go
+// data structures. This file caches data computed per reflect.Type (method sets
+// and simple-struct checks), which would otherwise be recomputed for every
+// element of homogeneous slices and maps.
+package govar
+
+import (
+	"reflect"
+	"sync"
+)
+
+var (
+	typeMethodsCache  sync.Map // reflect.Type -> []reflect.Method
+	simpleFieldsCache sync.Map // reflect.Type -> bool
+)
+
+// findTypeMethods returns all exported methods associated with the given
+// named reflect.Type, considering both value and pointer receivers. Results
+// are cached per type and shared, so callers must not modify them.
+func findTypeMethods(typ reflect.Type) []reflect.Method {
+	if cached, ok := typeMethodsCache.Load(typ); ok {
+		return cached.([]reflect.Method)
+	}
+	methods := collectTypeMethods(typ)
+	typeMethodsCache.Store(typ, methods)
+	return methods
+}
+
+// hasOnlySimpleFields reports whether every field of the struct type t is a
+// primitive (see isSimpleValue). The result is cached per type.
+func hasOnlySimpleFields(t reflect.Type) bool {
+	if cached, ok := simpleFieldsCache.Load(t); ok {
+		return cached.(bool)
+	}
+	simple := true
+	for i := range t.NumField() {
+		if !isSimpleValue(reflect.Zero(t.Field(i).Type)) {
+			simple = false
+			break
+		}
+	}
+	simpleFieldsCache.Store(t, simple)
+	return simple
+}
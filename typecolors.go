@@ -0,0 +1,70 @@
+// Package govar provides a powerful and highly configurable pretty-printer for Go
+// data structures. This file implements the TypeColors option: values of the
+// listed types, and their type names, are rendered in a color of their own, so
+// they are easy to trace across a large dump.
+package govar
+
+import (
+	"path"
+	"reflect"
+	"sort"
+)
+
+// typeColor returns the color TypeColors assigns to type t, or "" if there is
+// none. Keys are compared with the type name as rendered and with the name
+// qualified by the full import path; exact names take precedence over
+// path.Match patterns, which are tried in lexical order.
+func (d *Dumper) typeColor(t reflect.Type) string {
+	if len(d.config.TypeColors) == 0 {
+		return ""
+	}
+	if color, ok := d.typeColors[t]; ok {
+		return color
+	}
+	names := []string{d.typeName(t)}
+	if t.Name() != "" && t.PkgPath() != "" {
+		names = append(names, t.PkgPath()+"."+t.Name())
+	}
+	color := ""
+	for _, name := range names {
+		if c, ok := d.config.TypeColors[name]; ok {
+			color = c
+			break
+		}
+	}
+	if color == "" {
+		patterns := make([]string, 0, len(d.config.TypeColors))
+		for pattern := range d.config.TypeColors {
+			patterns = append(patterns, pattern)
+		}
+		sort.Strings(patterns)
+	search:
+		for _, pattern := range patterns {
+			for _, name := range names {
+				if ok, _ := path.Match(pattern, name); ok {
+					color = d.config.TypeColors[pattern]
+					break search
+				}
+			}
+		}
+	}
+	d.typeColors[t] = color
+	return color
+}
+
+// useTypeColor makes ApplyFormat render everything in the TypeColors color of
+// v's type, if it has one, until the returned function is called. Colors of
+// enclosing values are replaced, so a mapped type nested in another one keeps
+// its own color.
+func (d *Dumper) useTypeColor(v reflect.Value) func() {
+	if !v.IsValid() {
+		return func() {}
+	}
+	color := d.typeColor(v.Type())
+	if color == "" {
+		return func() {}
+	}
+	previous := d.colorOverride
+	d.colorOverride = color
+	return func() { d.colorOverride = previous }
+}
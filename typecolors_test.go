@@ -0,0 +1,37 @@
+package govar
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTypeColors(t *testing.T) {
+	type Job struct {
+		Timeout time.Duration
+		Retries int
+	}
+	cfg := DefaultConfig
+	cfg.EmbedTypeMethods = false
+	cfg.ShowMetaInformation = false
+	cfg.Palette = PaletteDark
+	cfg.TypeColors = map[string]string{"time.*": ColorPink}
+
+	out := NewDumper(cfg).Sdump(Job{Timeout: time.Second, Retries: 3})
+	if !strings.Contains(out, ColorPink+"time.Duration"+ColorReset) {
+		t.Errorf("expected the type name in the mapped color, got %q", out)
+	}
+	if !strings.Contains(out, ColorPink+"1s"+ColorReset) {
+		t.Errorf("expected the value in the mapped color, got %q", out)
+	}
+	if !strings.Contains(out, ColorSkyBlue+"3"+ColorReset) {
+		t.Errorf("expected other values to keep their colors, got %q", out)
+	}
+
+	cfg.TypeColors = map[string]string{"time.*": ColorPink, "time.Duration": ColorGreen}
+	d := NewDumper(cfg)
+	if color := d.typeColor(reflect.TypeOf(time.Second)); color != ColorGreen {
+		t.Errorf("expected an exact name to take precedence over patterns, got %q", color)
+	}
+}
@@ -0,0 +1,244 @@
+// Package govar provides a powerful and highly configurable pretty-printer for Go
+// data structures. This file implements type dumps, which render only the
+// structure of a type (fields, field types, struct tags, and method sets)
+// without any values, as a readable schema view.
+package govar
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"unicode/utf8"
+)
+
+// DumpType prints the type structure of the given values to stdout.
+func (d *Dumper) DumpType(vs ...any) {
+	fmt.Fprintln(stdoutWriter(), d.sdumpTypes(d.config.UseColors, typesOf(vs)...))
+}
+
+// SdumpType returns the type structure of the given values as a string.
+func (d *Dumper) SdumpType(vs ...any) string {
+	return d.sdumpTypes(d.config.UseColors, typesOf(vs)...)
+}
+
+// typesOf returns the dynamic types of the given values.
+func typesOf(vs []any) []reflect.Type {
+	types := make([]reflect.Type, len(vs))
+	for i, v := range vs {
+		types[i] = reflect.TypeOf(v)
+	}
+	return types
+}
+
+// sdumpTypes renders the header followed by the structure of every type.
+func (d *Dumper) sdumpTypes(useColors bool, types ...reflect.Type) string {
+	d.Formatter = d.textFormatter(useColors)
+	sb := &strings.Builder{}
+	d.renderHeader(sb)
+	for i, t := range types {
+		if i > 0 {
+			sb.WriteString("\n")
+		}
+		if t == nil {
+			fmt.Fprintln(sb, d.ApplyFormat(ColorDarkGray, "unknown"))
+			continue
+		}
+		referenced := map[reflect.Type]bool{}
+		d.renderTypeBody(&strings.Builder{}, t, 0, newTypeDumpState(referenced))
+		sb.WriteString(d.ApplyFormat(ColorDarkGray, d.typeName(t)))
+		d.renderTypeBody(sb, t, 0, newTypeDumpState(referenced))
+		fmt.Fprintln(sb)
+	}
+	return sb.String()
+}
+
+// typeDumpState tracks the types being expanded while rendering a type dump.
+// Dumps are rendered twice: the first pass finds the types that are referred
+// back to, so the second pass only assigns IDs to those.
+type typeDumpState struct {
+	onPath     map[reflect.Type]bool   // Types whose body is currently being rendered.
+	expanded   map[reflect.Type]bool   // Types whose body has been rendered.
+	referenced map[reflect.Type]bool   // Types referred back to, as found by the first pass.
+	ids        map[reflect.Type]string // Reference IDs assigned to referenced types.
+}
+
+// newTypeDumpState returns the state for a pass, keeping the referenced types
+// found by a previous pass.
+func newTypeDumpState(referenced map[reflect.Type]bool) *typeDumpState {
+	return &typeDumpState{
+		onPath:     map[reflect.Type]bool{},
+		expanded:   map[reflect.Type]bool{},
+		referenced: referenced,
+		ids:        map[reflect.Type]string{},
+	}
+}
+
+// renderTypeBody writes the fields and methods of the struct, interface, or
+// named type behind t (following pointer, slice, array, map, and channel
+// element types) as an indented block, recursively expanding the named types it
+// refers to up to MaxDepth. Named types defined as another type are annotated
+// with their underlying type. Standard library types are only expanded at the
+// top level. With TrackReferences, each type is expanded once and later
+// occurrences refer back to it by ID ("↩︎ &1"); otherwise only recursive types
+// are cut short.
+func (d *Dumper) renderTypeBody(sb *strings.Builder, t reflect.Type, level int, state *typeDumpState) {
+	if underlying := underlyingTypeName(t); underlying != "" {
+		sb.WriteString(" " + d.ApplyFormat(ColorSlateGray, "("+underlying+")"))
+	}
+	for t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice || t.Kind() == reflect.Array || t.Kind() == reflect.Map || t.Kind() == reflect.Chan {
+		t = t.Elem()
+	}
+	methods := d.typeDumpMethods(t)
+	hasFields := t.Kind() == reflect.Struct && t.NumField() > 0
+	if !hasFields && len(methods) == 0 {
+		return
+	}
+	if state.onPath[t] || (d.config.TrackReferences && state.expanded[t]) {
+		state.referenced[t] = true
+		sb.WriteString(" " + d.ApplyFormat(ColorSlateGray, strings.TrimSpace("↩︎ "+state.ids[t])))
+		return
+	}
+	if level > 0 && (level >= d.config.MaxDepth || isStandardLibraryType(t)) {
+		sb.WriteString(" " + d.ApplyFormat(ColorSlateGray, "{…}"))
+		return
+	}
+	state.expanded[t] = true
+	if state.referenced[t] {
+		id := fmt.Sprintf("&%d", len(state.ids)+1)
+		state.ids[t] = id
+		sb.WriteString(" " + d.ApplyFormat(ColorGoldenrod, id))
+	}
+	state.onPath[t] = true
+	defer delete(state.onPath, t)
+
+	maxKeyLen, maxTypeLen := 0, 0
+	if hasFields {
+		for i := range t.NumField() {
+			maxKeyLen = max(maxKeyLen, utf8.RuneCountInString(t.Field(i).Name))
+			maxTypeLen = max(maxTypeLen, utf8.RuneCountInString(d.typeName(t.Field(i).Type)))
+		}
+	}
+	for _, m := range methods {
+		maxKeyLen = max(maxKeyLen, utf8.RuneCountInString(m.name))
+	}
+
+	fmt.Fprintln(sb, " {")
+	if hasFields {
+		for i := range t.NumField() {
+			field := t.Field(i)
+			symbol := "⯀ "
+			if !field.IsExported() {
+				symbol = "🞏 "
+			}
+			typeName := d.typeName(field.Type)
+			d.renderIndent(sb, level+1, d.ApplyFormat(ColorDarkGoBlue, symbol)+
+				padRight(d.ApplyFormat(ColorLightTeal, field.Name), utf8.RuneCountInString(field.Name), maxKeyLen)+"  ")
+			line := padRight(d.ApplyFormat(ColorDarkGray, typeName), utf8.RuneCountInString(typeName), maxTypeLen)
+			if field.Tag != "" {
+				line += "  " + d.ApplyFormat(ColorSlateGray, "`"+string(field.Tag)+"`")
+			}
+			sb.WriteString(strings.TrimRight(line, " "))
+			d.renderTypeBody(sb, field.Type, level+1, state)
+			fmt.Fprintln(sb)
+		}
+	}
+	for _, m := range methods {
+		d.renderIndent(sb, level+1, d.ApplyFormat(ColorDarkTeal, "⦿ ")+
+			padRight(d.ApplyFormat(ColorMutedBlue, m.name), utf8.RuneCountInString(m.name), maxKeyLen)+"  "+
+			d.ApplyFormat(ColorDarkGray, m.signature))
+		fmt.Fprintln(sb)
+	}
+	d.renderIndent(sb, level, "}")
+}
+
+// underlyingTypeName returns the underlying type of a named type that is not a
+// struct or interface (e.g. "int" for `type Status int`), or "" otherwise.
+func underlyingTypeName(t reflect.Type) string {
+	if t.Name() == "" {
+		return ""
+	}
+	var underlying reflect.Type
+	switch t.Kind() {
+	case reflect.Struct, reflect.Interface, reflect.Func, reflect.UnsafePointer:
+		return ""
+	case reflect.Ptr:
+		underlying = reflect.PointerTo(t.Elem())
+	case reflect.Slice:
+		underlying = reflect.SliceOf(t.Elem())
+	case reflect.Array:
+		underlying = reflect.ArrayOf(t.Len(), t.Elem())
+	case reflect.Map:
+		underlying = reflect.MapOf(t.Key(), t.Elem())
+	case reflect.Chan:
+		underlying = reflect.ChanOf(t.ChanDir(), t.Elem())
+	default:
+		if t.String() == t.Kind().String() {
+			return "" // A predeclared type such as int.
+		}
+		return t.Kind().String()
+	}
+	return underlying.String()
+}
+
+// typeDumpMethod is a method listed in a type dump.
+type typeDumpMethod struct {
+	name      string
+	signature string
+}
+
+// typeDumpMethods returns the method set of an interface type, or of a named
+// type (including pointer receiver methods) when EmbedTypeMethods is enabled.
+func (d *Dumper) typeDumpMethods(t reflect.Type) []typeDumpMethod {
+	var methods []typeDumpMethod
+	if t.Kind() == reflect.Interface {
+		for i := range t.NumMethod() {
+			m := t.Method(i)
+			methods = append(methods, typeDumpMethod{m.Name, d.funcSignature(m.Type, 0)})
+		}
+		return methods
+	}
+	if !d.config.EmbedTypeMethods {
+		return nil
+	}
+	for _, m := range findTypeMethods(t) {
+		methods = append(methods, typeDumpMethod{m.Name, d.funcSignature(m.Type, 1)})
+	}
+	return methods
+}
+
+// funcSignature renders a function type as "func(int, string) error", leaving
+// out the first skip parameters (the receiver of a method expression).
+func (d *Dumper) funcSignature(t reflect.Type, skip int) string {
+	in := make([]string, 0, t.NumIn())
+	for i := skip; i < t.NumIn(); i++ {
+		name := d.typeName(t.In(i))
+		if t.IsVariadic() && i == t.NumIn()-1 {
+			name = "..." + strings.TrimPrefix(name, "[]")
+		}
+		in = append(in, name)
+	}
+	out := make([]string, 0, t.NumOut())
+	for i := range t.NumOut() {
+		out = append(out, d.typeName(t.Out(i)))
+	}
+	sig := "func(" + strings.Join(in, ", ") + ")"
+	switch len(out) {
+	case 0:
+	case 1:
+		sig += " " + out[0]
+	default:
+		sig += " (" + strings.Join(out, ", ") + ")"
+	}
+	return strings.ReplaceAll(sig, "interface {}", "any")
+}
+
+// isStandardLibraryType reports whether a named type is declared in the
+// standard library, whose import paths have no dot in their first element.
+func isStandardLibraryType(t reflect.Type) bool {
+	pkg := t.PkgPath()
+	if pkg == "" {
+		return false
+	}
+	first, _, _ := strings.Cut(pkg, "/")
+	return !strings.Contains(first, ".")
+}
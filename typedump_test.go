@@ -0,0 +1,98 @@
+package govar
+
+import (
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+type schemaNode struct {
+	Name     string `json:"name"`
+	Parent   *schemaNode
+	Children []schemaNode
+	Created  time.Time
+	Labels   map[string]*schemaLabel
+	secret   string
+}
+
+type schemaLabel struct {
+	Value string
+}
+
+type schemaStatus int
+
+type schemaTags []string
+
+type schemaOrder struct {
+	Status   schemaStatus
+	Tags     schemaTags
+	Billing  schemaLabel
+	Shipping *schemaLabel
+}
+
+func (schemaNode) Describe(verbose bool, keys ...string) (string, error) { return "", nil }
+
+func TestSdumpType(t *testing.T) {
+	cfg := DefaultConfig
+	cfg.UseColors = false
+	out := NewDumper(cfg).SdumpType(schemaNode{Name: "not shown"})
+
+	for _, want := range []string{
+		"govar.schemaNode &1 {",
+		"⯀ Name      string                         `json:\"name\"`",
+		"⯀ Parent    *govar.schemaNode ↩︎ &1",
+		"⯀ Created   time.Time {…}",
+		"⯀ Labels    map[string]*govar.schemaLabel {",
+		"⯀ Value  string",
+		"🞏 secret    string",
+		"⦿ Describe  func(bool, ...string) (string, error)",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in output, got:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "not shown") {
+		t.Errorf("type dumps must not contain values, got:\n%s", out)
+	}
+
+	out = NewDumper(cfg).sdumpTypes(false, reflect.TypeFor[io.ReadWriter]())
+	if !strings.Contains(out, "io.ReadWriter {") || !strings.Contains(out, "⦿ Write  func([]uint8) (int, error)") {
+		t.Errorf("expected the interface method set, got:\n%s", out)
+	}
+}
+
+func TestSdumpTypeRecursive(t *testing.T) {
+	cfg := DefaultConfig
+	cfg.UseColors = false
+	cfg.EmbedTypeMethods = false
+	out := NewDumper(cfg).SdumpType(schemaOrder{})
+
+	for _, want := range []string{
+		"govar.schemaOrder {",
+		"⯀ Status    govar.schemaStatus (int)",
+		"⯀ Tags      govar.schemaTags ([]string)",
+		"⯀ Billing   govar.schemaLabel &1 {",
+		"⯀ Shipping  *govar.schemaLabel ↩︎ &1",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in output, got:\n%s", want, out)
+		}
+	}
+	if strings.Count(out, "⯀ Value") != 1 {
+		t.Errorf("expected shared types to be expanded once, got:\n%s", out)
+	}
+
+	cfg.TrackReferences = false
+	out = NewDumper(cfg).SdumpType(schemaOrder{})
+	if strings.Count(out, "⯀ Value") != 2 || strings.Contains(out, "&1") {
+		t.Errorf("expected shared types expanded at each use without TrackReferences, got:\n%s", out)
+	}
+
+	cfg.MaxDepth = 1
+	out = NewDumper(cfg).SdumpType(schemaNode{})
+	if !strings.Contains(out, "map[string]*govar.schemaLabel {…}") {
+		t.Errorf("expected MaxDepth to cut nested types short, got:\n%s", out)
+	}
+}
@@ -0,0 +1,131 @@
+// Package govar provides a powerful and highly configurable pretty-printer for Go
+// data structures. This file implements watch expressions: a value is
+// re-rendered periodically and printed again only when its rendering changes,
+// with the changed lines marked, to follow state in long-running programs.
+package govar
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"time"
+)
+
+// DefaultWatchInterval is the interval used by Watch and WatchTo when the
+// interval passed is not positive.
+const DefaultWatchInterval = time.Second
+
+// maxWatchDiffCells bounds the work of the line diff between two renderings;
+// larger renderings are compared line by line at equal positions instead.
+const maxWatchDiffCells = 1 << 22
+
+// Watch re-renders the value ptr points to every interval using the default
+// configuration (DefaultConfig, or the one set with SetDefault) and prints it
+// to stdout whenever the rendering changes, until ctx is done.
+// See Dumper.WatchTo.
+func Watch(ctx context.Context, ptr any, interval time.Duration) {
+	if !Enabled() {
+		return
+	}
+	NewDumper(defaultConfig()).WatchTo(ctx, stdoutWriter(), ptr, interval)
+}
+
+// Watch is like WatchTo, printing to stdout.
+func (d *Dumper) Watch(ctx context.Context, ptr any, interval time.Duration) {
+	d.WatchTo(ctx, stdoutWriter(), ptr, interval)
+}
+
+// WatchTo renders the value ptr points to, writes it to w, and then re-renders
+// it every interval until ctx is done, writing it again whenever the rendering
+// changes. Lines that changed since the previous rendering are marked with a
+// "~" in the gutter. An interval <= 0 selects DefaultWatchInterval. WatchTo
+// blocks, so it is usually run on its own goroutine.
+//
+// The value is read without synchronization; like any debugging aid, watching
+// a value that other goroutines modify concurrently is a data race.
+func (d *Dumper) WatchTo(ctx context.Context, w io.Writer, ptr any, interval time.Duration) {
+	d.Formatter = d.textFormatter(d.config.UseColors)
+	if interval <= 0 {
+		interval = DefaultWatchInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	var previous []string
+	for {
+		sb := &strings.Builder{}
+		d.renderAllValues(sb, ptr)
+		current := strings.Split(strings.TrimSuffix(sb.String(), "\n"), "\n")
+		if previous == nil || !slices.Equal(previous, current) {
+			out := &strings.Builder{}
+			d.renderHeader(out)
+			d.renderWatchLines(out, previous, current)
+			fmt.Fprintln(w, out.String())
+			previous = current
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// renderWatchLines writes the current rendering of a watched value. After the
+// first rendering, every line is prefixed with a gutter marking changed lines.
+func (d *Dumper) renderWatchLines(sb *strings.Builder, previous, current []string) {
+	if previous == nil {
+		sb.WriteString(strings.Join(current, "\n") + "\n")
+		return
+	}
+	changed := changedLines(previous, current)
+	for i, line := range current {
+		gutter := "  "
+		if changed[i] {
+			gutter = d.ApplyFormat(ColorGoldenrod, "~ ")
+		}
+		sb.WriteString(gutter + line + "\n")
+	}
+}
+
+// changedLines reports for each line of current whether it is new compared to
+// previous, using a longest common subsequence of the two line lists.
+func changedLines(previous, current []string) []bool {
+	changed := make([]bool, len(current))
+	if len(previous)*len(current) > maxWatchDiffCells {
+		for i := range current {
+			changed[i] = i >= len(previous) || previous[i] != current[i]
+		}
+		return changed
+	}
+
+	// lcs[i][j] is the LCS length of previous[i:] and current[j:].
+	lcs := make([][]int, len(previous)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(current)+1)
+	}
+	for i := len(previous) - 1; i >= 0; i-- {
+		for j := len(current) - 1; j >= 0; j-- {
+			if previous[i] == current[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+	i, j := 0, 0
+	for j < len(current) {
+		switch {
+		case i < len(previous) && previous[i] == current[j]:
+			i, j = i+1, j+1
+		case i < len(previous) && lcs[i+1][j] >= lcs[i][j+1]:
+			i++
+		default:
+			changed[j] = true
+			j++
+		}
+	}
+	return changed
+}
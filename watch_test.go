@@ -0,0 +1,87 @@
+package govar
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+// watchWriter records the renderings written by WatchTo and runs a step after
+// each of them. The steps run on the watching goroutine, so they can modify the
+// watched value without a data race.
+type watchWriter struct {
+	writes []string
+	steps  []func()
+}
+
+func (w *watchWriter) Write(p []byte) (int, error) {
+	w.writes = append(w.writes, string(p))
+	if len(w.steps) > 0 {
+		step := w.steps[0]
+		w.steps = w.steps[1:]
+		step()
+	}
+	return len(p), nil
+}
+
+func TestWatchTo(t *testing.T) {
+	type Job struct {
+		Name     string
+		Progress int
+		Steps    []string
+	}
+	job := Job{Name: "import", Progress: 10, Steps: []string{"fetch"}}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	cfg := DefaultConfig
+	cfg.UseColors = false
+	cfg.EmbedTypeMethods = false
+	cfg.ShowMetaInformation = false
+	w := &watchWriter{steps: []func(){
+		func() { job.Progress = 55 },
+		cancel,
+	}}
+	NewDumper(cfg).WatchTo(ctx, w, &job, time.Millisecond)
+
+	if len(w.writes) != 2 {
+		t.Fatalf("expected 2 renderings, got %d:\n%s", len(w.writes), strings.Join(w.writes, "\n"))
+	}
+	if strings.Contains(w.writes[0], "~ ") || !strings.Contains(w.writes[0], "⯀ Progress  int      => 10") {
+		t.Errorf("first rendering should be unmarked, got:\n%s", w.writes[0])
+	}
+	if !strings.Contains(w.writes[1], "~    ⯀ Progress  int      => 55\n") {
+		t.Errorf("changed line should be marked, got:\n%s", w.writes[1])
+	}
+	if !strings.Contains(w.writes[1], "\n     ⯀ Name      string   => \"import\"\n") {
+		t.Errorf("unchanged line should not be marked, got:\n%s", w.writes[1])
+	}
+}
+
+func TestWatchToInvalidInterval(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	cfg := DefaultConfig
+	cfg.UseColors = false
+	w := &watchWriter{steps: []func(){cancel}}
+	NewDumper(cfg).WatchTo(ctx, w, new(int), -time.Second)
+
+	if len(w.writes) != 1 {
+		t.Fatalf("expected 1 rendering, got %d:\n%s", len(w.writes), strings.Join(w.writes, "\n"))
+	}
+}
+
+func TestChangedLines(t *testing.T) {
+	previous := []string{"a", "b", "c", "d"}
+	current := []string{"a", "x", "c", "d", "e"}
+	want := []bool{false, true, false, false, true}
+	got := changedLines(previous, current)
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("changedLines() = %v, want %v", got, want)
+			break
+		}
+	}
+}
@@ -1,6 +1,9 @@
 // Package who provides utilities for analyzing Go packages to determine
 // which types implement specific interfaces, and which interfaces are
 // implemented by specific types within a project or across all dependencies.
+// It can also list the interfaces a set of packages declares and the types that
+// almost implement an interface, and audit released versions of modules in the
+// module cache.
 package who
 
 import (
@@ -9,9 +12,58 @@ import (
 	"slices"
 	"strings"
 
+	"github.com/janvaclavik/govar/internal/modcache"
+	"golang.org/x/mod/module"
 	"golang.org/x/tools/go/packages"
 )
 
+// Option configures how the packages of a query are loaded.
+type Option func(*options)
+
+// options holds the settings applied by Option values.
+type options struct {
+	tests bool
+	dir   string // Directory the packages are loaded from ("" for the working directory).
+}
+
+// WithTests includes _test.go files in a query, so types and interfaces
+// declared in tests (fakes, test helpers) are found too. By default test files
+// are excluded.
+func WithTests() Option {
+	return func(o *options) { o.tests = true }
+}
+
+// loadPackages loads the packages matching patterns with the given mode and
+// options. When tests are included, each package is kept only once, in its
+// variant compiled with its test files, and generated test mains are dropped.
+func loadPackages(mode packages.LoadMode, opts []Option, patterns ...string) ([]*packages.Package, error) {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+	cfg := &packages.Config{Mode: mode | packages.NeedName, Tests: o.tests, Dir: o.dir}
+	pkgs, err := packages.Load(cfg, patterns...)
+	if err != nil || !o.tests {
+		return pkgs, err
+	}
+
+	var result []*packages.Package
+	byPath := map[string]int{}
+	for _, pkg := range pkgs {
+		if pkg.Name == "main" && strings.HasSuffix(pkg.PkgPath, ".test") {
+			continue
+		}
+		i, seen := byPath[pkg.PkgPath]
+		if !seen {
+			byPath[pkg.PkgPath] = len(result)
+			result = append(result, pkg)
+		} else if len(pkg.Syntax) > len(result[i].Syntax) {
+			result[i] = pkg
+		}
+	}
+	return result, nil
+}
+
 // isConcreteNamedType checks whether the given object is a concrete (non-interface) named type.
 func isConcreteNamedType(obj types.Object) bool {
 	// Must be a type declaration
@@ -37,7 +89,7 @@ func isConcreteNamedType(obj types.Object) bool {
 //
 // Returns a sorted list of fully-qualified type names like "mypkg.MyType".
 // Returns an error if the interface cannot be resolved or packages fail to load.
-func Implements(interfaceFullName string) ([]string, error) {
+func Implements(interfaceFullName string, opts ...Option) ([]string, error) {
 	// 1. Parse "pkgpath.InterfaceName"
 	typePkgPath, typeName, err := splitTypeName(interfaceFullName)
 	if err != nil {
@@ -45,13 +97,66 @@ func Implements(interfaceFullName string) ([]string, error) {
 	}
 
 	// 2. Load all packages
-	cfg := &packages.Config{Mode: packages.LoadTypes | packages.LoadSyntax | packages.NeedDeps}
-	// pkgs, err := packages.Load(cfg, "./...")
-	pkgs, err := packages.Load(cfg, "all")
+	pkgs, err := loadPackages(packages.LoadTypes|packages.LoadSyntax|packages.NeedDeps, opts, "all")
+	if err != nil {
+		return nil, err
+	}
+
+	return findImplementers(pkgs, interfaceFullName, typePkgPath, typeName, "")
+}
+
+// ImplementsInModule is Implements for a released module version in the module
+// cache, given as "path@version" (e.g. "github.com/acme/shop@v1.2.3"), so the
+// implementers of an interface can be compared across versions without
+// checking them out. The version is downloaded if it is not cached yet.
+//
+// Only types declared in the module's own packages are returned; the interface
+// may come from the module or any package it can import.
+func ImplementsInModule(moduleVersion, interfaceFullName string, opts ...Option) ([]string, error) {
+	typePkgPath, typeName, err := splitTypeName(interfaceFullName)
+	if err != nil {
+		return nil, err
+	}
+	modPath, dir, err := moduleDir(moduleVersion)
 	if err != nil {
 		return nil, err
 	}
 
+	opts = append(slices.Clip(opts), func(o *options) { o.dir = dir })
+	pkgs, err := loadPackages(packages.LoadTypes|packages.LoadSyntax|packages.NeedDeps, opts, "./...", typePkgPath)
+	if err != nil {
+		return nil, err
+	}
+
+	return findImplementers(pkgs, interfaceFullName, typePkgPath, typeName, modPath)
+}
+
+// moduleDir returns the path of the module given as "path@version" and its
+// directory in the module cache, downloading it with "go mod download" first if
+// it is not there.
+func moduleDir(moduleVersion string) (modPath, dir string, err error) {
+	modPath, version, ok := strings.Cut(moduleVersion, "@")
+	if !ok || version == "" {
+		return "", "", fmt.Errorf("invalid module version (want path@version): %s", moduleVersion)
+	}
+	if err := module.Check(modPath, version); err != nil {
+		return "", "", err
+	}
+
+	dir, err = modcache.Dir(modPath, version)
+	if err == nil && dir == "" {
+		dir, err = modcache.Download(modPath, version)
+	}
+	if err != nil {
+		return "", "", err
+	}
+	return modPath, dir, nil
+}
+
+// findImplementers returns the sorted names of the concrete types in pkgs that
+// implement the interface typePkgPath.typeName. If modPath is not empty, only
+// types declared in packages of that module are considered.
+func findImplementers(pkgs []*packages.Package, interfaceFullName, typePkgPath, typeName, modPath string) ([]string, error) {
 	// 3. Locate the target interface object
 	var targetIface *types.Interface
 	for _, pkg := range pkgs {
@@ -76,6 +181,9 @@ func Implements(interfaceFullName string) ([]string, error) {
 	// 4. Iterate over all named types and check if they implement the interface
 	var result []string
 	for _, pkg := range pkgs {
+		if modPath != "" && pkg.PkgPath != modPath && !strings.HasPrefix(pkg.PkgPath, modPath+"/") {
+			continue
+		}
 		scope := pkg.Types.Scope()
 		for _, name := range scope.Names() {
 			obj := scope.Lookup(name)
@@ -103,23 +211,23 @@ func Implements(interfaceFullName string) ([]string, error) {
 // Interfaces finds all project-local interfaces that are implemented by the given type,
 // specified by fully-qualified name (e.g. "mypkg.MyStruct").
 // This does not include interfaces from the standard library or external modules.
-func Interfaces(typeFullName string) ([]string, error) {
-	return findInterfaces(typeFullName, false)
+func Interfaces(typeFullName string, opts ...Option) ([]string, error) {
+	return findInterfaces(typeFullName, false, opts)
 }
 
 // InterfacesExt returns interfaces implemented by the given type
 // from the standard library and external dependencies only (excluding project-local interfaces).
 // It excludes interfaces found in the current project (i.e. those returned by Interfaces()).
-func InterfacesExt(typeFullName string) ([]string, error) {
+func InterfacesExt(typeFullName string, opts ...Option) ([]string, error) {
 
 	// First, find all matched interfaces, including stdlib and external imports
-	listAll, err := findInterfaces(typeFullName, true)
+	listAll, err := findInterfaces(typeFullName, true, opts)
 	if err != nil {
 		return nil, err
 	}
 
 	// Second, codebase (project-defined) interfaces only
-	listCodebase, err := findInterfaces(typeFullName, false)
+	listCodebase, err := findInterfaces(typeFullName, false, opts)
 	if err != nil {
 		return nil, err
 	}
@@ -145,23 +253,19 @@ func InterfacesExt(typeFullName string) ([]string, error) {
 // findInterfaces returns all interfaces (optionally including external ones) that the
 // specified type implements, based on its fully-qualified name.
 // This is a shared internal helper used by Interfaces and InterfacesExt.
-func findInterfaces(typeFullName string, includeExt bool) ([]string, error) {
+func findInterfaces(typeFullName string, includeExt bool, opts []Option) ([]string, error) {
 	typePkgPath, typeName, err := splitTypeName(typeFullName)
 	// fmt.Println("(target) type name: ", typeName)
 	if err != nil {
 		return nil, err
 	}
 
-	cfg := &packages.Config{
-		Mode: packages.NeedTypes | packages.NeedTypesInfo | packages.NeedImports | packages.NeedDeps | packages.NeedSyntax,
-	}
-
 	loadPattern := "./..."
 	if includeExt {
 		loadPattern = "all"
 	}
 
-	pkgs, err := packages.Load(cfg, loadPattern)
+	pkgs, err := loadPackages(packages.NeedTypes|packages.NeedTypesInfo|packages.NeedImports|packages.NeedDeps|packages.NeedSyntax, opts, loadPattern)
 	if err != nil {
 		return nil, fmt.Errorf("failed to load packages: %w", err)
 	}
@@ -214,6 +318,146 @@ func findInterfaces(typeFullName string, includeExt bool) ([]string, error) {
 	return implementedInterfaces, nil
 }
 
+// NearMiss describes a concrete type that implements all but a few methods of
+// an interface.
+type NearMiss struct {
+	Type    string   // Fully-qualified type name, e.g. "mypkg.MyType".
+	Missing []string // Missing methods; methods with a different signature are marked "(wrong signature)".
+}
+
+// AlmostImplements finds the concrete types in the current module that have
+// at least one method of the interface identified by the fully-qualified name
+// (e.g. "io.ReadWriteCloser") but miss up to maxMissing of them, helping to
+// discover types that were probably meant to satisfy the interface.
+// Types that fully implement the interface are not listed.
+//
+// Returns the near misses sorted by the number of missing methods and name, or
+// an error if the interface cannot be resolved or packages fail to load.
+func AlmostImplements(interfaceFullName string, maxMissing int, opts ...Option) ([]NearMiss, error) {
+	typePkgPath, typeName, err := splitTypeName(interfaceFullName)
+	if err != nil {
+		return nil, err
+	}
+
+	// Both are loaded together so that types shared by the interface and the
+	// candidates are identical.
+	mode := packages.NeedName | packages.NeedTypes | packages.NeedTypesInfo | packages.NeedImports | packages.NeedDeps | packages.NeedSyntax | packages.NeedModule
+	pkgs, err := loadPackages(mode, opts, "./...", typePkgPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load packages: %w", err)
+	}
+
+	var targetIface *types.Interface
+	packages.Visit(pkgs, nil, func(pkg *packages.Package) {
+		if targetIface != nil || pkg.PkgPath != typePkgPath || pkg.Types == nil {
+			return
+		}
+		if obj := pkg.Types.Scope().Lookup(typeName); obj != nil {
+			targetIface, _ = obj.Type().Underlying().(*types.Interface)
+		}
+	})
+	if targetIface == nil {
+		return nil, fmt.Errorf("interface not found: %s", interfaceFullName)
+	}
+
+	var result []NearMiss
+	for _, pkg := range pkgs {
+		if pkg.Types == nil || pkg.Module == nil || !pkg.Module.Main {
+			continue
+		}
+		scope := pkg.Types.Scope()
+		for _, name := range scope.Names() {
+			obj := scope.Lookup(name)
+			if !isConcreteNamedType(obj) {
+				continue
+			}
+			missing := missingMethods(obj.Type(), targetIface)
+			if len(missing) == 0 || len(missing) > maxMissing || len(missing) == targetIface.NumMethods() {
+				continue
+			}
+			result = append(result, NearMiss{Type: fmt.Sprintf("%s.%s", pkg.PkgPath, obj.Name()), Missing: missing})
+		}
+	}
+
+	slices.SortFunc(result, func(a, b NearMiss) int {
+		if len(a.Missing) != len(b.Missing) {
+			return len(a.Missing) - len(b.Missing)
+		}
+		return strings.Compare(a.Type, b.Type)
+	})
+
+	return result, nil
+}
+
+// missingMethods lists the methods of iface that neither T nor *T provides with
+// an identical signature.
+func missingMethods(t types.Type, iface *types.Interface) []string {
+	methodSet := types.NewMethodSet(types.NewPointer(t))
+	var missing []string
+	for i := range iface.NumMethods() {
+		m := iface.Method(i)
+		sel := methodSet.Lookup(m.Pkg(), m.Name())
+		switch {
+		case sel == nil:
+			missing = append(missing, m.Name())
+		case !types.Identical(sel.Type(), m.Type()):
+			missing = append(missing, m.Name()+" (wrong signature)")
+		}
+	}
+	return missing
+}
+
+// InterfaceInfo describes an interface type declared in a package.
+type InterfaceInfo struct {
+	Name    string   // Fully-qualified name, e.g. "io.ReadWriter".
+	Methods []string // Complete method set (including embedded interfaces), sorted, e.g. "Read(p []byte) (n int, err error)".
+}
+
+// InterfacesOf lists every interface type declared in the packages matched by
+// pkgPattern (e.g. "./...", "io", or "github.com/acme/shop/..."), together
+// with its method set, making it easy to explore what contracts a codebase
+// defines. Unexported interfaces are included, aliases are not.
+//
+// Returns the interfaces sorted by fully-qualified name, or an error if the
+// packages fail to load.
+func InterfacesOf(pkgPattern string, opts ...Option) ([]InterfaceInfo, error) {
+	pkgs, err := loadPackages(packages.NeedName|packages.NeedTypes|packages.NeedTypesInfo|packages.NeedImports|packages.NeedDeps|packages.NeedSyntax, opts, pkgPattern)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load packages: %w", err)
+	}
+
+	var result []InterfaceInfo
+	for _, pkg := range pkgs {
+		if pkg.Types == nil {
+			continue
+		}
+		qualifier := func(p *types.Package) string { return p.Name() }
+		scope := pkg.Types.Scope()
+		for _, name := range scope.Names() {
+			typeName, ok := scope.Lookup(name).(*types.TypeName)
+			if !ok || typeName.IsAlias() {
+				continue
+			}
+			iface, ok := typeName.Type().Underlying().(*types.Interface)
+			if !ok {
+				continue
+			}
+			info := InterfaceInfo{Name: fmt.Sprintf("%s.%s", pkg.PkgPath, name), Methods: []string{}}
+			for i := range iface.NumMethods() {
+				m := iface.Method(i)
+				signature := strings.TrimPrefix(types.TypeString(m.Type(), qualifier), "func")
+				info.Methods = append(info.Methods, m.Name()+signature)
+			}
+			slices.Sort(info.Methods)
+			result = append(result, info)
+		}
+	}
+
+	slices.SortFunc(result, func(a, b InterfaceInfo) int { return strings.Compare(a.Name, b.Name) })
+
+	return result, nil
+}
+
 // splitTypeName splits a fully-qualified type string such as "mypkg.MyType"
 // into its package path ("mypkg") and type name ("MyType") components.
 //
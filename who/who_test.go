@@ -219,3 +219,260 @@ func TestIsConcreteNamedType(t *testing.T) {
 		})
 	}
 }
+
+func TestInterfacesOf(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	goMod := `module testmod
+
+go 1.20
+`
+
+	// --- iface/iface.go ---
+	ifaceCode := `package iface
+
+import "io"
+
+type Store interface {
+	Get(key string) ([]byte, error)
+	Put(key string, value []byte) error
+}
+
+type ReadStore interface {
+	io.Reader
+	Store
+}
+
+type closer interface{ Close() error }
+
+type Alias = Store
+
+type Impl struct{}
+`
+
+	mustWriteFile(t, tmpDir, "go.mod", goMod)
+	mustWriteFile(t, tmpDir, "iface/iface.go", ifaceCode)
+
+	oldWd, _ := os.Getwd()
+	defer os.Chdir(oldWd)
+	if err := os.Chdir(tmpDir); err != nil {
+		t.Fatalf("chdir failed: %v", err)
+	}
+
+	results, err := InterfacesOf("./...")
+	if err != nil {
+		t.Fatalf("InterfacesOf error: %v", err)
+	}
+	t.Logf("Found interfaces: %v", results)
+
+	want := []InterfaceInfo{
+		{Name: "testmod/iface.ReadStore", Methods: []string{
+			"Get(key string) ([]byte, error)",
+			"Put(key string, value []byte) error",
+			"Read(p []byte) (n int, err error)",
+		}},
+		{Name: "testmod/iface.Store", Methods: []string{
+			"Get(key string) ([]byte, error)",
+			"Put(key string, value []byte) error",
+		}},
+		{Name: "testmod/iface.closer", Methods: []string{"Close() error"}},
+	}
+	if !slices.EqualFunc(results, want, func(a, b InterfaceInfo) bool {
+		return a.Name == b.Name && slices.Equal(a.Methods, b.Methods)
+	}) {
+		t.Errorf("InterfacesOf() = %v, want %v", results, want)
+	}
+}
+
+func TestAlmostImplements(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	goMod := `module testmod
+
+go 1.20
+`
+
+	// --- impl/impl.go ---
+	implCode := `package impl
+
+type Full struct{}
+
+func (Full) Read(p []byte) (int, error) { return 0, nil }
+func (Full) Write(p []byte) (int, error) { return 0, nil }
+func (Full) Close() error { return nil }
+
+type NoClose struct{}
+
+func (NoClose) Read(p []byte) (int, error) { return 0, nil }
+func (*NoClose) Write(p []byte) (int, error) { return 0, nil }
+
+type BadWrite struct{}
+
+func (BadWrite) Read(p []byte) (int, error) { return 0, nil }
+func (BadWrite) Write(s string) error { return nil }
+func (BadWrite) Close() error { return nil }
+
+type OnlyRead struct{}
+
+func (OnlyRead) Read(p []byte) (int, error) { return 0, nil }
+
+type Unrelated struct{}
+`
+
+	mustWriteFile(t, tmpDir, "go.mod", goMod)
+	mustWriteFile(t, tmpDir, "impl/impl.go", implCode)
+
+	oldWd, _ := os.Getwd()
+	defer os.Chdir(oldWd)
+	if err := os.Chdir(tmpDir); err != nil {
+		t.Fatalf("chdir failed: %v", err)
+	}
+
+	results, err := AlmostImplements("io.ReadWriteCloser", 1)
+	if err != nil {
+		t.Fatalf("AlmostImplements error: %v", err)
+	}
+	t.Logf("Found near misses: %v", results)
+
+	want := []NearMiss{
+		{Type: "testmod/impl.BadWrite", Missing: []string{"Write (wrong signature)"}},
+		{Type: "testmod/impl.NoClose", Missing: []string{"Close"}},
+	}
+	if !slices.EqualFunc(results, want, func(a, b NearMiss) bool {
+		return a.Type == b.Type && slices.Equal(a.Missing, b.Missing)
+	}) {
+		t.Errorf("AlmostImplements() = %v, want %v", results, want)
+	}
+
+	if _, err := AlmostImplements("io.NoSuchInterface", 1); err == nil {
+		t.Error("expected an error for an unknown interface")
+	}
+}
+
+func TestWithTests(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	goMod := `module testmod
+
+go 1.20
+`
+
+	// --- store/store.go ---
+	storeCode := `package store
+
+type Store interface {
+	Get(key string) string
+}
+
+type MemStore struct{}
+
+func (MemStore) Get(key string) string { return "" }
+`
+
+	// --- store/store_test.go ---
+	storeTestCode := `package store
+
+type Clock interface {
+	Now() int
+}
+
+type fakeStore struct{}
+
+func (fakeStore) Get(key string) string { return "fake" }
+func (fakeStore) Now() int { return 0 }
+`
+
+	mustWriteFile(t, tmpDir, "go.mod", goMod)
+	mustWriteFile(t, tmpDir, "store/store.go", storeCode)
+	mustWriteFile(t, tmpDir, "store/store_test.go", storeTestCode)
+
+	oldWd, _ := os.Getwd()
+	defer os.Chdir(oldWd)
+	if err := os.Chdir(tmpDir); err != nil {
+		t.Fatalf("chdir failed: %v", err)
+	}
+
+	results, err := Implements("testmod/store.Store")
+	if err != nil {
+		t.Fatalf("Implements error: %v", err)
+	}
+	if want := []string{"testmod/store.MemStore"}; !slices.Equal(results, want) {
+		t.Errorf("Implements() = %v, want %v", results, want)
+	}
+
+	results, err = Implements("testmod/store.Store", WithTests())
+	if err != nil {
+		t.Fatalf("Implements(WithTests) error: %v", err)
+	}
+	if want := []string{"testmod/store.MemStore", "testmod/store.fakeStore"}; !slices.Equal(results, want) {
+		t.Errorf("Implements(WithTests) = %v, want %v", results, want)
+	}
+
+	if _, err := Interfaces("testmod/store.fakeStore"); err == nil {
+		t.Error("expected test-only type to be unknown without WithTests")
+	}
+	ifaces, err := Interfaces("testmod/store.fakeStore", WithTests())
+	if err != nil {
+		t.Fatalf("Interfaces(WithTests) error: %v", err)
+	}
+	if want := []string{"testmod/store.Clock", "testmod/store.Store"}; !slices.Equal(ifaces, want) {
+		t.Errorf("Interfaces(WithTests) = %v, want %v", ifaces, want)
+	}
+
+	contracts, err := InterfacesOf("./...", WithTests())
+	if err != nil {
+		t.Fatalf("InterfacesOf(WithTests) error: %v", err)
+	}
+	if len(contracts) != 2 || contracts[0].Name != "testmod/store.Clock" {
+		t.Errorf("InterfacesOf(WithTests) = %v, want Clock and Store once each", contracts)
+	}
+}
+
+func TestImplementsInModule(t *testing.T) {
+	cache := t.TempDir()
+	t.Setenv("GOMODCACHE", cache)
+	t.Setenv("GOFLAGS", "-mod=mod")
+
+	// The module cache escapes upper-case letters in paths as "!" + lower case.
+	modDir := filepath.Join(cache, "example.com", "!acme", "shop@v1.2.0")
+	mustWriteFile(t, modDir, "go.mod", "module example.com/Acme/shop\n\ngo 1.20\n")
+	mustWriteFile(t, modDir, "cart/cart.go", `package cart
+
+type Pricer interface {
+	Price() int
+}
+
+type Item struct{}
+
+func (Item) Price() int { return 1 }
+`)
+	mustWriteFile(t, modDir, "promo/promo.go", `package promo
+
+type Coupon struct{}
+
+func (*Coupon) Price() int { return -1 }
+
+func (*Coupon) String() string { return "coupon" }
+`)
+
+	results, err := ImplementsInModule("example.com/Acme/shop@v1.2.0", "example.com/Acme/shop/cart.Pricer")
+	if err != nil {
+		t.Fatalf("ImplementsInModule error: %v", err)
+	}
+	if want := []string{"example.com/Acme/shop/cart.Item", "example.com/Acme/shop/promo.Coupon"}; !slices.Equal(results, want) {
+		t.Errorf("ImplementsInModule() = %v, want %v", results, want)
+	}
+
+	// Implementers of external interfaces are limited to the module's types.
+	results, err = ImplementsInModule("example.com/Acme/shop@v1.2.0", "fmt.Stringer")
+	if err != nil {
+		t.Fatalf("ImplementsInModule(fmt.Stringer) error: %v", err)
+	}
+	if want := []string{"example.com/Acme/shop/promo.Coupon"}; !slices.Equal(results, want) {
+		t.Errorf("ImplementsInModule(fmt.Stringer) = %v, want %v", results, want)
+	}
+
+	if _, err := ImplementsInModule("example.com/Acme/shop", "fmt.Stringer"); err == nil {
+		t.Error("expected an error for a module without a version")
+	}
+}